@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+// cacheDirective is how a request may use the cache, from its Cache-Control header.
+type cacheDirective int
+
+const (
+	// cacheDefault reads the cache and fills it on a miss.
+	cacheDefault cacheDirective = iota
+	// cacheNoCache skips the cache read but stores the fresh result.
+	cacheNoCache
+	// cacheNoStore neither reads nor writes the cache.
+	cacheNoStore
+)
+
+// String returns the X-Cache-Behavior value for d.
+func (d cacheDirective) String() string {
+	switch d {
+	case cacheNoCache:
+		return "refresh"
+	case cacheNoStore:
+		return "bypass"
+	default:
+		return "default"
+	}
+}
+
+// parseCacheControl maps a request Cache-Control header to a directive. no-store wins
+// over no-cache; max-age=0 is treated like no-cache. Other directives are ignored.
+func parseCacheControl(header string) cacheDirective {
+	directive := cacheDefault
+	for _, part := range strings.Split(header, ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "no-store":
+			return cacheNoStore
+		case "no-cache", "max-age=0":
+			directive = cacheNoCache
+		}
+	}
+	return directive
+}
+
+// cachedETag returns the entity tag of key in the reader's cache, or "" when the entry
+// is missing, has no content hash or the cache cannot report one.
+func cachedETag(ctx context.Context, reader *cache_manager.ReadThroughCache, key string) string {
+	getter, ok := reader.Cache.(cache_manager.MetadataGetter)
+	if !ok {
+		return ""
+	}
+	meta, found, err := getter.GetWithMetadata(ctx, key, nil, reader.Options)
+	if err != nil || !found {
+		return ""
+	}
+	return meta.ETag
+}
+
+// etagMatches implements the weak comparison If-None-Match uses: any listed tag equal to
+// etag ignoring a W/ prefix, or "*".
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
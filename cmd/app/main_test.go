@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"go-cache-poc/internal/db"
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+// newTestServer serves GET /users/:id from an L1-only cache whose loader counts calls
+// and names users after the number of loads so far.
+func newTestServer(t *testing.T) (*gin.Engine, *cache_manager.MultiLevelCache, *atomic.Int64) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	bcConfig := bigcache.DefaultConfig(time.Minute)
+	bcConfig.Verbose = false
+	l1, err := cache_manager.NewBigCache(context.Background(), cache_manager.BigCacheConfig{Config: bcConfig})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	ml, err := cache_manager.NewMultiLevelCache(l1, nil, cache_manager.JSONSerializer{}, cache_manager.MultiLevelConfig{
+		Mode:          cache_manager.ModeL1Only,
+		LogSampleRate: cache_manager.Float64Ptr(0),
+		ContentHashes: true,
+	})
+	require.NoError(t, err)
+
+	var loads atomic.Int64
+	srv := &server{userReaders: map[string]*cache_manager.ReadThroughCache{
+		"both-levels": {
+			Cache: ml,
+			KeyFn: cache_manager.UserCacheKey,
+			Loader: func(context.Context, string) (any, error) {
+				n := loads.Add(1)
+				return db.User{ID: 1, Name: fmt.Sprintf("load-%d", n)}, nil
+			},
+		},
+	}}
+	router := gin.New()
+	router.GET("/users/:id", srv.handleGetUser)
+	return router, ml, &loads
+}
+
+func getUser(t *testing.T, router http.Handler, cacheControl string) (*httptest.ResponseRecorder, db.User) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	if cacheControl != "" {
+		req.Header.Set("Cache-Control", cacheControl)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body struct {
+		User       db.User `json:"user"`
+		CacheLevel string  `json:"cache_level"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, rec.Header().Get("X-Cache") == "HIT", body.CacheLevel != "miss", body.CacheLevel)
+	return rec, body.User
+}
+
+func TestGetUserDefaultReadsThroughCache(t *testing.T) {
+	router, _, loads := newTestServer(t)
+
+	rec, user := getUser(t, router, "")
+	require.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	require.Equal(t, "default", rec.Header().Get("X-Cache-Behavior"))
+	require.Equal(t, "load-1", user.Name)
+
+	rec, user = getUser(t, router, "")
+	require.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	require.Contains(t, rec.Body.String(), `"cache_level":"L1"`)
+	require.Equal(t, "load-1", user.Name)
+	require.Equal(t, int64(1), loads.Load())
+}
+
+func TestGetUserNoCacheSkipsReadButStores(t *testing.T) {
+	router, ml, loads := newTestServer(t)
+	getUser(t, router, "")
+
+	rec, user := getUser(t, router, "no-cache")
+	require.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	require.Equal(t, "refresh", rec.Header().Get("X-Cache-Behavior"))
+	require.Equal(t, "load-2", user.Name)
+	require.Equal(t, int64(2), loads.Load())
+
+	var cached db.User
+	res, err := ml.Get(context.Background(), userCacheKey(1), &cached, cache_manager.CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "load-2", cached.Name)
+}
+
+func TestGetUserNoStoreBypassesCache(t *testing.T) {
+	router, ml, loads := newTestServer(t)
+	getUser(t, router, "")
+
+	rec, user := getUser(t, router, "max-age=60, No-Store")
+	require.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	require.Equal(t, "bypass", rec.Header().Get("X-Cache-Behavior"))
+	require.Equal(t, "load-2", user.Name)
+	require.Equal(t, int64(2), loads.Load())
+
+	var cached db.User
+	_, err := ml.Get(context.Background(), userCacheKey(1), &cached, cache_manager.CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "load-1", cached.Name, "no-store must not overwrite the cached user")
+}
+
+func TestParseCacheControl(t *testing.T) {
+	tests := map[string]cacheDirective{
+		"":                    cacheDefault,
+		"max-age=60":          cacheDefault,
+		"no-cache":            cacheNoCache,
+		"max-age=0":           cacheNoCache,
+		"no-store":            cacheNoStore,
+		"no-cache, no-store":  cacheNoStore,
+		" NO-CACHE ,private ": cacheNoCache,
+	}
+	for header, want := range tests {
+		require.Equal(t, want, parseCacheControl(header), header)
+	}
+}
+
+func TestGetUserETagThenNotModified(t *testing.T) {
+	router, _, loads := newTestServer(t)
+
+	rec, _ := getUser(t, router, "")
+	etag := rec.Header().Get("ETag")
+	require.Regexp(t, `^"[0-9a-f]+"$`, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Equal(t, etag, rec.Header().Get("ETag"))
+	require.Empty(t, rec.Body.String())
+	require.Equal(t, int64(1), loads.Load())
+
+	// A stale tag gets the full response with the current tag.
+	req = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, etag, rec.Header().Get("ETag"))
+	require.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+}
+
+func TestGetUserNoStoreHasNoETag(t *testing.T) {
+	router, _, _ := newTestServer(t)
+
+	rec, _ := getUser(t, router, "no-store")
+	require.Empty(t, rec.Header().Get("ETag"))
+}
+
+func TestETagMatches(t *testing.T) {
+	require.True(t, etagMatches(`"a"`, `"a"`))
+	require.True(t, etagMatches(`W/"a"`, `"a"`))
+	require.True(t, etagMatches(`"b", "a"`, `"a"`))
+	require.True(t, etagMatches(`*`, `"a"`))
+	require.False(t, etagMatches(`"b"`, `"a"`))
+}
+
+func TestGetUserHitWritesStoredJSON(t *testing.T) {
+	router, ml, _ := newTestServer(t)
+	getUser(t, router, "")
+
+	raw, res, err := ml.GetRaw(context.Background(), userCacheKey(1), cache_manager.CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+
+	rec, _ := getUser(t, router, "")
+	require.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	require.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, `{"cache_level":"L1","cache_mode":"both-levels","from_cache":true,"user":`+string(raw)+`}`, rec.Body.String())
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+// requestIDHeader carries the request ID in both directions.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds accepted incoming IDs, so a client cannot bloat every log line.
+const maxRequestIDLen = 128
+
+// requestID keeps the caller's X-Request-ID, or assigns a random one, echoes it in the
+// response and stores it in the request context, where the caches pick it up for their
+// logs and events and warnf for the handlers' warnings.
+func requestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLen {
+			id = newRequestID()
+		}
+		c.Header(requestIDHeader, id)
+		c.Request = c.Request.WithContext(cache_manager.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// warnf logs a warning about a request, tagged with its request ID when ctx has one.
+func warnf(ctx context.Context, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if id := cache_manager.RequestIDFromContext(ctx); id != "" {
+		msg += " request_id=" + id
+	}
+	log.Print("warn: " + msg)
+}
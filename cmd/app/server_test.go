@@ -0,0 +1,448 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go-cache-poc/internal/db"
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+func TestServerStandardUserEndpoints(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	resp, body := doJSON(t, ts, http.MethodGet, "/users/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, false, body["from_cache"])
+	require.Equal(t, "Ada Lovelace", body["user"].(map[string]any)["name"])
+
+	resp, body = doJSON(t, ts, http.MethodGet, "/users/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, true, body["from_cache"])
+	require.Equal(t, "L1", body["cache_level"])
+
+	resp, _ = doJSON(t, ts, http.MethodGet, "/users/42", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	for _, path := range []string{"/users/l1-only/1", "/users/l2-only/1"} {
+		doJSON(t, ts, http.MethodGet, path, "")
+		_, body = doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, true, body["from_cache"], path)
+	}
+
+	resp, body = doJSON(t, ts, http.MethodPost, "/users/forget/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	for _, name := range []string{"both_levels", "l1_only", "l2_only"} {
+		require.Contains(t, body, name)
+	}
+	for _, path := range []string{"/users/1", "/users/l1-only/1", "/users/l2-only/1"} {
+		_, body = doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, false, body["from_cache"], path)
+	}
+}
+
+func TestServerModeSpecificEndpoints(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	for path, level := range map[string]string{
+		"/users/l1-only/2":     "L1",
+		"/users/l2-only/2":     "L2",
+		"/users/both-levels/2": "L1",
+	} {
+		_, body := doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, false, body["from_cache"], path)
+
+		_, body = doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, true, body["from_cache"], path)
+		require.Equal(t, level, body["cache_level"], path)
+	}
+}
+
+func TestServerOverrideEndpoints(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	_, body := doJSON(t, ts, http.MethodGet, "/users/override-l2/3", "")
+	require.Equal(t, false, body["from_cache"])
+	_, body = doJSON(t, ts, http.MethodGet, "/users/override-l2/3", "")
+	require.Equal(t, "L2", body["cache_level"])
+
+	// The override filled only L2, so an L1-only read of the same cache still misses.
+	_, body = doJSON(t, ts, http.MethodGet, "/users/override-l1/3", "")
+	require.Equal(t, false, body["from_cache"])
+}
+
+func TestServerInspectionEndpoints(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+	doJSON(t, ts, http.MethodGet, "/users/1", "")
+
+	_, body := doJSON(t, ts, http.MethodGet, "/cache/stats/1", "")
+	require.Equal(t, map[string]any{"cached": true}, body["both_levels"])
+	require.Equal(t, map[string]any{"cached": false}, body["l1_only"])
+
+	resp, body := doJSON(t, ts, http.MethodDelete, "/cache/clear/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, true, body["both_levels"])
+
+	_, body = doJSON(t, ts, http.MethodGet, "/cache/stats/1", "")
+	require.Equal(t, map[string]any{"cached": false}, body["both_levels"])
+
+	resp, _ = doJSON(t, ts, http.MethodGet, "/cache/warmup/status", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServerAdminEndpoints(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{Chaos: true})
+	doJSON(t, ts, http.MethodGet, "/users/1", "")
+
+	resp, body := doJSON(t, ts, http.MethodGet, "/admin/cache/entries/user:1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	resp, body = doJSON(t, ts, http.MethodPost, "/admin/chaos", `{"enabled":true,"latency":"1ms"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	require.Equal(t, "1ms", body["latency"])
+
+	// Without an admin token the event stream and key listing are not routed.
+	resp, _ = doJSON(t, ts, http.MethodGet, "/cache/keys", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServerUserServerTiming(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	resp, _ := doJSON(t, ts, http.MethodGet, "/users/3", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Regexp(t, `^cache;dur=\d+\.\d, db;dur=\d+\.\d$`, resp.Header.Get("Server-Timing"))
+
+	resp, _ = doJSON(t, ts, http.MethodGet, "/users/3", "")
+	require.Equal(t, "HIT", resp.Header.Get("X-Cache"))
+	require.Regexp(t, `^cache;dur=\d+\.\d$`, resp.Header.Get("Server-Timing"))
+}
+
+func TestServerNamespaces(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	resp, body := doJSON(t, ts, http.MethodGet, "/cache/namespaces", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, []any{"L1-only", "L2-only"}, body["namespaces"])
+}
+
+func TestServerKeyScan(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{AdminToken: "secret"})
+	doJSON(t, ts, http.MethodGet, "/users/1", "")
+	doJSON(t, ts, http.MethodGet, "/users/2", "")
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/cache/keys/scan?pattern=user:*&limit=1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page cache_manager.KeyPage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	require.Len(t, page.Keys, 1)
+	require.Equal(t, []string{cache_manager.LevelL1, cache_manager.LevelL2}, page.Keys[0].Levels)
+	require.NotEmpty(t, page.Cursor)
+
+	resp, _ = doJSON(t, ts, http.MethodGet, "/cache/keys/scan", "")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServerUserHitSkipsStore(t *testing.T) {
+	store := db.NewSeededMemoryStore()
+	ts := NewTestServer(t, TestServerOptions{Store: store})
+
+	_, body := doJSON(t, ts, http.MethodGet, "/users/2", "")
+	require.Equal(t, false, body["from_cache"])
+	require.Equal(t, 1, store.GetUserCalls())
+
+	_, body = doJSON(t, ts, http.MethodGet, "/users/2", "")
+	require.Equal(t, true, body["from_cache"])
+	require.Equal(t, 1, store.GetUserCalls())
+
+	resp, body := doJSON(t, ts, http.MethodGet, "/users/9", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, db.ErrUserNotFound.Error(), body["error"])
+}
+
+func TestServerRefreshAndSetEndpoints(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{Store: db.NewMemoryStore(db.User{ID: 1, Name: "Ada"})})
+	doJSON(t, ts, http.MethodGet, "/users/1", "")
+
+	resp, body := doJSON(t, ts, http.MethodPost, "/users/refresh/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	refreshed := body["name"].(string)
+	require.Contains(t, refreshed, "Ada (refreshed at ")
+
+	// The refresh replaced the stale cached copy, so the next read hits the new name.
+	_, body = doJSON(t, ts, http.MethodGet, "/users/1", "")
+	require.Equal(t, true, body["from_cache"])
+	require.Equal(t, refreshed, body["user"].(map[string]any)["name"])
+
+	resp, _ = doJSON(t, ts, http.MethodPost, "/users/refresh/2", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, body = doJSON(t, ts, http.MethodPost, "/users/set-l2-only/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	_, body = doJSON(t, ts, http.MethodGet, "/users/override-l2/1", "")
+	require.Equal(t, "L2", body["cache_level"])
+}
+
+func TestServerRefreshLogsCacheFailure(t *testing.T) {
+	var logs bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	ts := NewTestServer(t, TestServerOptions{Chaos: true})
+	resp, _ := doJSON(t, ts, http.MethodPost, "/admin/chaos", `{"enabled":true,"error_rate":1}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Failing to replace the stale copies is logged, not returned to the client.
+	resp, body := doJSON(t, ts, http.MethodPost, "/users/refresh/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	require.Contains(t, logs.String(), "warn: failed refreshing both-levels cache")
+	require.Contains(t, logs.String(), "warn: failed refreshing L2-only cache")
+	require.NotContains(t, logs.String(), "warn: failed refreshing L1-only cache")
+}
+
+func TestServerCacheWarningsCarryRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	ts := NewTestServer(t, TestServerOptions{Chaos: true})
+	resp, _ := doJSON(t, ts, http.MethodPost, "/admin/chaos", `{"enabled":true,"error_rate":1}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Refreshing the L2-only copy fails its Set
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/users/refresh/1", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Request-ID", "req-refresh-1")
+	resp, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "req-refresh-1", resp.Header.Get("X-Request-ID"))
+
+	var setWarning string
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if strings.Contains(line, "warn: failed refreshing L2-only cache") {
+			setWarning = line
+		}
+	}
+	require.NotEmpty(t, setWarning, logs.String())
+	require.True(t, strings.HasSuffix(setWarning, " request_id=req-refresh-1"), setWarning)
+
+	// Requests without an ID are assigned one
+	resp, _ = doJSON(t, ts, http.MethodGet, "/users/1", "")
+	require.Len(t, resp.Header.Get("X-Request-ID"), 16)
+}
+
+func TestServerRenameEndpoint(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+	_, body := doJSON(t, ts, http.MethodGet, "/users/1", "")
+	require.Equal(t, false, body["from_cache"])
+
+	resp, body := doJSON(t, ts, http.MethodGet, "/cache/rename?old=user:1&new=user:1001", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+	_, body = doJSON(t, ts, http.MethodGet, "/admin/cache/entries/user:1001", "")
+	require.Equal(t, "user:1001", body["key"])
+	resp, _ = doJSON(t, ts, http.MethodGet, "/admin/cache/entries/user:1", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = doJSON(t, ts, http.MethodGet, "/cache/rename?old=user:1&new=user:2", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = doJSON(t, ts, http.MethodGet, "/cache/rename?old=user:1", "")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServerDeleteUserServesTombstone(t *testing.T) {
+	store := db.NewSeededMemoryStore()
+	ts := NewTestServer(t, TestServerOptions{Store: store})
+	for _, path := range []string{"/users/1", "/users/l1-only/1", "/users/l2-only/1"} {
+		_, body := doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, false, body["from_cache"], path)
+	}
+	calls := store.GetUserCalls()
+
+	resp, body := doJSON(t, ts, http.MethodDelete, "/users/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	require.Equal(t, true, body["deleted"])
+
+	// Every instance answers from its tombstone without asking the store.
+	for _, path := range []string{"/users/1", "/users/l1-only/1", "/users/l2-only/1", "/users/override-l2/1"} {
+		resp, body = doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+		require.Equal(t, db.ErrUserNotFound.Error(), body["error"], path)
+	}
+	require.Equal(t, calls, store.GetUserCalls())
+
+	resp, _ = doJSON(t, ts, http.MethodDelete, "/users/1", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServerListUsersPagesInvalidatedOnMutation(t *testing.T) {
+	store := db.NewSeededMemoryStore()
+	ts := NewTestServer(t, TestServerOptions{Store: store})
+
+	pages := []string{"/users?limit=2", "/users?limit=2&offset=2"}
+	for _, path := range pages {
+		_, body := doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, false, body["from_cache"], path)
+		_, body = doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, true, body["from_cache"], path)
+	}
+	_, body := doJSON(t, ts, http.MethodGet, pages[1], "")
+	page := body["page"].(map[string]any)
+	require.Len(t, page["users"], 1)
+	require.Equal(t, float64(3), page["total"])
+
+	// Refreshing one user drops every cached page, not only the one holding it.
+	resp, _ := doJSON(t, ts, http.MethodPost, "/users/refresh/1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	for _, path := range pages {
+		_, body := doJSON(t, ts, http.MethodGet, path, "")
+		require.Equal(t, false, body["from_cache"], path)
+	}
+
+	resp, _ = doJSON(t, ts, http.MethodDelete, "/users/3", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_, body = doJSON(t, ts, http.MethodGet, pages[1], "")
+	require.Equal(t, false, body["from_cache"])
+	require.Empty(t, body["page"].(map[string]any)["users"])
+
+	resp, _ = doJSON(t, ts, http.MethodGet, "/users?limit=0", "")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServerHotKeysEndpoint(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+	resp, _ := doJSON(t, ts, http.MethodGet, "/cache/admin/hotkeys", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "only routed with hot key tracking")
+
+	ts = NewTestServer(t, TestServerOptions{TrackHotKeys: true})
+	for range 5 {
+		doJSON(t, ts, http.MethodGet, "/users/1", "")
+	}
+	doJSON(t, ts, http.MethodGet, "/users/2", "")
+
+	resp, body := doJSON(t, ts, http.MethodGet, "/cache/admin/hotkeys", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	shards := body["shards"].([]any)
+	require.NotEmpty(t, shards)
+	require.LessOrEqual(t, len(shards), 5)
+	top := shards[0].(map[string]any)
+	require.Equal(t, "both-levels:user:1", top["hot_keys"].([]any)[0])
+}
+
+func TestServerPurgeExpired(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{L1TTL: 50 * time.Millisecond})
+	doJSON(t, ts, http.MethodGet, "/users/1", "")
+	doJSON(t, ts, http.MethodGet, "/users/2", "")
+	time.Sleep(100 * time.Millisecond)
+
+	resp, body := doJSON(t, ts, http.MethodPost, "/cache/admin/purge", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, float64(2), body["purged"])
+
+	_, body = doJSON(t, ts, http.MethodPost, "/cache/admin/purge", "")
+	require.Equal(t, float64(0), body["purged"])
+}
+
+func TestServerAdminLevels(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	resp, body := doJSON(t, ts, http.MethodPost, "/admin/cache/levels", `{"level":"L2","enabled":false}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, map[string]any{"L1": true, "L2": false}, body)
+
+	resp, _ = doJSON(t, ts, http.MethodPost, "/admin/cache/levels", `{"level":"L3","enabled":false}`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	doJSON(t, ts, http.MethodPost, "/admin/cache/levels", `{"level":"L2","enabled":true}`)
+	_, body = doJSON(t, ts, http.MethodGet, "/admin/cache/levels", "")
+	require.Equal(t, map[string]any{"L1": true, "L2": true}, body)
+}
+
+func TestServerWarmRange(t *testing.T) {
+	store := db.NewMemoryStore()
+	for id := 1; id <= 10; id++ {
+		store.Seed(db.User{ID: id, Name: fmt.Sprintf("user %d", id)})
+	}
+	ts := NewTestServer(t, TestServerOptions{Store: store})
+
+	resp, _ := doJSON(t, ts, http.MethodPost, "/admin/warm?from=9&to=2", "")
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err := ts.Client().Post(ts.URL+"/admin/warm?from=2&to=9&batch=3&stream=true", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, 4, "one line per batch and the report")
+	require.Equal(t, float64(4), lines[0]["last_id"])
+	require.Equal(t, float64(6), lines[1]["loaded"])
+	report := lines[3]["report"].(map[string]any)
+	require.Equal(t, float64(8), report["loaded"])
+	require.Equal(t, false, report["canceled"])
+	require.NotContains(t, lines[3], "error")
+
+	_, body := doJSON(t, ts, http.MethodGet, "/cache/stats/9", "")
+	require.Equal(t, true, body["both_levels"].(map[string]any)["cached"])
+	_, body = doJSON(t, ts, http.MethodGet, "/cache/stats/10", "")
+	require.Equal(t, false, body["both_levels"].(map[string]any)["cached"])
+
+	resp, body = doJSON(t, ts, http.MethodPost, "/admin/warm?from=1&to=10&cache=L2-only", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, float64(10), body["report"].(map[string]any)["loaded"])
+	_, body = doJSON(t, ts, http.MethodGet, "/cache/stats/10", "")
+	require.Equal(t, true, body["l2_only"].(map[string]any)["cached"])
+}
+
+func TestServerTraceParameter(t *testing.T) {
+	ts := NewTestServer(t, TestServerOptions{})
+
+	steps := func(body map[string]any) []string {
+		var out []string
+		for _, ev := range body["trace"].([]any) {
+			ev := ev.(map[string]any)
+			out = append(out, fmt.Sprintf("%s %s %v %v", ev["op"], ev["step"], ev["level"], ev["result"]))
+		}
+		return out
+	}
+
+	_, body := doJSON(t, ts, http.MethodGet, "/users/1?trace=1", "")
+	require.Equal(t, false, body["from_cache"])
+	trace := steps(body)
+	require.Equal(t, []string{"get lookup L1 miss", "get lookup L2 miss"}, trace[:2])
+	require.Contains(t, trace, "set write L2 ok")
+
+	_, body = doJSON(t, ts, http.MethodGet, "/users/1?trace=1", "")
+	require.Equal(t, true, body["from_cache"])
+	// The user is served from L1, then its ETag is read from L1 as well
+	require.Equal(t, []string{"get lookup L1 hit", "get decode L1 <nil>", "get lookup L1 hit", "get decode L1 <nil>"}, steps(body))
+	require.Equal(t, "user:1", body["trace"].([]any)[0].(map[string]any)["key"])
+
+	_, body = doJSON(t, ts, http.MethodGet, "/users/1", "")
+	require.NotContains(t, body, "trace")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// resource is something the server closes on shutdown, after in-flight requests finish.
+type resource struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// serveUntilSignal serves handler on ln until SIGINT or SIGTERM. It then stops accepting
+// connections, waits up to shutdownTimeout for in-flight requests, and closes resources
+// in order under the same deadline. A resource that fails to close is logged and the
+// rest are still closed.
+func serveUntilSignal(handler http.Handler, ln net.Listener, shutdownTimeout time.Duration, resources []resource) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- httpServer.Serve(ln) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+	log.Printf("⏳ Shutting down, waiting up to %s for in-flight requests", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
+		log.Printf("warn: http server shutdown: %v", err)
+	}
+	for _, r := range resources {
+		if err := r.close(shutdownCtx); err != nil {
+			errs = append(errs, err)
+			log.Printf("warn: failed closing %s: %v", r.name, err)
+		}
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		errs = append(errs, err)
+	}
+	log.Println("✓ Shutdown complete")
+	return errors.Join(errs...)
+}
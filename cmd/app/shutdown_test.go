@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+func TestServeUntilSignalDrainsRequestsThenClosesResources(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bcConfig := bigcache.DefaultConfig(time.Minute)
+	bcConfig.Verbose = false
+	l1, err := cache_manager.NewBigCache(context.Background(), cache_manager.BigCacheConfig{Config: bcConfig})
+	require.NoError(t, err)
+	cache, err := cache_manager.NewMultiLevelCache(l1, nil, cache_manager.JSONSerializer{}, cache_manager.MultiLevelConfig{Mode: cache_manager.ModeL1Only})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var closed []string
+	record := func(name string, close func(context.Context) error) resource {
+		return resource{name, func(ctx context.Context) error {
+			mu.Lock()
+			closed = append(closed, name)
+			mu.Unlock()
+			return close(ctx)
+		}}
+	}
+	resources := []resource{
+		record("cache", cache.Close),
+		record("bigcache", func(context.Context) error { return l1.Close() }),
+		record("store", func(context.Context) error { return nil }),
+	}
+
+	started := make(chan struct{})
+	router := gin.New()
+	router.GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- serveUntilSignal(router, ln, 5*time.Second, resources) }()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{resp.StatusCode, string(body), err}
+	}()
+
+	<-started
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	res := <-responses
+	require.NoError(t, res.err)
+	require.Equal(t, http.StatusOK, res.status)
+	require.Equal(t, "done", res.body)
+
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	require.Equal(t, []string{"cache", "bigcache", "store"}, closed)
+
+	// The listener is closed, so new connections are refused.
+	_, err = net.Dial("tcp", ln.Addr().String())
+	require.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/allegro/bigcache/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+
+	"go-cache-poc/internal/db"
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+// TestServerOptions customizes NewTestServer. Nil caches are replaced by the same three
+// instances main builds, over one in-memory BigCache and one miniredis.
+type TestServerOptions struct {
+	CacheBothLevels *cache_manager.MultiLevelCache
+	CacheL1Only     *cache_manager.MultiLevelCache
+	CacheL2Only     *cache_manager.MultiLevelCache
+	// Store backs the user readers and the refresh and set endpoints. nil uses
+	// db.NewSeededMemoryStore.
+	Store db.UserStore
+	// Chaos wraps L2 of the default caches in a DelayedCache and exposes /admin/chaos.
+	Chaos bool
+	// AdminToken exposes /cache/events, /cache/keys and /cache/keys/scan.
+	AdminToken string
+	// TrackHotKeys enables hot key tracking on the default BigCache and exposes
+	// /cache/admin/hotkeys.
+	TrackHotKeys bool
+	L1TTL        time.Duration
+	L2TTL        time.Duration
+}
+
+// NewTestServer starts the demo server with every route registered by main, backed by
+// in-memory caches. The server is closed when the test ends.
+func NewTestServer(t *testing.T, opts TestServerOptions) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	if opts.L1TTL <= 0 {
+		opts.L1TTL = time.Minute
+	}
+	if opts.L2TTL <= 0 {
+		opts.L2TTL = 2 * time.Minute
+	}
+
+	var chaos *cache_manager.DelayedCache
+	var hotKeys *cache_manager.HotKeyDetector
+	var caches *cache_manager.CacheFactory
+	if opts.CacheBothLevels == nil || opts.CacheL1Only == nil || opts.CacheL2Only == nil {
+		both, l1Only, l2Only, factory, delayed, detector := newTestCaches(t, opts)
+		caches = factory
+		if opts.CacheBothLevels == nil {
+			opts.CacheBothLevels = both
+			chaos = delayed
+			hotKeys = detector
+		}
+		if opts.CacheL1Only == nil {
+			opts.CacheL1Only = l1Only
+		}
+		if opts.CacheL2Only == nil {
+			opts.CacheL2Only = l2Only
+		}
+	}
+
+	if opts.Store == nil {
+		opts.Store = db.NewSeededMemoryStore()
+	}
+
+	srv := &server{
+		cacheBothLevels: opts.CacheBothLevels,
+		cacheL1Only:     opts.CacheL1Only,
+		cacheL2Only:     opts.CacheL2Only,
+		caches:          caches,
+		userReaders:     newUserReaders(storeReader(opts.Store), opts.CacheBothLevels, opts.CacheL1Only, opts.CacheL2Only, opts.L1TTL, opts.L2TTL),
+		db:              opts.Store,
+		chaos:           chaos,
+		hotKeys:         hotKeys,
+		l1TTL:           opts.L1TTL,
+		l2TTL:           opts.L2TTL,
+	}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	registerRoutes(router, srv, opts.AdminToken)
+
+	ts := httptest.NewServer(router)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// newTestCaches builds the both-levels, L1-only and L2-only instances the way main does,
+// sharing one BigCache and one miniredis; the single-level ones come from the factory.
+func newTestCaches(t *testing.T, opts TestServerOptions) (both, l1Only, l2Only *cache_manager.MultiLevelCache, caches *cache_manager.CacheFactory, chaos *cache_manager.DelayedCache, hotKeys *cache_manager.HotKeyDetector) {
+	t.Helper()
+
+	bcConfig := bigcache.DefaultConfig(10 * time.Minute)
+	bcConfig.Verbose = false
+	l1, err := cache_manager.NewBigCache(context.Background(), cache_manager.BigCacheConfig{Config: bcConfig, TrackHotKeys: opts.TrackHotKeys})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	redisCache, err := cache_manager.NewRedisCache(client)
+	require.NoError(t, err)
+
+	var l2 cache_manager.RawCache = redisCache
+	if opts.Chaos {
+		chaos = cache_manager.NewDelayedCache(redisCache, cache_manager.ChaosConfig{})
+		l2 = chaos
+	}
+
+	base := cache_manager.MultiLevelConfig{
+		WarmupTTL:     opts.L1TTL,
+		L1DefaultTTL:  opts.L1TTL,
+		L2DefaultTTL:  opts.L2TTL,
+		ContentHashes: true,
+		LogSampleRate: cache_manager.Float64Ptr(0),
+	}
+	bothConfig := base
+	bothConfig.Mode = cache_manager.ModeBothLevels
+	bothConfig.InstanceName = "both-levels"
+	both, err = cache_manager.NewMultiLevelCache(l1, l2, cache_manager.JSONSerializer{}, bothConfig)
+	require.NoError(t, err)
+
+	caches = cache_manager.NewCacheFactory(l1, l2, cache_manager.JSONSerializer{}, base)
+	l1Only, err = caches.Create("L1-only", cache_manager.ModeL1Only, opts.L1TTL, opts.L2TTL)
+	require.NoError(t, err)
+	l2Only, err = caches.Create("L2-only", cache_manager.ModeL2Only, opts.L1TTL, opts.L2TTL)
+	require.NoError(t, err)
+	return both, l1Only, l2Only, caches, chaos, l1.HotKeys()
+}
+
+// doJSON sends a request to the test server and decodes a JSON response body into a map;
+// other bodies, such as gin's plain-text 404, are discarded.
+func doJSON(t *testing.T, ts *httptest.Server, method, path, body string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	require.NoError(t, err)
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var out map[string]any
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		require.NoError(t, json.Unmarshal(data, &out), string(data))
+	}
+	return resp, out
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+// traceCache records the cache steps of requests sent with ?trace=1, for withTrace to
+// add to the response.
+func traceCache() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("trace") == "1" {
+			c.Request = c.Request.WithContext(cache_manager.WithTrace(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// withTrace adds the cache steps recorded for ctx to body as "trace" when the request is
+// traced.
+func withTrace(ctx context.Context, body gin.H) gin.H {
+	if trace := cache_manager.TraceFromContext(ctx); trace != nil {
+		body["trace"] = trace
+	}
+	return body
+}
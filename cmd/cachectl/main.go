@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+const usage = `usage: cachectl [--addr URL] <command> [args]
+
+commands:
+  get <key>                    show value, levels and TTLs for key
+  set <key> <json> [--ttl D]   store a JSON value (TTL defaults to the service defaults)
+  del <key>                    delete key from all levels
+  keys [pattern]               list keys matching a glob pattern (default "*")
+  stats                        show key counts per level
+  flush --prefix P             delete every key starting with P
+
+The server address defaults to $CACHECTL_ADDR or http://localhost:8080.`
+
+// command is a parsed cachectl invocation.
+type command struct {
+	name    string
+	addr    string
+	key     string
+	value   string
+	pattern string
+	prefix  string
+	ttl     time.Duration
+}
+
+func main() {
+	cmd, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cachectl: %v\n\n%s\n", err, usage)
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), cmd, http.DefaultClient, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "cachectl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseArgs accepts flags anywhere on the command line, in --name value or --name=value form.
+func parseArgs(args []string) (command, error) {
+	cmd := command{addr: os.Getenv("CACHECTL_ADDR")}
+	if cmd.addr == "" {
+		cmd.addr = "http://localhost:8080"
+	}
+
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, val, hasVal := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !hasVal {
+			if i+1 >= len(args) {
+				return command{}, fmt.Errorf("flag --%s requires a value", name)
+			}
+			i++
+			val = args[i]
+		}
+
+		switch name {
+		case "addr":
+			cmd.addr = val
+		case "prefix":
+			cmd.prefix = val
+		case "ttl":
+			ttl, err := time.ParseDuration(val)
+			if err != nil {
+				return command{}, fmt.Errorf("invalid --ttl: %w", err)
+			}
+			cmd.ttl = ttl
+		default:
+			return command{}, fmt.Errorf("unknown flag --%s", name)
+		}
+	}
+
+	if len(positional) == 0 {
+		return command{}, errors.New("missing command")
+	}
+	cmd.name, positional = positional[0], positional[1:]
+
+	switch cmd.name {
+	case "get", "del":
+		if len(positional) != 1 {
+			return command{}, fmt.Errorf("%s requires exactly one key", cmd.name)
+		}
+		cmd.key = positional[0]
+	case "set":
+		if len(positional) != 2 {
+			return command{}, errors.New("set requires a key and a JSON value")
+		}
+		cmd.key, cmd.value = positional[0], positional[1]
+		if !json.Valid([]byte(cmd.value)) {
+			return command{}, errors.New("set value must be valid JSON")
+		}
+	case "keys":
+		if len(positional) > 1 {
+			return command{}, errors.New("keys accepts at most one pattern")
+		}
+		cmd.pattern = "*"
+		if len(positional) == 1 {
+			cmd.pattern = positional[0]
+		}
+	case "stats":
+		if len(positional) != 0 {
+			return command{}, errors.New("stats takes no arguments")
+		}
+	case "flush":
+		if len(positional) != 0 || cmd.prefix == "" {
+			return command{}, errors.New("flush requires --prefix")
+		}
+	default:
+		return command{}, fmt.Errorf("unknown command %q", cmd.name)
+	}
+
+	return cmd, nil
+}
+
+// run executes cmd against the admin API and writes human-readable output to out.
+func run(ctx context.Context, cmd command, client *http.Client, out io.Writer) error {
+	base := strings.TrimRight(cmd.addr, "/") + "/admin/cache"
+
+	switch cmd.name {
+	case "get":
+		var info cache_manager.EntryInfo
+		if err := do(ctx, client, http.MethodGet, base+"/entries/"+url.PathEscape(cmd.key), nil, &info); err != nil {
+			return err
+		}
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, info.Value, "", "  "); err != nil {
+			pretty.Write(info.Value)
+		}
+		fmt.Fprintf(out, "key:    %s\n", info.Key)
+		fmt.Fprintf(out, "levels: %s\n", strings.Join(info.Levels, ", "))
+		fmt.Fprintf(out, "l1_ttl: %s\n", formatTTL(info.L1TTL))
+		fmt.Fprintf(out, "l2_ttl: %s\n", formatTTL(info.L2TTL))
+		fmt.Fprintf(out, "value:\n%s\n", pretty.String())
+
+	case "set":
+		target := base + "/entries/" + url.PathEscape(cmd.key)
+		if cmd.ttl > 0 {
+			target += "?ttl=" + url.QueryEscape(cmd.ttl.String())
+		}
+		if err := do(ctx, client, http.MethodPut, target, strings.NewReader(cmd.value), nil); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "stored %s\n", cmd.key)
+
+	case "del":
+		if err := do(ctx, client, http.MethodDelete, base+"/entries/"+url.PathEscape(cmd.key), nil, nil); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "deleted %s\n", cmd.key)
+
+	case "keys":
+		var resp struct {
+			Keys []string `json:"keys"`
+		}
+		if err := do(ctx, client, http.MethodGet, base+"/keys?pattern="+url.QueryEscape(cmd.pattern), nil, &resp); err != nil {
+			return err
+		}
+		for _, k := range resp.Keys {
+			fmt.Fprintln(out, k)
+		}
+
+	case "stats":
+		var counts cache_manager.KeyCounts
+		if err := do(ctx, client, http.MethodGet, base+"/stats", nil, &counts); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "l1_keys: %d\nl2_keys: %d\n", counts.L1, counts.L2)
+
+	case "flush":
+		var resp struct {
+			Deleted int `json:"deleted"`
+		}
+		if err := do(ctx, client, http.MethodPost, base+"/flush?prefix="+url.QueryEscape(cmd.prefix), nil, &resp); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "deleted %d keys\n", resp.Deleted)
+
+	default:
+		return fmt.Errorf("unknown command %q", cmd.name)
+	}
+
+	return nil
+}
+
+// do sends a request and decodes a successful JSON response into dest (when non-nil).
+func do(ctx context.Context, client *http.Client, method, target string, body io.Reader, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = resp.Status
+		}
+		return fmt.Errorf("%s %s: %s", method, target, apiErr.Error)
+	}
+
+	if dest == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+func formatTTL(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "-"
+	}
+	return ttl.Round(time.Second).String()
+}
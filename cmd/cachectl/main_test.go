@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/allegro/bigcache/v3"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	cache_manager "go-cache-poc/pkg/cache-manager"
+)
+
+func TestParseArgs(t *testing.T) {
+	t.Setenv("CACHECTL_ADDR", "")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    command
+		wantErr string
+	}{
+		{
+			name: "get",
+			args: []string{"get", "user:1"},
+			want: command{name: "get", addr: "http://localhost:8080", key: "user:1"},
+		},
+		{
+			name: "set with trailing ttl flag",
+			args: []string{"set", "user:1", `{"id":1}`, "--ttl", "30s"},
+			want: command{name: "set", addr: "http://localhost:8080", key: "user:1", value: `{"id":1}`, ttl: 30 * time.Second},
+		},
+		{
+			name: "keys defaults pattern",
+			args: []string{"--addr=http://cache:9000", "keys"},
+			want: command{name: "keys", addr: "http://cache:9000", pattern: "*"},
+		},
+		{
+			name: "flush with prefix",
+			args: []string{"flush", "--prefix", "user:"},
+			want: command{name: "flush", addr: "http://localhost:8080", prefix: "user:"},
+		},
+		{name: "missing command", args: nil, wantErr: "missing command"},
+		{name: "unknown command", args: []string{"explode"}, wantErr: "unknown command"},
+		{name: "get without key", args: []string{"get"}, wantErr: "exactly one key"},
+		{name: "set invalid json", args: []string{"set", "k", "{nope"}, wantErr: "valid JSON"},
+		{name: "bad ttl", args: []string{"set", "k", "1", "--ttl", "soon"}, wantErr: "invalid --ttl"},
+		{name: "flush without prefix", args: []string{"flush"}, wantErr: "requires --prefix"},
+		{name: "flag without value", args: []string{"get", "k", "--addr"}, wantErr: "requires a value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(tt.args)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCachectlEndToEnd(t *testing.T) {
+	ctx := context.Background()
+
+	l1, err := cache_manager.NewBigCache(ctx, cache_manager.BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := cache_manager.NewRedisCache(client)
+	require.NoError(t, err)
+
+	ml, err := cache_manager.NewMultiLevelCache(l1, l2, cache_manager.JSONSerializer{}, cache_manager.MultiLevelConfig{
+		Mode:         cache_manager.ModeBothLevels,
+		L1DefaultTTL: time.Minute,
+		L2DefaultTTL: time.Minute,
+	})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.StripPrefix("/admin/cache", cache_manager.NewAdminHandler(ml)))
+	t.Cleanup(srv.Close)
+
+	exec := func(args ...string) (string, error) {
+		cmd, err := parseArgs(append([]string{"--addr", srv.URL}, args...))
+		require.NoError(t, err)
+		var out bytes.Buffer
+		err = run(ctx, cmd, srv.Client(), &out)
+		return out.String(), err
+	}
+
+	out, err := exec("set", "user:1", `{"id":1,"name":"Ada"}`, "--ttl", "30s")
+	require.NoError(t, err)
+	require.Equal(t, "stored user:1\n", out)
+
+	_, err = exec("set", "user:2", `{"id":2}`)
+	require.NoError(t, err)
+	_, err = exec("set", "session:1", `"abc"`)
+	require.NoError(t, err)
+
+	out, err = exec("get", "user:1")
+	require.NoError(t, err)
+	require.Contains(t, out, "levels: L1, L2")
+	require.Contains(t, out, "l1_ttl: 30s")
+	require.Contains(t, out, `"name": "Ada"`)
+
+	out, err = exec("keys", "user:*")
+	require.NoError(t, err)
+	require.Equal(t, "user:1\nuser:2\n", out)
+
+	out, err = exec("stats")
+	require.NoError(t, err)
+	require.Equal(t, "l1_keys: 3\nl2_keys: 3\n", out)
+
+	out, err = exec("del", "session:1")
+	require.NoError(t, err)
+	require.Equal(t, "deleted session:1\n", out)
+
+	out, err = exec("flush", "--prefix", "user:")
+	require.NoError(t, err)
+	require.Equal(t, "deleted 2 keys\n", out)
+
+	_, err = exec("get", "user:1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "key not found")
+}
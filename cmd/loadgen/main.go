@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// config holds the loadgen command-line options.
+type config struct {
+	addr        string
+	keySpace    int
+	dist        string
+	zipfS       float64
+	readRatio   float64
+	concurrency int
+	duration    time.Duration
+	seed        uint64
+}
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.addr, "addr", "http://localhost:8080", "base URL of the cache POC API")
+	flag.IntVar(&cfg.keySpace, "keys", 100, "number of distinct user ids to request (1..keys)")
+	flag.StringVar(&cfg.dist, "dist", "zipf", "key distribution: uniform or zipf")
+	flag.Float64Var(&cfg.zipfS, "zipf-s", 1.1, "zipf exponent (> 1; higher is more skewed)")
+	flag.Float64Var(&cfg.readRatio, "read-ratio", 0.95, "fraction of requests that are reads (0..1)")
+	flag.IntVar(&cfg.concurrency, "concurrency", 8, "number of concurrent workers")
+	flag.DurationVar(&cfg.duration, "duration", 10*time.Second, "how long to generate load")
+	flag.Uint64Var(&cfg.seed, "seed", uint64(time.Now().UnixNano()), "random seed")
+	flag.Parse()
+
+	if cfg.readRatio < 0 || cfg.readRatio > 1 {
+		log.Fatalf("read-ratio must be between 0 and 1, got %v", cfg.readRatio)
+	}
+	if cfg.concurrency < 1 {
+		log.Fatalf("concurrency must be at least 1, got %d", cfg.concurrency)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.duration)
+	defer cancel()
+
+	stats, elapsed, err := run(ctx, cfg, http.DefaultClient)
+	if err != nil {
+		log.Fatalf("loadgen: %v", err)
+	}
+	writeReport(os.Stdout, stats, elapsed)
+}
+
+// run drives the API until ctx is done and returns the merged statistics.
+func run(ctx context.Context, cfg config, client *http.Client) (*workerStats, time.Duration, error) {
+	base := strings.TrimRight(cfg.addr, "/")
+	gens := make([]keyGenerator, cfg.concurrency)
+	for i := range gens {
+		gen, err := newKeyGenerator(cfg.dist, cfg.keySpace, cfg.zipfS, cfg.seed+uint64(i))
+		if err != nil {
+			return nil, 0, err
+		}
+		gens[i] = gen
+	}
+
+	results := make([]*workerStats, cfg.concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < cfg.concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = worker(ctx, client, base, gens[i], cfg.readRatio, cfg.seed+uint64(i))
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	merged := newWorkerStats()
+	for _, r := range results {
+		merged.merge(r)
+	}
+	return merged, elapsed, nil
+}
+
+func worker(ctx context.Context, client *http.Client, base string, gen keyGenerator, readRatio float64, seed uint64) *workerStats {
+	stats := newWorkerStats()
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	for ctx.Err() == nil {
+		id := gen.Next()
+		method, target := http.MethodGet, fmt.Sprintf("%s/users/%d", base, id)
+		isRead := r.Float64() < readRatio
+		if !isRead {
+			method, target = http.MethodPost, fmt.Sprintf("%s/users/refresh/%d", base, id)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, nil)
+		if err != nil {
+			stats.errors["request"]++
+			continue
+		}
+
+		begin := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				// the run ended mid-request; don't count it
+				break
+			}
+			stats.errors["transport"]++
+			continue
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		stats.latencies = append(stats.latencies, time.Since(begin))
+
+		if isRead {
+			stats.reads++
+		} else {
+			stats.writes++
+		}
+		if resp.StatusCode >= 400 {
+			stats.errors[fmt.Sprintf("status %d", resp.StatusCode)]++
+			continue
+		}
+		if isRead {
+			stats.sources[cacheSource(resp.Header.Get("X-Cache"))]++
+		}
+	}
+	return stats
+}
+
+// cacheSource maps an X-Cache header to a report bucket; a miss was served by the DB.
+func cacheSource(header string) string {
+	switch strings.ToUpper(header) {
+	case "L1", "L2", "HIT":
+		return strings.ToUpper(header)
+	case "MISS":
+		return "DB"
+	default:
+		return "unknown"
+	}
+}
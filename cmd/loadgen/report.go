@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// workerStats accumulates results for a single worker; it is merged after the run.
+type workerStats struct {
+	latencies []time.Duration
+	reads     int
+	writes    int
+	sources   map[string]int // X-Cache value (L1, L2, HIT, DB) -> count
+	errors    map[string]int // "status 404", "transport" -> count
+}
+
+func newWorkerStats() *workerStats {
+	return &workerStats{sources: map[string]int{}, errors: map[string]int{}}
+}
+
+func (w *workerStats) merge(other *workerStats) {
+	w.latencies = append(w.latencies, other.latencies...)
+	w.reads += other.reads
+	w.writes += other.writes
+	for k, v := range other.sources {
+		w.sources[k] += v
+	}
+	for k, v := range other.errors {
+		w.errors[k] += v
+	}
+}
+
+// percentile returns the p-th percentile (0-100) of sorted using nearest-rank.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// writeReport prints a human-readable summary of the run.
+func writeReport(out io.Writer, stats *workerStats, elapsed time.Duration) {
+	sort.Slice(stats.latencies, func(i, j int) bool { return stats.latencies[i] < stats.latencies[j] })
+	total := len(stats.latencies)
+	rps := 0.0
+	if elapsed > 0 {
+		rps = float64(total) / elapsed.Seconds()
+	}
+
+	fmt.Fprintf(out, "duration:  %s\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(out, "requests:  %d (%.1f req/s)\n", total, rps)
+	fmt.Fprintf(out, "mix:       reads=%d writes=%d\n", stats.reads, stats.writes)
+	fmt.Fprintf(out, "latency:   p50=%s p95=%s p99=%s\n",
+		percentile(stats.latencies, 50), percentile(stats.latencies, 95), percentile(stats.latencies, 99))
+	fmt.Fprintf(out, "sources:   %s\n", formatCounts(stats.sources, stats.reads))
+
+	errTotal := 0
+	for _, v := range stats.errors {
+		errTotal += v
+	}
+	fmt.Fprintf(out, "errors:    %d", errTotal)
+	if errTotal > 0 {
+		fmt.Fprintf(out, " (%s)", formatCounts(stats.errors, 0))
+	}
+	fmt.Fprintln(out)
+}
+
+// formatCounts renders counts sorted by key; when total > 0 each entry includes its share.
+func formatCounts(counts map[string]int, total int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if total > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d (%.1f%%)", k, counts[k], 100*float64(counts[k])/float64(total)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+		}
+	}
+	return strings.Join(parts, " ")
+}
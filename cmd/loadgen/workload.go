@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// keyGenerator yields user ids in [1, keySpace].
+type keyGenerator interface {
+	Next() int
+}
+
+// newKeyGenerator builds a generator for the named distribution ("uniform" or "zipf").
+// Each worker should own its generator because the underlying sources are not goroutine-safe.
+func newKeyGenerator(dist string, keySpace int, zipfS float64, seed uint64) (keyGenerator, error) {
+	if keySpace < 1 {
+		return nil, fmt.Errorf("key space must be at least 1, got %d", keySpace)
+	}
+	r := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+
+	switch dist {
+	case "uniform":
+		return &uniformGenerator{r: r, n: keySpace}, nil
+	case "zipf":
+		if zipfS <= 1 {
+			return nil, fmt.Errorf("zipf exponent must be > 1, got %v", zipfS)
+		}
+		return &zipfGenerator{z: rand.NewZipf(r, zipfS, 1, uint64(keySpace-1))}, nil
+	default:
+		return nil, fmt.Errorf("unknown distribution %q (want uniform or zipf)", dist)
+	}
+}
+
+type uniformGenerator struct {
+	r *rand.Rand
+	n int
+}
+
+func (g *uniformGenerator) Next() int {
+	return g.r.IntN(g.n) + 1
+}
+
+// zipfGenerator favours low ids, so id 1 is the hottest key.
+type zipfGenerator struct {
+	z *rand.Zipf
+}
+
+func (g *zipfGenerator) Next() int {
+	return int(g.z.Uint64()) + 1
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniformGeneratorCoversKeySpaceEvenly(t *testing.T) {
+	t.Parallel()
+
+	gen, err := newKeyGenerator("uniform", 10, 0, 42)
+	require.NoError(t, err)
+
+	const draws = 100_000
+	counts := make(map[int]int)
+	for i := 0; i < draws; i++ {
+		id := gen.Next()
+		require.GreaterOrEqual(t, id, 1)
+		require.LessOrEqual(t, id, 10)
+		counts[id]++
+	}
+
+	require.Len(t, counts, 10)
+	for id, c := range counts {
+		require.InDelta(t, draws/10, c, draws/10*0.1, "id %d drawn %d times", id, c)
+	}
+}
+
+func TestZipfGeneratorSkewsTowardsLowIDs(t *testing.T) {
+	t.Parallel()
+
+	gen, err := newKeyGenerator("zipf", 1000, 1.2, 42)
+	require.NoError(t, err)
+
+	const draws = 100_000
+	counts := make(map[int]int)
+	hot := 0
+	for i := 0; i < draws; i++ {
+		id := gen.Next()
+		require.GreaterOrEqual(t, id, 1)
+		require.LessOrEqual(t, id, 1000)
+		counts[id]++
+		if id <= 10 {
+			hot++
+		}
+	}
+
+	require.Greater(t, counts[1], counts[2])
+	require.Greater(t, counts[2], counts[10])
+	require.Greater(t, float64(hot)/draws, 0.5, "top 1%% of keys should receive most traffic")
+}
+
+func TestKeyGeneratorRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := newKeyGenerator("uniform", 0, 0, 1)
+	require.ErrorContains(t, err, "key space")
+
+	_, err = newKeyGenerator("zipf", 10, 1.0, 1)
+	require.ErrorContains(t, err, "zipf exponent")
+
+	_, err = newKeyGenerator("pareto", 10, 1.1, 1)
+	require.ErrorContains(t, err, "unknown distribution")
+}
+
+func TestGeneratorIsDeterministicForSeed(t *testing.T) {
+	t.Parallel()
+
+	a, err := newKeyGenerator("zipf", 100, 1.1, 7)
+	require.NoError(t, err)
+	b, err := newKeyGenerator("zipf", 100, 1.1, 7)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		require.Equal(t, a.Next(), b.Next())
+	}
+}
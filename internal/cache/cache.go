@@ -17,9 +17,26 @@ const (
 	ModeL2Only
 )
 
+// CacheLevelHit names the level a Get was served from.
+type CacheLevelHit string
+
+const (
+	// CacheLevelNone reports a miss.
+	CacheLevelNone CacheLevelHit = "miss"
+	CacheLevelL1   CacheLevelHit = "L1"
+	CacheLevelL2   CacheLevelHit = "L2"
+)
+
+// CacheGetResult is the outcome of a Get. Found reports whether dest was filled; Level
+// reports which cache level filled it.
+type CacheGetResult struct {
+	Found bool
+	Level CacheLevelHit
+}
+
 // Cache represents the multi-level cache facade exposed to callers.
 type Cache interface {
-	Get(ctx context.Context, key string, dest any) (bool, error)
+	Get(ctx context.Context, key string, dest any) (CacheGetResult, error)
 	Set(ctx context.Context, key string, value any, ttlOptions SetTTLOptions) error
 	Delete(ctx context.Context, key string) error
 }
@@ -57,15 +57,15 @@ func TestIntegrationMultiLevelCacheWithRedis(t *testing.T) {
 	}))
 
 	var out user
-	found, err := ml.Get(ctx, key, &out)
+	res, err := ml.Get(ctx, key, &out)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
 	require.Equal(t, value, out)
 
 	time.Sleep(300 * time.Millisecond)
 
 	var expired user
-	found, err = ml.Get(ctx, key, &expired)
+	res, err = ml.Get(ctx, key, &expired)
 	require.NoError(t, err)
-	require.False(t, found)
+	require.False(t, res.Found)
 }
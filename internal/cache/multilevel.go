@@ -30,18 +30,22 @@ type MultiLevelConfig struct {
 	L1DefaultTTL time.Duration
 	// L2DefaultTTL is used when SetTTLOptions do not specify an L2 TTL.
 	L2DefaultTTL time.Duration
+	// PanicOnInternalError re-panics instead of returning an ErrPanic when a backend or
+	// the serializer panics inside Get, Set or Delete, e.g. during development.
+	PanicOnInternalError bool
 }
 
 // MultiLevelCache composes an L1 and L2 cache with cache-aside semantics.
 type MultiLevelCache struct {
-	l1             RawCache
-	l2             RawCache
-	serializer     Serializer
-	mode           CacheMode
-	allowOverrides bool // true only when both L1 and L2 are configured
-	warmupTTL      time.Duration
-	l1DefaultTTL   time.Duration
-	l2DefaultTTL   time.Duration
+	l1              RawCache
+	l2              RawCache
+	serializer      Serializer
+	mode            CacheMode
+	allowOverrides  bool // true only when both L1 and L2 are configured
+	warmupTTL       time.Duration
+	l1DefaultTTL    time.Duration
+	l2DefaultTTL    time.Duration
+	panicOnInternal bool
 }
 
 // NewMultiLevelCache builds a MultiLevelCache with sensible defaults.
@@ -108,49 +112,53 @@ func NewMultiLevelCache(l1 RawCache, l2 RawCache, serializer Serializer, cfg Mul
 	}
 
 	return &MultiLevelCache{
-		l1:             l1,
-		l2:             l2,
-		serializer:     serializer,
-		mode:           mode,
-		allowOverrides: allowOverrides,
-		warmupTTL:      warmTTL,
-		l1DefaultTTL:   l1TTL,
-		l2DefaultTTL:   l2TTL,
+		l1:              l1,
+		l2:              l2,
+		serializer:      serializer,
+		mode:            mode,
+		allowOverrides:  allowOverrides,
+		warmupTTL:       warmTTL,
+		l1DefaultTTL:    l1TTL,
+		l2DefaultTTL:    l2TTL,
+		panicOnInternal: cfg.PanicOnInternalError,
 	}, nil
 }
 
 // Get implements Cache.Get with cache-aside semantics and mode-aware warmup.
-func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any) (bool, error) {
+func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any) (res CacheGetResult, err error) {
+	res.Level = CacheLevelNone
+	miss := res
 	if m == nil {
-		return false, errors.New("cache not initialized")
+		return miss, errors.New("cache not initialized")
 	}
+	defer m.recoverPanic("get", key, &err)
 
 	// Check L1 first if available
 	if m.l1 != nil {
 		if data, ok, err := m.l1.Get(ctx, key); err != nil {
-			return false, err
+			return miss, err
 		} else if ok {
 			log.Printf("[cache] hit level=L1 key=%s", key)
-			return true, m.serializer.Unmarshal(data, dest)
+			return CacheGetResult{Found: true, Level: CacheLevelL1}, m.serializer.Unmarshal(data, dest)
 		}
 	}
 
 	// Check L2 if available
 	if m.l2 == nil {
-		return false, nil
+		return miss, nil
 	}
 
 	data, ok, err := m.l2.Get(ctx, key)
 	if err != nil {
-		return false, err
+		return miss, err
 	}
 	if !ok {
 		log.Printf("[cache] miss key=%s", key)
-		return false, nil
+		return miss, nil
 	}
 
 	if err := m.serializer.Unmarshal(data, dest); err != nil {
-		return false, err
+		return miss, err
 	}
 
 	log.Printf("[cache] hit level=L2 key=%s", key)
@@ -163,14 +171,15 @@ func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any) (bool,
 		_ = m.l1.Set(ctx, key, data, m.warmupTTL)
 	}
 
-	return true, nil
+	return CacheGetResult{Found: true, Level: CacheLevelL2}, nil
 }
 
 // Set serializes value and persists to cache levels based on mode and options.
-func (m *MultiLevelCache) Set(ctx context.Context, key string, value any, opts SetTTLOptions) error {
+func (m *MultiLevelCache) Set(ctx context.Context, key string, value any, opts SetTTLOptions) (err error) {
 	if m == nil {
 		return errors.New("cache not initialized")
 	}
+	defer m.recoverPanic("set", key, &err)
 
 	// Check if user is trying to override levels when not allowed
 	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
@@ -233,10 +242,11 @@ func (m *MultiLevelCache) Set(ctx context.Context, key string, value any, opts S
 }
 
 // Delete removes the key from both levels.
-func (m *MultiLevelCache) Delete(ctx context.Context, key string) error {
+func (m *MultiLevelCache) Delete(ctx context.Context, key string) (err error) {
 	if m == nil {
 		return errors.New("cache not initialized")
 	}
+	defer m.recoverPanic("delete", key, &err)
 
 	var firstErr error
 
@@ -64,9 +64,10 @@ func TestMultiLevelCacheL1MissL2HitWarmsL1(t *testing.T) {
 	require.NoError(t, err)
 
 	var result map[string]string
-	found, err := ml.Get(context.Background(), "key", &result)
+	res, err := ml.Get(context.Background(), "key", &result)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
+	require.Equal(t, CacheLevelL2, res.Level)
 	require.Equal(t, payload, result)
 
 	_, ok := l1.data["key"]
@@ -88,9 +89,10 @@ func TestMultiLevelCacheMiss(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	found, err := ml.Get(context.Background(), "missing", &struct{}{})
+	res, err := ml.Get(context.Background(), "missing", &struct{}{})
 	require.NoError(t, err)
-	require.False(t, found)
+	require.False(t, res.Found)
+	require.Equal(t, CacheLevelNone, res.Level)
 }
 
 func TestMultiLevelCacheSetWritesBoth(t *testing.T) {
@@ -238,9 +240,9 @@ func TestMultiLevelCacheModeL2Only_NoWarmup(t *testing.T) {
 	require.NoError(t, err)
 
 	var result map[string]string
-	found, err := ml.Get(context.Background(), "key", &result)
+	res, err := ml.Get(context.Background(), "key", &result)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
 	require.Equal(t, payload, result)
 
 	// L1 should NOT be warmed in ModeL2Only
@@ -340,9 +342,10 @@ func TestMultiLevelCacheGet_ModeL1Only_ChecksBothButNoWarmup(t *testing.T) {
 
 	// Get should find it in L2 (Get checks both levels regardless of mode)
 	var result map[string]string
-	found, err := ml.Get(context.Background(), "key", &result)
+	res, err := ml.Get(context.Background(), "key", &result)
 	require.NoError(t, err)
-	require.True(t, found, "should find data in L2 even in ModeL1Only")
+	require.True(t, res.Found, "should find data in L2 even in ModeL1Only")
+	require.Equal(t, CacheLevelL2, res.Level)
 
 	// But L1 should NOT be warmed because mode is ModeL1Only
 	require.NotContains(t, l1.data, "key", "L1 should not be warmed in ModeL1Only")
@@ -372,9 +375,10 @@ func TestMultiLevelCacheGet_ModeL2Only_ChecksBothButNoWarmup(t *testing.T) {
 
 	// Get should find it in L1 (Get checks both levels regardless of mode)
 	var result map[string]string
-	found, err := ml.Get(context.Background(), "key", &result)
+	res, err := ml.Get(context.Background(), "key", &result)
 	require.NoError(t, err)
-	require.True(t, found, "should find data in L1 even in ModeL2Only")
+	require.True(t, res.Found, "should find data in L1 even in ModeL2Only")
+	require.Equal(t, CacheLevelL1, res.Level)
 	require.Equal(t, payload, result)
 }
 
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// ErrPanic is returned when a backend or the serializer panicked inside Get, Set or
+// Delete. Match it with errors.As.
+type ErrPanic struct {
+	// Cause is the value passed to panic.
+	Cause any
+}
+
+func (e *ErrPanic) Error() string {
+	return fmt.Sprintf("cache panic: %v", e.Cause)
+}
+
+// Unwrap returns Cause when the panic value is an error, such as a runtime.Error.
+func (e *ErrPanic) Unwrap() error {
+	if err, ok := e.Cause.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// recoverPanic must be deferred directly. It stores a recovered panic as an ErrPanic in
+// *err, or panics again with MultiLevelConfig.PanicOnInternalError.
+func (m *MultiLevelCache) recoverPanic(op, key string, err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if m.panicOnInternal {
+		panic(v)
+	}
+	log.Printf("[cache] panic recovered op=%s key=%s: %v\n%s", op, key, v, debug.Stack())
+	*err = &ErrPanic{Cause: v}
+}
@@ -111,9 +111,9 @@ func TestModeL1Only(t *testing.T) {
 
 	// Get should only check L1
 	var result string
-	found, err := cache.Get(ctx, "key", &result)
+	res, err := cache.Get(ctx, "key", &result)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
 	require.Equal(t, "value", result)
 }
 
@@ -138,9 +138,9 @@ func TestModeL2Only(t *testing.T) {
 
 	// Get should only check L2
 	var result string
-	found, err := cache.Get(ctx, "key", &result)
+	res, err := cache.Get(ctx, "key", &result)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
 	require.Equal(t, "value", result)
 }
 
@@ -166,9 +166,9 @@ func TestModeBothLevels_NoWarmupInL2OnlyMode(t *testing.T) {
 
 	// Get should not warm L1 when mode is ModeL2Only
 	var result string
-	found, err := cache.Get(ctx, "key", &result)
+	res, err := cache.Get(ctx, "key", &result)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
 
 	// L1 should NOT be warmed because mode is ModeL2Only
 	require.NotContains(t, l1.data, "key")
@@ -195,9 +195,9 @@ func TestModeBothLevels_WarmupEnabled(t *testing.T) {
 
 	// Get should warm L1 when mode is ModeBothLevels
 	var result string
-	found, err := cache.Get(ctx, "key", &result)
+	res, err := cache.Get(ctx, "key", &result)
 	require.NoError(t, err)
-	require.True(t, found)
+	require.True(t, res.Found)
 
 	// L1 should be warmed because mode is ModeBothLevels
 	require.Contains(t, l1.data, "key")
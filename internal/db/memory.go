@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory UserStore for tests. It is safe for concurrent use.
+type MemoryStore struct {
+	mu           sync.Mutex
+	users        map[int]User
+	getUserCalls int
+}
+
+var _ UserStore = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a MemoryStore holding users.
+func NewMemoryStore(users ...User) *MemoryStore {
+	s := &MemoryStore{users: make(map[int]User, len(users))}
+	s.Seed(users...)
+	return s
+}
+
+// NewSeededMemoryStore returns a MemoryStore holding the users migration 0002 seeds.
+func NewSeededMemoryStore() *MemoryStore {
+	return NewMemoryStore(
+		User{ID: 1, Name: "Ada Lovelace"},
+		User{ID: 2, Name: "Grace Hopper"},
+		User{ID: 3, Name: "Alan Turing"},
+	)
+}
+
+// Seed adds users, replacing existing users with the same id. A zero UpdatedAt is set
+// to the current time, as the column default does.
+func (s *MemoryStore) Seed(users ...User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	for _, u := range users {
+		if u.UpdatedAt.IsZero() {
+			u.UpdatedAt = now
+		}
+		s.users[u.ID] = u
+	}
+}
+
+// GetUserCalls returns how many times GetUser has been called.
+func (s *MemoryStore) GetUserCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.getUserCalls
+}
+
+// GetUser fetches a user by id.
+func (s *MemoryStore) GetUser(_ context.Context, id int) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.getUserCalls++
+	user, ok := s.users[id]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UserUpdatedAt fetches only the user's updated_at.
+func (s *MemoryStore) UserUpdatedAt(_ context.Context, id int) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return time.Time{}, ErrUserNotFound
+	}
+	return user.UpdatedAt, nil
+}
+
+// RefreshUser appends a timestamp to the user's name and bumps UpdatedAt, like
+// Store.RefreshUser.
+func (s *MemoryStore) RefreshUser(_ context.Context, id int) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	now := time.Now()
+	user.Name = fmt.Sprintf("%s (refreshed at %s)", user.Name, now.Format(time.RFC3339))
+	// Strictly increasing, even when two refreshes land on the same clock reading
+	if next := user.UpdatedAt.Add(time.Microsecond); now.Before(next) {
+		now = next
+	}
+	user.UpdatedAt = now.UTC()
+	s.users[id] = user
+	return user, nil
+}
+
+// DeleteUser removes the user with id.
+func (s *MemoryStore) DeleteUser(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(s.users, id)
+	return nil
+}
+
+// ListUsers returns a page of users ordered by id.
+func (s *MemoryStore) ListUsers(_ context.Context, limit, offset int) ([]User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	users = users[offset:]
+	if limit >= 0 && limit < len(users) {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// GetUsersRange returns up to limit users with fromID <= id <= toID, ordered by id.
+func (s *MemoryStore) GetUsersRange(_ context.Context, fromID, toID, limit int) ([]User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []User
+	for id, u := range s.users {
+		if id >= fromID && id <= toID {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if limit >= 0 && limit < len(users) {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// ListUsersPage returns a page of users ordered by id together with the total.
+func (s *MemoryStore) ListUsersPage(ctx context.Context, limit, offset int) (UserPage, error) {
+	users, err := s.ListUsers(ctx, limit, offset)
+	if err != nil {
+		return UserPage{}, err
+	}
+	total, err := s.CountUsers(ctx)
+	if err != nil {
+		return UserPage{}, err
+	}
+	if users == nil {
+		users = []User{}
+	}
+	return UserPage{Users: users, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// CountUsers returns the number of users.
+func (s *MemoryStore) CountUsers(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.users)), nil
+}
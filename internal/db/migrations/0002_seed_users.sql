@@ -0,0 +1,5 @@
+INSERT INTO users (id, name) VALUES
+    (1, 'Ada Lovelace'),
+    (2, 'Grace Hopper'),
+    (3, 'Alan Turing')
+ON CONFLICT (id) DO NOTHING;
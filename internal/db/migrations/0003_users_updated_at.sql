@@ -0,0 +1,2 @@
+-- updated_at lets caches tell a fresh row from the copy they hold
+ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
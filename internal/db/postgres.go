@@ -14,11 +14,50 @@ import (
 type User struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	// UpdatedAt is bumped by every write; cached copies compare it to detect staleness.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CacheVersion returns UpdatedAt, so a cached User can be checked with
+// cache_manager.RefreshIfStale.
+func (u User) CacheVersion() time.Time { return u.UpdatedAt }
+
+// UserPage is one page of users ordered by id, with the total number of users.
+type UserPage struct {
+	Users  []User `json:"users"`
+	Total  int64  `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
 }
 
 // ErrUserNotFound is returned when no rows match the requested id.
 var ErrUserNotFound = errors.New("user not found")
 
+// UserStore is the user data access the application and the cache helpers depend on.
+// Store implements it against PostgreSQL and MemoryStore in memory for tests.
+type UserStore interface {
+	// GetUser fetches a user by id, returning ErrUserNotFound when it does not exist.
+	GetUser(ctx context.Context, id int) (User, error)
+	// UserUpdatedAt returns only the user's updated_at, a cheap staleness check for
+	// cached copies. It returns ErrUserNotFound when the user does not exist.
+	UserUpdatedAt(ctx context.Context, id int) (time.Time, error)
+	// RefreshUser rewrites the user's name to simulate an update and returns the new row.
+	RefreshUser(ctx context.Context, id int) (User, error)
+	// DeleteUser removes the user, returning ErrUserNotFound when it does not exist.
+	DeleteUser(ctx context.Context, id int) error
+	// ListUsers returns a page of users ordered by id.
+	ListUsers(ctx context.Context, limit, offset int) ([]User, error)
+	// GetUsersRange returns up to limit users with fromID <= id <= toID, ordered by id.
+	// Callers page through a range by passing the last id seen plus one as fromID.
+	GetUsersRange(ctx context.Context, fromID, toID, limit int) ([]User, error)
+	// ListUsersPage returns a page of users ordered by id together with the total.
+	ListUsersPage(ctx context.Context, limit, offset int) (UserPage, error)
+	// CountUsers returns the number of users.
+	CountUsers(ctx context.Context) (int64, error)
+}
+
+var _ UserStore = (*Store)(nil)
+
 // Store encapsulates database access.
 type Store struct {
 	pool *pgxpool.Pool
@@ -47,37 +86,6 @@ func (s *Store) Close() {
 	s.pool.Close()
 }
 
-// Init ensures schema exists and seeds baseline data.
-func (s *Store) Init(ctx context.Context) error {
-	if s == nil || s.pool == nil {
-		return errors.New("store not initialized")
-	}
-
-	ddl := `
-CREATE TABLE IF NOT EXISTS users (
-    id   INTEGER PRIMARY KEY,
-    name TEXT NOT NULL
-)`
-
-	if _, err := s.pool.Exec(ctx, ddl); err != nil {
-		return fmt.Errorf("create table: %w", err)
-	}
-
-	seed := []User{
-		{ID: 1, Name: "Ada Lovelace"},
-		{ID: 2, Name: "Grace Hopper"},
-		{ID: 3, Name: "Alan Turing"},
-	}
-
-	for _, u := range seed {
-		if _, err := s.pool.Exec(ctx, `INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`, u.ID, u.Name); err != nil {
-			return fmt.Errorf("seed user %d: %w", u.ID, err)
-		}
-	}
-
-	return nil
-}
-
 // GetUser fetches a user by id.
 func (s *Store) GetUser(ctx context.Context, id int) (User, error) {
 	if s == nil || s.pool == nil {
@@ -85,7 +93,7 @@ func (s *Store) GetUser(ctx context.Context, id int) (User, error) {
 	}
 
 	var user User
-	err := s.pool.QueryRow(ctx, `SELECT id, name FROM users WHERE id = $1`, id).Scan(&user.ID, &user.Name)
+	err := s.pool.QueryRow(ctx, `SELECT id, name, updated_at FROM users WHERE id = $1`, id).Scan(&user.ID, &user.Name, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
@@ -96,6 +104,23 @@ func (s *Store) GetUser(ctx context.Context, id int) (User, error) {
 	return user, nil
 }
 
+// UserUpdatedAt fetches only the user's updated_at.
+func (s *Store) UserUpdatedAt(ctx context.Context, id int) (time.Time, error) {
+	if s == nil || s.pool == nil {
+		return time.Time{}, errors.New("store not initialized")
+	}
+
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `SELECT updated_at FROM users WHERE id = $1`, id).Scan(&updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, ErrUserNotFound
+		}
+		return time.Time{}, err
+	}
+	return updatedAt, nil
+}
+
 // RefreshUser updates the user's name with a timestamp suffix to simulate refreshing data.
 func (s *Store) RefreshUser(ctx context.Context, id int) (User, error) {
 	if s == nil || s.pool == nil {
@@ -105,13 +130,14 @@ func (s *Store) RefreshUser(ctx context.Context, id int) (User, error) {
 	refreshedAt := time.Now().Format(time.RFC3339)
 	row := s.pool.QueryRow(ctx, `
         UPDATE users
-           SET name = CONCAT(name, ' (refreshed at ', $2, ')')
+           SET name = CONCAT(name, ' (refreshed at ', $2, ')'),
+               updated_at = now()
          WHERE id = $1
-         RETURNING id, name
+         RETURNING id, name, updated_at
     `, id, refreshedAt)
 
 	var user User
-	if err := row.Scan(&user.ID, &user.Name); err != nil {
+	if err := row.Scan(&user.ID, &user.Name, &user.UpdatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
 		}
@@ -120,3 +146,131 @@ func (s *Store) RefreshUser(ctx context.Context, id int) (User, error) {
 
 	return user, nil
 }
+
+// DeleteUser removes the user with id.
+func (s *Store) DeleteUser(ctx context.Context, id int) error {
+	if s == nil || s.pool == nil {
+		return errors.New("store not initialized")
+	}
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ListUsers returns a page of users ordered by id.
+func (s *Store) ListUsers(ctx context.Context, limit, offset int) ([]User, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("store not initialized")
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT id, name, updated_at FROM users ORDER BY id LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetUsersRange returns up to limit users with fromID <= id <= toID, ordered by id. It
+// seeks on the primary key, so paging through a range does not rescan skipped rows the
+// way OFFSET does.
+func (s *Store) GetUsersRange(ctx context.Context, fromID, toID, limit int) ([]User, error) {
+	if s == nil || s.pool == nil {
+		return nil, errors.New("store not initialized")
+	}
+
+	rows, err := s.pool.Query(ctx, `
+        SELECT id, name, updated_at
+          FROM users
+         WHERE id BETWEEN $1 AND $2
+         ORDER BY id
+         LIMIT $3
+    `, fromID, toID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListUsersPage returns a page of users and the total in one query; only a page past
+// the end needs a second query for the total.
+func (s *Store) ListUsersPage(ctx context.Context, limit, offset int) (UserPage, error) {
+	if s == nil || s.pool == nil {
+		return UserPage{}, errors.New("store not initialized")
+	}
+
+	rows, err := s.pool.Query(ctx, `
+        SELECT id, name, updated_at, COUNT(*) OVER ()
+          FROM users
+         ORDER BY id
+         LIMIT $1 OFFSET $2
+    `, limit, offset)
+	if err != nil {
+		return UserPage{}, err
+	}
+	defer rows.Close()
+
+	page := UserPage{Users: []User{}, Limit: limit, Offset: offset}
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.UpdatedAt, &page.Total); err != nil {
+			return UserPage{}, err
+		}
+		page.Users = append(page.Users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return UserPage{}, err
+	}
+
+	if len(page.Users) == 0 {
+		if page.Total, err = s.CountUsers(ctx); err != nil {
+			return UserPage{}, err
+		}
+	}
+	return page, nil
+}
+
+// CountUsers returns the number of users.
+func (s *Store) CountUsers(ctx context.Context) (int64, error) {
+	if s == nil || s.pool == nil {
+		return 0, errors.New("store not initialized")
+	}
+
+	var n int64
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,258 @@
+package cache_manager
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// NewAdminHandler exposes inspection and mutation endpoints for m over HTTP.
+// Paths are relative, so callers can mount it under any prefix with http.StripPrefix:
+//
+//	GET    /entries/{key}        inspect a key (value, levels, TTLs)
+//	PUT    /entries/{key}?ttl=   store the JSON request body under key
+//	DELETE /entries/{key}        delete a key from all levels
+//	GET    /keys?pattern=        list keys matching a glob pattern
+//	GET    /stats                key counts, latency, L1 evictions, backend hit/miss stats
+//	                             and per-prefix hit rates
+//	POST   /flush?prefix=        delete every key with the given prefix
+//	GET    /levels               whether each configured level is enabled
+//	POST   /levels               switch a level on or off: {"level":"L2","enabled":false}
+func NewAdminHandler(m *MultiLevelCache) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /entries/{key}", func(w http.ResponseWriter, r *http.Request) {
+		info, found, err := m.Inspect(r.Context(), r.PathValue("key"))
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !found {
+			writeAdminError(w, http.StatusNotFound, "key not found")
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, info)
+	})
+
+	mux.HandleFunc("PUT /entries/{key}", func(w http.ResponseWriter, r *http.Request) {
+		var opts CacheOptions
+		if raw := r.URL.Query().Get("ttl"); raw != "" {
+			ttl, err := time.ParseDuration(raw)
+			if err != nil {
+				writeAdminError(w, http.StatusBadRequest, "invalid ttl: "+err.Error())
+				return
+			}
+			opts.L1TTL, opts.L2TTL = ttl, ttl
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !json.Valid(body) {
+			writeAdminError(w, http.StatusBadRequest, "body must be valid JSON")
+			return
+		}
+
+		key := r.PathValue("key")
+		if err := m.Set(r.Context(), key, json.RawMessage(body), opts); err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, map[string]string{"key": key})
+	})
+
+	mux.HandleFunc("DELETE /entries/{key}", func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		if err := m.Delete(r.Context(), key); err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, map[string]string{"key": key})
+	})
+
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		keys, err := m.Keys(r.Context(), r.URL.Query().Get("pattern"))
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, map[string][]string{"keys": keys})
+	})
+
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		counts, err := m.CountKeys(r.Context())
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		stats := adminStats{KeyCounts: counts, Version: m.version, Latency: m.LatencyReport(), HitRateByPrefix: m.HitRateByPrefix()}
+		// Backend stats are best-effort: some Redis deployments restrict INFO.
+		if report, err := m.Stats(r.Context()); err != nil {
+			stats.BackendError = err.Error()
+		} else {
+			ratio := report.HitRatio()
+			stats.Backend, stats.HitRatio = &report, &ratio
+		}
+		if reporter, ok := m.l1.(EvictionReporter); ok {
+			evictions := reporter.Evictions()
+			stats.L1Evictions = &evictions
+		}
+		writeAdminJSON(w, http.StatusOK, stats)
+	})
+
+	mux.HandleFunc("POST /flush", func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("prefix")
+		if prefix == "" {
+			writeAdminError(w, http.StatusBadRequest, "prefix is required")
+			return
+		}
+		deleted, err := m.DeleteByPrefix(r.Context(), prefix)
+		if errors.Is(err, ErrFlushRateLimited) {
+			writeAdminError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, map[string]int{"deleted": deleted})
+	})
+
+	mux.HandleFunc("GET /levels", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, adminLevels(m))
+	})
+
+	mux.HandleFunc("POST /levels", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Level   string `json:"level"`
+			Enabled *bool  `json:"enabled"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+			writeAdminError(w, http.StatusBadRequest, `body must be {"level":"L1"|"L2","enabled":true|false}`)
+			return
+		}
+		if err := m.SetLevelEnabled(req.Level, *req.Enabled); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, adminLevels(m))
+	})
+
+	return mux
+}
+
+// adminLevels is the /levels response body: the enabled state of each configured level.
+func adminLevels(m *MultiLevelCache) map[string]bool {
+	out := make(map[string]bool, 2)
+	for _, lvl := range m.levels() {
+		out[lvl.name] = m.LevelEnabled(lvl.name)
+	}
+	return out
+}
+
+// l1KeysLimit caps the number of keys returned by NewL1KeysHandler.
+const l1KeysLimit = 1000
+
+// NewL1KeysHandler lists L1 keys matching ?match= (default "*") as a JSON array of at
+// most 1000 keys. Requests must carry "Authorization: Bearer <token>"; an empty token
+// rejects every request.
+func NewL1KeysHandler(m *MultiLevelCache, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validAdminToken(r, token) {
+			writeAdminError(w, http.StatusUnauthorized, "missing or invalid admin token")
+			return
+		}
+
+		match := r.URL.Query().Get("match")
+		if match == "" {
+			match = "*"
+		}
+		if _, err := path.Match(match, ""); err != nil {
+			writeAdminError(w, http.StatusBadRequest, "invalid match pattern: "+err.Error())
+			return
+		}
+
+		keys, err := m.ListL1Keys(r.Context(), match)
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if len(keys) > l1KeysLimit {
+			keys = keys[:l1KeysLimit]
+		}
+		if keys == nil {
+			keys = []string{}
+		}
+		writeAdminJSON(w, http.StatusOK, keys)
+	})
+}
+
+// NewKeysHandler pages through the keys of both levels matching ?pattern= (default "*")
+// with ScanKeys, ?limit= keys at a time (default 100, at most 1000). Pass the returned
+// cursor as ?cursor= to get the next page. Requests must carry
+// "Authorization: Bearer <token>"; an empty token rejects every request.
+func NewKeysHandler(m *MultiLevelCache, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validAdminToken(r, token) {
+			writeAdminError(w, http.StatusUnauthorized, "missing or invalid admin token")
+			return
+		}
+
+		q := r.URL.Query()
+		pattern := q.Get("pattern")
+		if _, err := path.Match(pattern, ""); err != nil {
+			writeAdminError(w, http.StatusBadRequest, "invalid pattern: "+err.Error())
+			return
+		}
+		limit := 0
+		if raw := q.Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				writeAdminError(w, http.StatusBadRequest, "limit must be a positive integer")
+				return
+			}
+			limit = n
+		}
+
+		page, err := m.ScanKeys(r.Context(), pattern, limit, q.Get("cursor"))
+		if errors.Is(err, errInvalidKeyCursor) {
+			writeAdminError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			writeAdminError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, page)
+	})
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// adminStats is the GET /stats response body.
+type adminStats struct {
+	KeyCounts
+	Version      string                  `json:"version,omitempty"`
+	Latency      map[string]LatencyStats `json:"latency"`
+	L1Evictions  *EvictionCounts         `json:"l1_evictions,omitempty"`
+	Backend      *CacheStatsReport       `json:"backend,omitempty"`
+	HitRatio     *float64                `json:"hit_ratio,omitempty"`
+	BackendError string                  `json:"backend_error,omitempty"`
+
+	// HitRateByPrefix is set when MultiLevelConfig.HitRates is enabled.
+	HitRateByPrefix map[string]float64 `json:"hit_rate_by_prefix,omitempty"`
+}
+
+func writeAdminError(w http.ResponseWriter, status int, msg string) {
+	writeAdminJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,96 @@
+package cache_manager
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AuditLogger records every cache outcome reported by MultiLevelCache, for compliance
+// and debugging. Audit is called synchronously on the cache call path.
+type AuditLogger interface {
+	Audit(ev CacheEvent)
+}
+
+// AuditEntry is one record read back from the audit stream.
+type AuditEntry struct {
+	ID string `json:"id"`
+	CacheEvent
+}
+
+// RedisAuditLogger appends cache events to a Redis stream with XADD, trimming it to
+// roughly maxLen entries (MAXLEN ~).
+type RedisAuditLogger struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisAuditLogger returns a logger writing to stream. maxLen <= 0 disables trimming.
+func NewRedisAuditLogger(client *redis.Client, stream string, maxLen int) *RedisAuditLogger {
+	return &RedisAuditLogger{client: client, stream: stream, maxLen: int64(maxLen)}
+}
+
+// Audit writes ev as a stream entry. Failures are logged and otherwise ignored so the
+// audit trail never fails a cache call.
+func (a *RedisAuditLogger) Audit(ev CacheEvent) {
+	values := []any{
+		"op", ev.Op,
+		"key", ev.Key,
+		"level", ev.Level,
+		"result", ev.Result,
+		"ts", ev.TS.Unix(),
+	}
+	if ev.Version != "" {
+		values = append(values, "version", ev.Version)
+	}
+	if ev.RequestID != "" {
+		values = append(values, "request_id", ev.RequestID)
+	}
+	args := &redis.XAddArgs{Stream: a.stream, Values: values}
+	if a.maxLen > 0 {
+		args.MaxLen = a.maxLen
+		args.Approx = true
+	}
+	if err := a.client.XAdd(context.Background(), args).Err(); err != nil {
+		slog.Warn("cache audit write failed", "stream", a.stream, "op", ev.Op, "key", ev.Key, "error", err)
+	}
+}
+
+// ReadAuditLog returns up to count entries starting at stream ID from (inclusive);
+// an empty from starts at the oldest entry.
+func (a *RedisAuditLogger) ReadAuditLog(ctx context.Context, from string, count int64) ([]AuditEntry, error) {
+	if from == "" {
+		from = "-"
+	}
+
+	msgs, err := a.client.XRangeN(ctx, a.stream, from, "+", count).Result()
+	if err != nil {
+		return nil, &CacheError{Op: "audit", Level: LevelL2, Key: a.stream, Cause: err}
+	}
+
+	entries := make([]AuditEntry, 0, len(msgs))
+	for _, msg := range msgs {
+		field := func(name string) string {
+			s, _ := msg.Values[name].(string)
+			return s
+		}
+		ts, _ := strconv.ParseInt(field("ts"), 10, 64)
+		entries = append(entries, AuditEntry{
+			ID: msg.ID,
+			CacheEvent: CacheEvent{
+				Op:        field("op"),
+				Key:       field("key"),
+				Level:     field("level"),
+				Result:    field("result"),
+				TS:        time.Unix(ts, 0),
+				Version:   field("version"),
+				RequestID: field("request_id"),
+			},
+		})
+	}
+	return entries, nil
+}
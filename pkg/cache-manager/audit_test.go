@@ -0,0 +1,58 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisAuditLoggerWritesAndReadsEntries(t *testing.T) {
+	t.Parallel()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	audit := NewRedisAuditLogger(client, "cache:audit", 1000)
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		Mode:  ModeBothLevels,
+		Audit: audit,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+	var got string
+	_, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+
+	entries, err := audit.ReadAuditLog(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	require.Equal(t, "set", entries[0].Op)
+	require.Equal(t, LevelL1, entries[0].Level)
+	require.Equal(t, "set", entries[1].Op)
+	require.Equal(t, LevelL2, entries[1].Level)
+
+	hit := entries[2]
+	require.NotEmpty(t, hit.ID)
+	require.Equal(t, "get", hit.Op)
+	require.Equal(t, "user:1", hit.Key)
+	require.Equal(t, LevelL1, hit.Level)
+	require.Equal(t, EventHit, hit.Result)
+	require.False(t, hit.TS.IsZero())
+
+	// Reading from an ID is inclusive.
+	tail, err := audit.ReadAuditLog(ctx, hit.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, tail, 1)
+	require.Equal(t, hit.ID, tail[0].ID)
+}
@@ -0,0 +1,367 @@
+package cache_manager
+
+// Benchmarks for the full MultiLevelCache Get/Set path. L1 is a real BigCache and L2 is
+// a RedisCache backed by miniredis, so no external services are needed.
+//
+// BenchmarkDefault, BenchmarkL1Only and BenchmarkL2Only run the read-heavy, write-heavy
+// and mixed workload presets against one configuration each and also report ops/s and
+// p99-ns; to check a MultiLevelConfig change, edit the configuration they pass to
+// benchmarkConfig and compare runs.
+//
+// Compare a change with benchstat:
+//
+//	go test -run '^$' -bench . -benchmem -count 10 ./pkg/cache-manager > old.txt
+//	# apply change
+//	go test -run '^$' -bench . -benchmem -count 10 ./pkg/cache-manager > new.txt
+//	benchstat old.txt new.txt
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/allegro/bigcache/v3"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+)
+
+type benchPayload struct {
+	ID   int    `json:"id" msgpack:"id"`
+	Data string `json:"data" msgpack:"data"`
+}
+
+var benchSizes = []struct {
+	name string
+	size int
+}{
+	{"100B", 100},
+	{"10KB", 10 << 10},
+	{"1MB", 1 << 20},
+}
+
+var benchSerializers = []struct {
+	name       string
+	serializer Serializer
+}{
+	{"json", JSONSerializer{}},
+	{"msgpack", MsgpackSerializer{}},
+}
+
+type benchEnv struct {
+	both   *MultiLevelCache
+	l2Only *MultiLevelCache
+	l1     *BigCache
+}
+
+// newBenchEnv builds both-levels and L2-only caches sharing one miniredis instance.
+func newBenchEnv(b *testing.B, serializer Serializer) *benchEnv {
+	b.Helper()
+
+	l1, l2 := newBenchLevels(b)
+
+	// Disable the per-operation debug logs so they do not dominate the measurements.
+	cfg := MultiLevelConfig{WarmupTTL: time.Hour, L1DefaultTTL: time.Hour, L2DefaultTTL: time.Hour, LogSampleRate: Float64Ptr(0)}
+	cfg.Mode = ModeBothLevels
+	both, err := NewMultiLevelCache(l1, l2, serializer, cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cfg.Mode = ModeL2Only
+	l2Only, err := NewMultiLevelCache(nil, l2, serializer, cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return &benchEnv{both: both, l2Only: l2Only, l1: l1}
+}
+
+// newBenchLevels returns a BigCache and a RedisCache backed by miniredis, both closed
+// when the benchmark ends.
+func newBenchLevels(b *testing.B) (*BigCache, *RedisCache) {
+	b.Helper()
+
+	bcConfig := bigcache.DefaultConfig(time.Hour)
+	bcConfig.Verbose = false
+	l1, err := NewBigCache(context.Background(), BigCacheConfig{Config: bcConfig})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = l1.Close() })
+
+	mr := miniredis.RunT(b)
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+		// miniredis does not support CLIENT MAINT_NOTIFICATIONS; disabling it keeps
+		// the fallback warning from interleaving with benchmark output.
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	b.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return l1, l2
+}
+
+func newBenchPayload(size int) benchPayload {
+	return benchPayload{ID: 42, Data: strings.Repeat("x", size)}
+}
+
+// forEachVariant runs fn for every payload size and serializer combination.
+func forEachVariant(b *testing.B, fn func(b *testing.B, serializer Serializer, payload benchPayload)) {
+	for _, sz := range benchSizes {
+		for _, ser := range benchSerializers {
+			b.Run(fmt.Sprintf("size=%s/serializer=%s", sz.name, ser.name), func(b *testing.B) {
+				fn(b, ser.serializer, newBenchPayload(sz.size))
+			})
+		}
+	}
+}
+
+func BenchmarkGetL1Hit(b *testing.B) {
+	forEachVariant(b, func(b *testing.B, serializer Serializer, payload benchPayload) {
+		env := newBenchEnv(b, serializer)
+		ctx := context.Background()
+		if err := env.both.Set(ctx, "key", payload, CacheOptions{}); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var out benchPayload
+			if res, err := env.both.Get(ctx, "key", &out, CacheOptions{}); err != nil || !res.Found {
+				b.Fatalf("found=%v err=%v", res.Found, err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetL2HitWithWarmup(b *testing.B) {
+	forEachVariant(b, func(b *testing.B, serializer Serializer, payload benchPayload) {
+		env := newBenchEnv(b, serializer)
+		ctx := context.Background()
+		if err := env.l2Only.Set(ctx, "key", payload, CacheOptions{}); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// Evict the warmed L1 entry so every iteration is an L1 miss / L2 hit.
+			b.StopTimer()
+			_ = env.l1.Delete(ctx, "key")
+			b.StartTimer()
+
+			var out benchPayload
+			if res, err := env.both.Get(ctx, "key", &out, CacheOptions{}); err != nil || !res.Found {
+				b.Fatalf("found=%v err=%v", res.Found, err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetL2HitWithoutWarmup(b *testing.B) {
+	forEachVariant(b, func(b *testing.B, serializer Serializer, payload benchPayload) {
+		env := newBenchEnv(b, serializer)
+		ctx := context.Background()
+		if err := env.l2Only.Set(ctx, "key", payload, CacheOptions{}); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var out benchPayload
+			if res, err := env.l2Only.Get(ctx, "key", &out, CacheOptions{}); err != nil || !res.Found {
+				b.Fatalf("found=%v err=%v", res.Found, err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetMiss(b *testing.B) {
+	env := newBenchEnv(b, JSONSerializer{})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var out benchPayload
+		if res, err := env.both.Get(ctx, "missing", &out, CacheOptions{}); err != nil || res.Found {
+			b.Fatalf("found=%v err=%v", res.Found, err)
+		}
+	}
+}
+
+func BenchmarkSetBothLevels(b *testing.B) {
+	forEachVariant(b, func(b *testing.B, serializer Serializer, payload benchPayload) {
+		env := newBenchEnv(b, serializer)
+		ctx := context.Background()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := env.both.Set(ctx, "key", payload, CacheOptions{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkLatencyTrackerRecord measures the per-operation cost the tracker adds to
+// Get/Set/Delete; it should stay well under 200ns.
+func BenchmarkLatencyTrackerRecord(b *testing.B) {
+	tracker := NewLatencyTracker(0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			tracker.Record("get", time.Microsecond)
+		}
+	})
+}
+
+// WorkloadSpec describes a synthetic traffic mix for benchmarkConfig. The ratios are
+// relative weights of Get, Set and Delete calls.
+type WorkloadSpec struct {
+	ReadRatio   float64
+	WriteRatio  float64
+	DeleteRatio float64
+	// KeySpace is the number of distinct keys the operations are spread over; every key
+	// is written once before timing starts.
+	KeySpace int
+	// ValueSize is the payload size in bytes.
+	ValueSize int
+	// Concurrency is the number of goroutines issuing operations.
+	Concurrency int
+}
+
+var (
+	WorkloadReadHeavy  = WorkloadSpec{ReadRatio: 0.9, WriteRatio: 0.09, DeleteRatio: 0.01, KeySpace: 1000, ValueSize: 1 << 10, Concurrency: 8}
+	WorkloadWriteHeavy = WorkloadSpec{ReadRatio: 0.2, WriteRatio: 0.75, DeleteRatio: 0.05, KeySpace: 1000, ValueSize: 1 << 10, Concurrency: 8}
+	WorkloadMixed      = WorkloadSpec{ReadRatio: 0.6, WriteRatio: 0.3, DeleteRatio: 0.1, KeySpace: 1000, ValueSize: 1 << 10, Concurrency: 8}
+)
+
+var benchWorkloads = []struct {
+	name     string
+	workload WorkloadSpec
+}{
+	{"read-heavy", WorkloadReadHeavy},
+	{"write-heavy", WorkloadWriteHeavy},
+	{"mixed", WorkloadMixed},
+}
+
+// benchmarkConfig runs workload against a cache built from cfg, with a BigCache L1 and a
+// miniredis L2 for the levels cfg.Mode uses, splitting b.N operations across the
+// workload's goroutines. Besides ns/op it reports throughput as ops/s and the 99th
+// percentile latency of a single operation as p99-ns, so benchstat flags regressions in
+// either.
+func benchmarkConfig(b *testing.B, cfg MultiLevelConfig, workload WorkloadSpec) {
+	b.Helper()
+
+	l1, l2 := newBenchLevels(b)
+	var rawL1, rawL2 RawCache = l1, l2
+	switch cfg.Mode {
+	case ModeL1Only:
+		rawL2 = nil
+	case ModeL2Only:
+		rawL1 = nil
+	}
+	if cfg.LogSampleRate == nil {
+		cfg.LogSampleRate = Float64Ptr(0)
+	}
+	ml, err := NewMultiLevelCache(rawL1, rawL2, JSONSerializer{}, cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ctx := context.Background()
+	payload := newBenchPayload(workload.ValueSize)
+	keys := make([]string, workload.KeySpace)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("bench:%d", i)
+		if err := ml.Set(ctx, keys[i], payload, CacheOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	total := workload.ReadRatio + workload.WriteRatio + workload.DeleteRatio
+	readCut, writeCut := workload.ReadRatio/total, (workload.ReadRatio+workload.WriteRatio)/total
+	workers := max(workload.Concurrency, 1)
+	latencies := make([][]time.Duration, workers)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := range workers {
+		n := b.N / workers
+		if w < b.N%workers {
+			n++
+		}
+		latencies[w] = make([]time.Duration, 0, n)
+		wg.Add(1)
+		go func(w, n int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(w), 0))
+			for range n {
+				key := keys[rng.IntN(len(keys))]
+				op := rng.Float64()
+				began := time.Now()
+				var err error
+				switch {
+				case op < readCut:
+					var out benchPayload
+					_, err = ml.Get(ctx, key, &out, CacheOptions{})
+				case op < writeCut:
+					err = ml.Set(ctx, key, payload, CacheOptions{})
+				default:
+					err = ml.Delete(ctx, key)
+				}
+				latencies[w] = append(latencies[w], time.Since(began))
+				if err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		}(w, n)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	b.StopTimer()
+
+	all := slices.Concat(latencies...)
+	if len(all) == 0 {
+		return
+	}
+	slices.Sort(all)
+	b.ReportMetric(float64(len(all))/elapsed.Seconds(), "ops/s")
+	b.ReportMetric(float64(all[(len(all)-1)*99/100].Nanoseconds()), "p99-ns")
+}
+
+// forEachWorkload runs benchmarkConfig with cfg for every workload preset.
+func forEachWorkload(b *testing.B, cfg MultiLevelConfig) {
+	for _, w := range benchWorkloads {
+		b.Run("workload="+w.name, func(b *testing.B) {
+			benchmarkConfig(b, cfg, w.workload)
+		})
+	}
+}
+
+func BenchmarkDefault(b *testing.B) {
+	forEachWorkload(b, MultiLevelConfig{Mode: ModeBothLevels, WarmupTTL: time.Hour, L1DefaultTTL: time.Hour, L2DefaultTTL: time.Hour})
+}
+
+func BenchmarkL1Only(b *testing.B) {
+	forEachWorkload(b, MultiLevelConfig{Mode: ModeL1Only, L1DefaultTTL: time.Hour})
+}
+
+func BenchmarkL2Only(b *testing.B) {
+	forEachWorkload(b, MultiLevelConfig{Mode: ModeL2Only, L2DefaultTTL: time.Hour})
+}
@@ -0,0 +1,34 @@
+package cache_manager
+
+import "context"
+
+// Bypass selects which cache accesses a context skips, see WithBypass.
+type Bypass uint8
+
+const (
+	// BypassRead makes Get skip every cache level. With a Loader configured the value is
+	// loaded and, unless BypassWrite is also set, written back to the cache.
+	BypassRead Bypass = 1 << iota
+	// BypassWrite makes Set and SetIfChanged skip their writes and Get skip L1 warmup and
+	// caching loaded values.
+	BypassWrite
+)
+
+type bypassKey struct{}
+
+// WithBypass returns a context that makes MultiLevelCache calls skip cache reads and/or
+// writes, e.g. for a reconciliation job that must read the source of truth but should
+// still refresh the cache (BypassRead). The flag takes precedence over CacheOptions and
+// the cache mode, and only affects calls made with the returned context.
+func WithBypass(ctx context.Context, b Bypass) context.Context {
+	return context.WithValue(ctx, bypassKey{}, bypassFrom(ctx)|b)
+}
+
+// bypassFrom returns the bypass flags carried by ctx, including CacheHints.Bypass.
+func bypassFrom(ctx context.Context) Bypass {
+	b, _ := ctx.Value(bypassKey{}).(Bypass)
+	if hints, ok := CacheHintsFromContext(ctx); ok && hints.Bypass {
+		b |= BypassRead | BypassWrite
+	}
+	return b
+}
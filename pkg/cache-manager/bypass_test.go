@@ -0,0 +1,97 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBypassReadForcesLoaderAndStillWrites(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+		calls++
+		return loadedUser{ID: 1, Name: "fresh"}, 0, nil
+	})})
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1, Name: "stale"}, CacheOptions{}))
+
+	var got loadedUser
+	res, err := ml.Get(WithBypass(ctx, BypassRead), "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "fresh", got.Name)
+	require.Equal(t, 1, calls)
+
+	// The flag stays with the bypassed context: a plain Get is served from the refreshed cache.
+	res, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "fresh", got.Name)
+	require.Equal(t, 1, calls)
+	require.True(t, l1.has("user:1"))
+	require.True(t, l2.has("user:1"))
+}
+
+func TestBypassReadWriteLoadsWithoutCaching(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+		return loadedUser{ID: 2, Name: "Ada"}, 0, nil
+	})})
+
+	var got loadedUser
+	res, err := ml.Get(WithBypass(context.Background(), BypassRead|BypassWrite), "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "Ada", got.Name)
+	require.False(t, l1.has("user:2"))
+	require.False(t, l2.has("user:2"))
+}
+
+func TestBypassTakesPrecedenceOverOptions(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+
+	// Explicit targets would read L1; the context says not to read at all.
+	var got string
+	res, err := ml.Get(WithBypass(ctx, BypassRead), "k", &got, CacheOptions{TargetL1: BoolPtr(true), TargetL2: BoolPtr(false)})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+
+	writeCtx := WithBypass(ctx, BypassWrite)
+	require.NoError(t, ml.Set(writeCtx, "w", "v", CacheOptions{TargetL1: BoolPtr(true), TargetL2: BoolPtr(true)}))
+	require.False(t, l1.has("w"))
+	require.False(t, l2.has("w"))
+
+	// BypassWrite still reads, but an L2 hit does not warm L1.
+	require.NoError(t, l1.Delete(ctx, "k"))
+	res, err = ml.Get(writeCtx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.False(t, l1.has("k"))
+}
+
+func TestBypassDoesNotLeakAcrossContexts(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	base := context.Background()
+	_ = WithBypass(base, BypassRead|BypassWrite)
+
+	require.NoError(t, ml.Set(base, "k", "v", CacheOptions{}))
+	require.True(t, l1.has("k"))
+	var got string
+	res, err := ml.Get(base, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+
+	require.Equal(t, BypassRead|BypassWrite, bypassFrom(WithBypass(WithBypass(base, BypassRead), BypassWrite)))
+	require.Zero(t, bypassFrom(base))
+}
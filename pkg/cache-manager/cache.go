@@ -17,9 +17,49 @@ const (
 	ModeL2Only
 )
 
+// ModeValidation sets how NewMultiLevelCache treats a level that is configured but not
+// used by the mode, e.g. an L2 passed with ModeL1Only.
+type ModeValidation int
+
+const (
+	// ModeValidationWarn logs a warning and ignores the extra level.
+	ModeValidationWarn ModeValidation = iota
+	// ModeValidationSilent ignores the extra level without logging, for setups that
+	// share backends across instances of different modes on purpose.
+	ModeValidationSilent
+	// ModeValidationError fails construction with an ErrModeMismatch CacheError whose
+	// Level names the extra level.
+	ModeValidationError
+)
+
+// CacheLevelHit names the level a Get was served from. Its values are the level names
+// used in errors and events, so they can be reported as-is (e.g. in JSON responses).
+type CacheLevelHit string
+
+const (
+	// CacheLevelNone reports a miss, including a miss filled by a loader.
+	CacheLevelNone CacheLevelHit = "miss"
+	CacheLevelL1   CacheLevelHit = LevelL1
+	CacheLevelL2   CacheLevelHit = LevelL2
+)
+
+// CacheGetResult is the outcome of a Get. Found reports whether dest was filled; Level
+// reports which cache level filled it. Stored empty values (an empty string, object or
+// slice, a nil pointer, or a zero-length serialized payload) are hits, so an empty value
+// can mean "known absent" while a miss means nothing is cached.
+type CacheGetResult struct {
+	Found bool
+	Level CacheLevelHit
+	// Stale is set when an expired L1 copy was served, see ServeStaleOnError.
+	Stale bool
+	// Timing splits the call between the cache and the loader; only ReadThroughCache.Get
+	// sets it.
+	Timing ReadTiming
+}
+
 // Cache represents the multi-level cache facade exposed to callers.
 type Cache interface {
-	Get(ctx context.Context, key string, dest any, opts CacheOptions) (bool, error)
+	Get(ctx context.Context, key string, dest any, opts CacheOptions) (CacheGetResult, error)
 	Set(ctx context.Context, key string, value any, opts CacheOptions) error
 	Delete(ctx context.Context, key string) error
 }
@@ -34,6 +74,28 @@ type CacheOptions struct {
 	// TTL options (only used by Set, ignored by Get)
 	L1TTL time.Duration // TTL for L1 (0 = use default)
 	L2TTL time.Duration // TTL for L2 (0 = use default)
+
+	// PreserveTTL overwrites the value but keeps the remaining TTL of an existing entry
+	// (Redis SET KEEPTTL; the L1 expiry is carried over). It wins over L1TTL/L2TTL, which
+	// then only apply when the key is absent. Levels without KeepTTLSetter ignore it.
+	PreserveTTL bool
+
+	// Priority is stored with L1 entries (only used by Set). Entries with Priority > 0
+	// are skipped by BigCacheJanitor and PurgeExpired, so they stay in memory past their
+	// TTL until deleted or evicted by BigCache itself; reads still miss once the TTL has
+	// passed. Default 0.
+	Priority int8
+
+	// L1MaxValueBytes overrides MultiLevelConfig.L1MaxValueBytes for this call
+	// (0 = use config, negative = no limit).
+	L1MaxValueBytes int
+
+	// RefreshTTL makes SetIfChanged extend the TTLs of an unchanged value instead of
+	// leaving them as they are (only used by SetIfChanged).
+	RefreshTTL bool
+
+	// TouchBothLevels makes Touch update L2 as well as L1 (only used by Touch).
+	TouchBothLevels bool
 }
 
 // This function takes the per-call options and makes sure both layers end up with a valid duration
@@ -0,0 +1,30 @@
+package cache_manager
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+)
+
+// SetWithCallback is Set followed by after(key, err) on a new goroutine, whether or not
+// the Set succeeded, e.g. to update a search index without adding to Set latency. The
+// Set error is also returned. A panic in after is recovered and logged. Close waits for
+// callbacks that are still running.
+func (m *MultiLevelCache) SetWithCallback(ctx context.Context, key string, value any, opts CacheOptions, after func(key string, err error)) error {
+	err := m.Set(ctx, key, value, opts)
+	if after == nil || m == nil {
+		return err
+	}
+
+	m.backgroundWork.Add(1)
+	go func() {
+		defer m.backgroundWork.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				m.log.Log(ctx, slog.LevelError, "cache set callback panicked", "key", key, "panic", r, "stack", string(debug.Stack()))
+			}
+		}()
+		after(key, err)
+	}()
+	return err
+}
@@ -0,0 +1,83 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetWithCallbackFiresOncePerSet(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+
+	var calls atomic.Int64
+	var seen sync.Map
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("user:%d", i)
+		err := ml.SetWithCallback(ctx, key, i, CacheOptions{}, func(got string, err error) {
+			calls.Add(1)
+			require.NoError(t, err)
+			_, dup := seen.LoadOrStore(got, true)
+			require.False(t, dup, "callback fired twice for %s", got)
+		})
+		require.NoError(t, err)
+	}
+
+	// Close waits for the callbacks still running.
+	require.NoError(t, ml.Close(ctx))
+	require.Equal(t, int64(50), calls.Load())
+	for i := 0; i < 50; i++ {
+		_, ok := seen.Load(fmt.Sprintf("user:%d", i))
+		require.True(t, ok)
+	}
+}
+
+func TestSetWithCallbackReceivesSetError(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("down")
+	ml, err := NewMultiLevelCache(failingRawCache{errDown}, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	got := make(chan error, 1)
+	setErr := ml.SetWithCallback(context.Background(), "k", "v", CacheOptions{}, func(key string, err error) {
+		require.Equal(t, "k", key)
+		got <- err
+	})
+	require.ErrorIs(t, setErr, errDown)
+
+	select {
+	case err := <-got:
+		require.ErrorIs(t, err, errDown)
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+}
+
+func TestSetWithCallbackRecoversPanics(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{
+		Mode:   ModeL1Only,
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.SetWithCallback(context.Background(), "k", "v", CacheOptions{}, func(string, error) {
+		panic("indexer exploded")
+	}))
+	require.NoError(t, ml.Close(context.Background()))
+	require.Contains(t, logs.String(), "cache set callback panicked")
+	require.Contains(t, logs.String(), "indexer exploded")
+}
@@ -0,0 +1,65 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayedCacheAppliesLatency(t *testing.T) {
+	t.Parallel()
+
+	dc := NewDelayedCache(newMemoryRawCache(), ChaosConfig{
+		Enabled: true,
+		Latency: 20 * time.Millisecond,
+		Jitter:  10 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		_, _, err := dc.Get(ctx, "key")
+		elapsed := time.Since(start)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+		require.Less(t, elapsed, 80*time.Millisecond)
+	}
+}
+
+func TestDelayedCacheErrorRate(t *testing.T) {
+	t.Parallel()
+
+	dc := NewDelayedCache(newMemoryRawCache(), ChaosConfig{Enabled: true, ErrorRate: 0.3})
+	ctx := context.Background()
+
+	const ops = 2000
+	failures := 0
+	for i := 0; i < ops; i++ {
+		if err := dc.Set(ctx, "key", []byte("v"), time.Minute); err != nil {
+			require.True(t, errors.Is(err, ErrChaosInjected))
+			failures++
+		}
+	}
+	require.InDelta(t, 0.3, float64(failures)/ops, 0.05)
+}
+
+func TestDelayedCacheToggleAtRuntime(t *testing.T) {
+	t.Parallel()
+
+	dc := NewDelayedCache(newMemoryRawCache(), ChaosConfig{Enabled: true, ErrorRate: 1})
+	ctx := context.Background()
+
+	require.ErrorIs(t, dc.Set(ctx, "key", []byte("v"), time.Minute), ErrChaosInjected)
+
+	dc.SetChaos(ChaosConfig{Enabled: false, ErrorRate: 1})
+	require.NoError(t, dc.Set(ctx, "key", []byte("v"), time.Minute))
+
+	data, ok, err := dc.Get(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("v"), data)
+	require.False(t, dc.Chaos().Enabled)
+}
@@ -0,0 +1,23 @@
+package cache_manager
+
+import "time"
+
+// Clock tells the current time. BigCache and MultiLevelCache use it for TTL expiry and
+// event timestamps so tests can substitute a fake clock instead of sleeping. Latency
+// measurements always use the real clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// clockOrSystem returns c, or the system clock when c is nil.
+func clockOrSystem(c Clock) Clock {
+	if c == nil {
+		return systemClock{}
+	}
+	return c
+}
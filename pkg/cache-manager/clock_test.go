@@ -0,0 +1,112 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a Clock that only moves when Advance is called.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newFakeClockBigCache returns a BigCache whose entry expiry follows clock.
+func newFakeClockBigCache(t *testing.T, clock Clock, cfg BigCacheConfig) *BigCache {
+	t.Helper()
+
+	if cfg.Config.Shards == 0 {
+		cfg.Config = bigcache.DefaultConfig(time.Hour)
+		cfg.Config.Verbose = false
+	}
+	cfg.Clock = clock
+	bc, err := NewBigCache(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+	return bc
+}
+
+func TestBigCacheTTLFollowsClock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{})
+	require.NoError(t, bc.Set(ctx, "user:1", []byte("v"), 50*time.Millisecond))
+
+	clock.Advance(30 * time.Millisecond)
+	ttl, found, err := bc.TTL(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 20*time.Millisecond, ttl)
+
+	clock.Advance(20 * time.Millisecond)
+	_, found, err = bc.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, found, "an entry is live up to and including its expiry instant")
+
+	clock.Advance(time.Nanosecond)
+	_, found, err = bc.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestMultiLevelCacheEventsUseClock(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		Mode:  ModeBothLevels,
+		Clock: clock,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Delete(context.Background(), "user:1"))
+	ev := <-ml.Events()
+	require.Equal(t, clock.Now(), ev.TS)
+}
+
+func TestSetIfChangedHashExpiresWithClock(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, Clock: clock})
+	require.NoError(t, err)
+	ctx := context.Background()
+	opts := CacheOptions{L1TTL: time.Minute, L2TTL: time.Minute}
+
+	written, err := ml.SetIfChanged(ctx, "user:1", "ada", opts)
+	require.NoError(t, err)
+	require.True(t, written)
+	written, err = ml.SetIfChanged(ctx, "user:1", "ada", opts)
+	require.NoError(t, err)
+	require.False(t, written)
+
+	// Once the shortest TTL has passed the hash is stale and the value is written again.
+	clock.Advance(time.Minute + time.Second)
+	written, err = ml.SetIfChanged(ctx, "user:1", "ada", opts)
+	require.NoError(t, err)
+	require.True(t, written)
+}
@@ -0,0 +1,61 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+)
+
+// Close leaves the EventBus, stops the work the cache runs in the background (the
+// AutoWarmOnStart run, L2 probes of Degradation and the WarmupBatch queue, whose pending
+// warmups are written first) and waits for it and for running SetWithCallback callbacks to return, or until
+// ctx is done. With MultiLevelConfig.CloseLevels it then closes L2
+// and L1, even when ctx ended first; RedisCache.Close waits for in-flight commands
+// within the same ctx. Without it the levels, which are often shared between instances,
+// are left open and Get, Set and Delete keep working; close them after every cache
+// using them. Close is idempotent.
+func (m *MultiLevelCache) Close(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	m.closeOnce.Do(func() {
+		if m.busCancel != nil {
+			m.busCancel()
+		}
+		m.stopBackground()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		m.backgroundWork.Wait()
+		m.degradation.close()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = &CacheError{Op: "close", Cause: ctx.Err()}
+	}
+
+	if m.closeLevels {
+		m.closeLevelOnce.Do(func() {
+			err = errors.Join(err, closeLevel(ctx, LevelL2, m.l2), closeLevel(ctx, LevelL1, m.l1))
+		})
+	}
+	return err
+}
+
+// closeLevel closes a level that has a Close method; others are left alone.
+func closeLevel(ctx context.Context, level string, cache RawCache) error {
+	var err error
+	switch c := cache.(type) {
+	case interface{ Close(context.Context) error }:
+		err = c.Close(ctx)
+	case interface{ Close() error }:
+		err = c.Close()
+	}
+	if err != nil {
+		return wrapError("close", level, "", err)
+	}
+	return nil
+}
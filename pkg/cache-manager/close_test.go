@@ -0,0 +1,143 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stallingRawCache lists one key and blocks every Get until the context is done.
+type stallingRawCache struct{ *memoryRawCache }
+
+func (stallingRawCache) Keys(context.Context, string) ([]string, error) {
+	return []string{"user:1"}, nil
+}
+
+func (stallingRawCache) Get(ctx context.Context, _ string) ([]byte, bool, error) {
+	<-ctx.Done()
+	return nil, false, ctx.Err()
+}
+
+func TestCloseCancelsAutoWarm(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), stallingRawCache{newMemoryRawCache()}, JSONSerializer{}, MultiLevelConfig{
+		Mode:            ModeBothLevels,
+		AutoWarmOnStart: true,
+		WarmupTimeout:   time.Hour,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, ml.Close(ctx))
+	require.ErrorIs(t, ml.WaitForWarmup(ctx), context.Canceled)
+
+	// Closing again is a no-op, and the cache still serves requests.
+	require.NoError(t, ml.Close(ctx))
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{TargetL1: BoolPtr(true), TargetL2: BoolPtr(false)}))
+}
+
+func TestCloseStopsDegradationProbes(t *testing.T) {
+	t.Parallel()
+
+	l2 := &outageRawCache{memoryRawCache: newMemoryRawCache()}
+	l2.down.Store(true)
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:        ModeBothLevels,
+		Degradation: DegradationConfig{After: time.Nanosecond, ProbeInterval: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, _ = ml.Get(ctx, "k", new(string), CacheOptions{})
+	_, _ = ml.Get(ctx, "k", new(string), CacheOptions{})
+	require.Equal(t, L2Degraded, ml.L2State())
+	require.Eventually(t, func() bool { return l2.probes.Load() > 0 }, time.Second, time.Millisecond)
+
+	require.NoError(t, ml.Close(ctx))
+	probes := l2.probes.Load()
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, probes, l2.probes.Load(), "no probes after Close")
+}
+
+func TestCloseHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ml.backgroundWork.Add(1)
+	defer ml.backgroundWork.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, ml.Close(ctx), context.Canceled)
+}
+
+func TestRedisCacheCloseRejectsLaterOperations(t *testing.T) {
+	t.Parallel()
+
+	l2, _ := newRenameTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, l2.Set(ctx, "k", []byte("v"), time.Minute))
+	require.False(t, l2.Closed())
+
+	require.NoError(t, l2.Close(ctx))
+	require.True(t, l2.Closed())
+	require.NoError(t, l2.Close(ctx), "Close is idempotent")
+
+	_, _, err := l2.Get(ctx, "k")
+	require.ErrorIs(t, err, ErrCacheClosed)
+	require.ErrorIs(t, l2.Set(ctx, "k", []byte("v"), time.Minute), ErrCacheClosed)
+	_, err = l2.Touch(ctx, "k")
+	require.ErrorIs(t, err, ErrCacheClosed)
+}
+
+func TestRedisCacheCloseWaitsForInflight(t *testing.T) {
+	t.Parallel()
+
+	l2, _ := newRenameTestRedis(t)
+	done, err := l2.begin("get", "k")
+	require.NoError(t, err)
+
+	closed := make(chan error, 1)
+	go func() { closed <- l2.Close(context.Background()) }()
+	require.Eventually(t, l2.Closed, time.Second, time.Millisecond)
+	select {
+	case <-closed:
+		t.Fatal("Close returned while an operation was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	done()
+	require.NoError(t, <-closed)
+
+	// A straggler that outlives ctx is cut off and reported.
+	l2, _ = newRenameTestRedis(t)
+	_, err = l2.begin("get", "k")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l2.Close(ctx), context.DeadlineExceeded)
+}
+
+func TestCloseLevels(t *testing.T) {
+	t.Parallel()
+
+	l2, _ := newRenameTestRedis(t)
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, CloseLevels: true})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Close(context.Background()))
+	require.True(t, l2.Closed())
+	require.NoError(t, ml.Close(context.Background()))
+
+	// Without CloseLevels the shared level stays open.
+	l2, _ = newRenameTestRedis(t)
+	ml, err = NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+	require.NoError(t, ml.Close(context.Background()))
+	require.False(t, l2.Closed())
+	require.NoError(t, l2.Set(context.Background(), "k", []byte("v"), 0))
+}
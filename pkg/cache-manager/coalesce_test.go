@@ -0,0 +1,98 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// blockingRawCache counts Get calls and holds each one until release is closed.
+type blockingRawCache struct {
+	*memoryRawCache
+	gets    atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (b *blockingRawCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b.gets.Add(1)
+	<-b.release
+	if b.err != nil {
+		return nil, false, b.err
+	}
+	return b.memoryRawCache.Get(ctx, key)
+}
+
+func runConcurrentGets(t *testing.T, ml *MultiLevelCache, n int, release chan struct{}) []error {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var out map[string]string
+			res, err := ml.Get(context.Background(), "key", &out, CacheOptions{})
+			if err == nil && (!res.Found || out["value"] != "from-l2") {
+				err = errors.New("unexpected result")
+			}
+			errs[i] = err
+		}(i)
+	}
+
+	// Give every goroutine time to join the in-flight read before it completes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return errs
+}
+
+func TestMultiLevelCacheCoalescesConcurrentL2Reads(t *testing.T) {
+	t.Parallel()
+
+	l2 := &blockingRawCache{memoryRawCache: newMemoryRawCache(), release: make(chan struct{})}
+	payload, err := JSONSerializer{}.Marshal(map[string]string{"value": "from-l2"})
+	require.NoError(t, err)
+	require.NoError(t, l2.memoryRawCache.Set(context.Background(), "key", payload, time.Minute))
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:            ModeBothLevels,
+		CoalesceL2Reads: true,
+	})
+	require.NoError(t, err)
+
+	for _, err := range runConcurrentGets(t, ml, 100, l2.release) {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), l2.gets.Load())
+}
+
+func TestMultiLevelCacheCoalescedErrorsAreNotRetained(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("redis down")
+	l2 := &blockingRawCache{memoryRawCache: newMemoryRawCache(), release: make(chan struct{}), err: boom}
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:            ModeBothLevels,
+		CoalesceL2Reads: true,
+	})
+	require.NoError(t, err)
+
+	for _, err := range runConcurrentGets(t, ml, 10, l2.release) {
+		require.ErrorIs(t, err, boom)
+	}
+	require.Equal(t, int32(1), l2.gets.Load())
+
+	// A later Get starts a fresh read instead of reusing the failed one.
+	l2.err = nil
+	_, err = ml.Get(context.Background(), "key", &map[string]string{}, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), l2.gets.Load())
+}
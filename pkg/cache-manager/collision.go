@@ -0,0 +1,109 @@
+package cache_manager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// KeyHasher maps a caller's key to the key stored in the cache, e.g. to bound the length
+// of keys built from user input.
+type KeyHasher interface {
+	HashKey(key string) string
+}
+
+// KeyHasherFunc adapts a function to KeyHasher.
+type KeyHasherFunc func(key string) string
+
+// HashKey calls f(key).
+func (f KeyHasherFunc) HashKey(key string) string {
+	return f(key)
+}
+
+// SHA256KeyHasher stores keys as Prefix followed by the hex SHA-256 of the key.
+type SHA256KeyHasher struct {
+	Prefix string
+}
+
+// HashKey implements KeyHasher.
+func (h SHA256KeyHasher) HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return h.Prefix + hex.EncodeToString(sum[:])
+}
+
+// CollisionDetector remembers which key produced each hash and reports a collision when
+// a different key produces it again. The zero value is ready to use. It keeps one entry
+// per distinct key for its lifetime, so size it by the key space, not by traffic.
+type CollisionDetector struct {
+	// PanicOnCollision panics instead of returning ErrKeyCollision, e.g. in tests and
+	// staging where a collision should stop the process.
+	PanicOnCollision bool
+	// Logger receives an error record per collision. nil uses slog.Default().
+	Logger *slog.Logger
+
+	seen       sync.Map // hash -> original key
+	collisions atomic.Int64
+}
+
+// Check registers key under hash and returns an error wrapping ErrKeyCollision when hash
+// already belongs to a different key. The first key to claim a hash keeps it.
+func (d *CollisionDetector) Check(hash, key string) error {
+	prev, loaded := d.seen.LoadOrStore(hash, key)
+	if !loaded || prev.(string) == key {
+		return nil
+	}
+
+	d.collisions.Add(1)
+	logger := d.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Error("cache key collision", "hash", hash, "key", key, "existing_key", prev)
+
+	err := fmt.Errorf("%w: %q and %q both hash to %q", ErrKeyCollision, key, prev, hash)
+	if d.PanicOnCollision {
+		panic(err)
+	}
+	return err
+}
+
+// CollisionCount returns how many collisions Check has reported.
+func (d *CollisionDetector) CollisionCount() int64 {
+	return d.collisions.Load()
+}
+
+// HashedKeyCache wraps any Cache and stores every key through Hasher. When Detector is
+// set, Set refuses to overwrite an entry whose hash belongs to another key.
+type HashedKeyCache struct {
+	Cache  Cache
+	Hasher KeyHasher
+	// Detector checks every Set for collisions; nil disables the check.
+	Detector *CollisionDetector
+}
+
+var _ Cache = (*HashedKeyCache)(nil)
+
+// Get implements Cache.Get for the hashed key.
+func (c *HashedKeyCache) Get(ctx context.Context, key string, dest any, opts CacheOptions) (CacheGetResult, error) {
+	return c.Cache.Get(ctx, c.Hasher.HashKey(key), dest, opts)
+}
+
+// Set implements Cache.Set for the hashed key.
+func (c *HashedKeyCache) Set(ctx context.Context, key string, value any, opts CacheOptions) error {
+	hash := c.Hasher.HashKey(key)
+	if c.Detector != nil {
+		if err := c.Detector.Check(hash, key); err != nil {
+			return &CacheError{Op: "set", Key: key, Cause: err}
+		}
+	}
+	return c.Cache.Set(ctx, hash, value, opts)
+}
+
+// Delete implements Cache.Delete for the hashed key.
+func (c *HashedKeyCache) Delete(ctx context.Context, key string) error {
+	return c.Cache.Delete(ctx, c.Hasher.HashKey(key))
+}
@@ -0,0 +1,65 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// constantHasher maps every key to the same hash to force collisions.
+var constantHasher = KeyHasherFunc(func(string) string { return "h" })
+
+func TestHashedKeyCacheReportsCollision(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	var logs bytes.Buffer
+	detector := &CollisionDetector{Logger: slog.New(slog.NewTextHandler(&logs, nil))}
+	hc := &HashedKeyCache{Cache: ml, Hasher: constantHasher, Detector: detector}
+	ctx := context.Background()
+
+	require.NoError(t, hc.Set(ctx, "user:1", "ada", CacheOptions{}))
+	require.NoError(t, hc.Set(ctx, "user:1", "ada v2", CacheOptions{}), "re-setting the same key is not a collision")
+	require.True(t, l1.has("h"))
+
+	err := hc.Set(ctx, "user:2", "grace", CacheOptions{})
+	require.ErrorIs(t, err, ErrKeyCollision)
+	require.Equal(t, int64(1), detector.CollisionCount())
+	require.Contains(t, logs.String(), "existing_key=user:1")
+
+	var got string
+	res, err := hc.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "ada v2", got, "the colliding Set must not overwrite the entry")
+}
+
+func TestCollisionDetectorPanicOnCollision(t *testing.T) {
+	t.Parallel()
+
+	detector := &CollisionDetector{PanicOnCollision: true, Logger: slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))}
+	require.NoError(t, detector.Check("h", "a"))
+	require.Panics(t, func() { _ = detector.Check("h", "b") })
+	require.Equal(t, int64(1), detector.CollisionCount())
+}
+
+func TestHashedKeyCacheSHA256(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	hc := &HashedKeyCache{Cache: ml, Hasher: SHA256KeyHasher{Prefix: "k:"}, Detector: &CollisionDetector{}}
+	ctx := context.Background()
+
+	require.NoError(t, hc.Set(ctx, "search:a very long query", 1, CacheOptions{}))
+	require.True(t, l1.has(SHA256KeyHasher{Prefix: "k:"}.HashKey("search:a very long query")))
+	require.Len(t, SHA256KeyHasher{}.HashKey("x"), 64)
+
+	require.NoError(t, hc.Delete(ctx, "search:a very long query"))
+	var got int
+	res, err := hc.Get(ctx, "search:a very long query", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+}
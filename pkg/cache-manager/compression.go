@@ -0,0 +1,97 @@
+package cache_manager
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+)
+
+// CompressionAlgorithm selects how L2 payloads are compressed.
+type CompressionAlgorithm string
+
+const (
+	CompressionNone   CompressionAlgorithm = "none"
+	CompressionGzip   CompressionAlgorithm = "gzip"
+	CompressionSnappy CompressionAlgorithm = "snappy"
+)
+
+// L2CompressionConfig compresses values on their way to L2 only. L1 always holds
+// plain serialized bytes so local hits stay cheap.
+type L2CompressionConfig struct {
+	Algorithm CompressionAlgorithm // "" or CompressionNone disables compression
+	MinSize   int                  // payloads smaller than this are stored uncompressed
+}
+
+// compressedMagic prefixes compressed L2 values and is followed by one algorithm byte.
+// Values without it are read as-is, so plain and compressed entries can coexist while
+// compression is rolled out or switched off.
+var compressedMagic = []byte{0x00, 'c', 'm', 'p'}
+
+const (
+	compressionTagGzip   byte = 1
+	compressionTagSnappy byte = 2
+)
+
+func (c L2CompressionConfig) validate() error {
+	switch c.Algorithm {
+	case "", CompressionNone, CompressionGzip, CompressionSnappy:
+		return nil
+	default:
+		return fmt.Errorf("unknown L2 compression algorithm %q", c.Algorithm)
+	}
+}
+
+// compress returns data framed with the compression header, or data unchanged when
+// compression is disabled or the payload is below MinSize.
+func (c L2CompressionConfig) compress(data []byte) ([]byte, error) {
+	if len(data) < c.MinSize {
+		return data, nil
+	}
+
+	header := append(append([]byte{}, compressedMagic...), 0)
+	switch c.Algorithm {
+	case CompressionGzip:
+		header[len(compressedMagic)] = compressionTagGzip
+		buf := bytes.NewBuffer(header)
+		zw := gzip.NewWriter(buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionSnappy:
+		header[len(compressedMagic)] = compressionTagSnappy
+		return append(header, snappy.Encode(nil, data)...), nil
+	default:
+		return data, nil
+	}
+}
+
+// decompressL2 reverses compress. It does not depend on the current config, so entries
+// written with any algorithm remain readable.
+func decompressL2(data []byte) ([]byte, error) {
+	headerSize := len(compressedMagic) + 1
+	if len(data) < headerSize || !bytes.HasPrefix(data, compressedMagic) {
+		return data, nil
+	}
+
+	body := data[headerSize:]
+	switch tag := data[len(compressedMagic)]; tag {
+	case compressionTagGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case compressionTagSnappy:
+		return snappy.Decode(nil, body)
+	default:
+		return nil, fmt.Errorf("unknown compression tag %d", tag)
+	}
+}
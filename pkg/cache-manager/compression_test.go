@@ -0,0 +1,103 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompressionTestCache(t *testing.T, cfg L2CompressionConfig) (*MultiLevelCache, *memoryRawCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:          ModeBothLevels,
+		WarmupTTL:     time.Minute,
+		L2Compression: cfg,
+	})
+	require.NoError(t, err)
+	return ml, l1, mr
+}
+
+func TestL2CompressionStoresCompressedBytesInRedis(t *testing.T) {
+	t.Parallel()
+
+	for _, algo := range []CompressionAlgorithm{CompressionGzip, CompressionSnappy} {
+		t.Run(string(algo), func(t *testing.T) {
+			t.Parallel()
+
+			ml, l1, mr := newCompressionTestCache(t, L2CompressionConfig{Algorithm: algo})
+			ctx := context.Background()
+			value := map[string]string{"bio": strings.Repeat("cache ", 200)}
+
+			require.NoError(t, ml.Set(ctx, "user:1", value, CacheOptions{}))
+
+			stored, err := mr.Get("user:1")
+			require.NoError(t, err)
+			require.True(t, bytes.HasPrefix([]byte(stored), compressedMagic))
+
+			plain, _, err := l1.Get(ctx, "user:1")
+			require.NoError(t, err)
+			require.Less(t, len(stored), len(plain))
+			require.Equal(t, byte('{'), plain[0])
+
+			// Drop L1 so the read comes from Redis and warms L1 with plain bytes.
+			require.NoError(t, l1.Delete(ctx, "user:1"))
+			var got map[string]string
+			res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+			require.NoError(t, err)
+			require.True(t, res.Found)
+			require.Equal(t, value, got)
+
+			warmed, _, err := l1.Get(ctx, "user:1")
+			require.NoError(t, err)
+			require.Equal(t, plain, warmed)
+		})
+	}
+}
+
+func TestL2CompressionSkipsSmallPayloadsAndReadsLegacyValues(t *testing.T) {
+	t.Parallel()
+
+	ml, _, mr := newCompressionTestCache(t, L2CompressionConfig{Algorithm: CompressionGzip, MinSize: 1024})
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "small", "tiny", CacheOptions{}))
+	stored, err := mr.Get("small")
+	require.NoError(t, err)
+	require.Equal(t, `"tiny"`, stored)
+
+	// Entries written before compression was enabled carry no header.
+	require.NoError(t, mr.Set("legacy", `"plain"`))
+	var got string
+	res, err := ml.Get(ctx, "legacy", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "plain", got)
+}
+
+func TestNewMultiLevelCacheRejectsUnknownCompression(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		Mode:          ModeBothLevels,
+		L2Compression: L2CompressionConfig{Algorithm: "lz4"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,154 @@
+package cache_manager
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// TTLRefresher is implemented by raw caches that can extend a key's TTL without
+// rewriting its value. It reports false when the key does not exist.
+type TTLRefresher interface {
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// SetIfChanged behaves like Set but skips the backend writes when the serialized value is
+// identical to the last one this process wrote for key. With opts.RefreshTTL the TTLs of
+// an unchanged entry are extended instead; if a level has lost the key it is rewritten.
+//
+// Hashes are kept in a bounded in-process LRU that forgets a key after its shortest TTL,
+// on Set, and on Delete. Writes made by other processes, or evictions by the backends
+// themselves, are not seen, so a value evicted early is only restored once the tracked
+// hash expires or RefreshTTL detects the missing key.
+func (m *MultiLevelCache) SetIfChanged(ctx context.Context, key string, value any, opts CacheOptions) (bool, error) {
+	if m == nil {
+		return false, &CacheError{Op: "set", Key: key, Cause: ErrNotInitialized}
+	}
+	return m.set(ctx, m.storeKey(key), value, opts, true)
+}
+
+// refreshTTL extends the TTL of key on the targeted levels. It returns false when a level
+// does not support TTLRefresher, no longer holds the key, or fails.
+func (m *MultiLevelCache) refreshTTL(ctx context.Context, key string, targetL1, targetL2 bool, l1TTL, l2TTL time.Duration) bool {
+	refresh := func(cache RawCache, level string, ttl time.Duration) bool {
+		refresher, ok := cache.(TTLRefresher)
+		if !ok {
+			return false
+		}
+		found, err := refresher.Expire(ctx, key, ttl)
+		if err != nil {
+			m.log.Debug(ctx, "cache ttl refresh failed", "level", level, "key", key, "error", err)
+			return false
+		}
+		return found
+	}
+
+	if targetL1 && !refresh(m.l1, LevelL1, l1TTL) {
+		return false
+	}
+	if targetL2 && !refresh(m.l2, LevelL2, l2TTL) {
+		return false
+	}
+	return true
+}
+
+// shortestTTL returns the smallest TTL among the targeted levels.
+func shortestTTL(targetL1, targetL2 bool, l1TTL, l2TTL time.Duration) time.Duration {
+	switch {
+	case targetL1 && targetL2:
+		return min(l1TTL, l2TTL)
+	case targetL1:
+		return l1TTL
+	default:
+		return l2TTL
+	}
+}
+
+type payloadSum [sha256.Size]byte
+
+// payloadHash hashes the bytes written for a value. L2 bytes are preferred because they
+// are always produced when L2 is targeted; with a shared serializer both are identical.
+func payloadHash(l1Data, l2Data []byte) payloadSum {
+	if l2Data != nil {
+		return sha256.Sum256(l2Data)
+	}
+	return sha256.Sum256(l1Data)
+}
+
+// changeTracker is a bounded LRU of the last payload hash written per key.
+type changeTracker struct {
+	mu    sync.Mutex
+	max   int
+	order *list.List // front = most recently remembered
+	items map[string]*list.Element
+	clock Clock
+}
+
+type trackedHash struct {
+	key       string
+	sum       payloadSum
+	expiresAt time.Time
+}
+
+func newChangeTracker(max int, clock Clock) *changeTracker {
+	return &changeTracker{
+		max:   max,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+		clock: clock,
+	}
+}
+
+// unchanged reports whether sum matches the live hash remembered for key.
+func (c *changeTracker) unchanged(key string, sum payloadSum) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	entry := el.Value.(*trackedHash)
+	if c.clock.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return false
+	}
+	return entry.sum == sum
+}
+
+func (c *changeTracker) remember(key string, sum payloadSum, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &trackedHash{key: key, sum: sum, expiresAt: c.clock.Now().Add(ttl)}
+	if el, ok := c.items[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*trackedHash).key)
+	}
+}
+
+func (c *changeTracker) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+func (c *changeTracker) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,126 @@
+package cache_manager
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingRawCache counts writes and TTL refreshes on top of memoryRawCache.
+type countingRawCache struct {
+	*memoryRawCache
+	sets    atomic.Int32
+	expires atomic.Int32
+}
+
+func (c *countingRawCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.sets.Add(1)
+	return c.memoryRawCache.Set(ctx, key, value, ttl)
+}
+
+func (c *countingRawCache) Expire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	c.expires.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.data[key]; !ok {
+		return false, nil
+	}
+	c.ttl[key] = ttl
+	return true, nil
+}
+
+func newCountingRawCache() *countingRawCache {
+	return &countingRawCache{memoryRawCache: newMemoryRawCache()}
+}
+
+func TestSetIfChangedSkipsUnchangedValue(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := newCountingRawCache(), newCountingRawCache()
+	ml := newTestMultiLevelCacheOver(t, l1, l2, MultiLevelConfig{ChangeTrackingMaxKeys: 2})
+	ctx := context.Background()
+
+	changed, err := ml.SetIfChanged(ctx, "user:1", map[string]string{"name": "ada"}, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	changed, err = ml.SetIfChanged(ctx, "user:1", map[string]string{"name": "ada"}, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, int32(1), l1.sets.Load())
+	require.Equal(t, int32(1), l2.sets.Load())
+	require.Zero(t, l2.expires.Load())
+
+	changed, err = ml.SetIfChanged(ctx, "user:1", map[string]string{"name": "grace"}, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, int32(2), l2.sets.Load())
+}
+
+func TestSetIfChangedRefreshesTTL(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := newCountingRawCache(), newCountingRawCache()
+	ml := newTestMultiLevelCacheOver(t, l1, l2, MultiLevelConfig{ChangeTrackingMaxKeys: 2})
+	ctx := context.Background()
+
+	_, err := ml.SetIfChanged(ctx, "user:1", "ada", CacheOptions{})
+	require.NoError(t, err)
+
+	changed, err := ml.SetIfChanged(ctx, "user:1", "ada", CacheOptions{L2TTL: time.Hour, RefreshTTL: true})
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, int32(1), l2.sets.Load())
+	require.Equal(t, int32(1), l2.expires.Load())
+	require.Equal(t, time.Hour, l2.ttl["user:1"])
+
+	// A level that lost the key is rewritten rather than refreshed.
+	require.NoError(t, l1.Delete(ctx, "user:1"))
+	changed, err = ml.SetIfChanged(ctx, "user:1", "ada", CacheOptions{RefreshTTL: true})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.True(t, l1.has("user:1"))
+}
+
+func TestSetIfChangedForgetsOnSetAndDelete(t *testing.T) {
+	t.Parallel()
+
+	l2 := newCountingRawCache()
+	ml := newTestMultiLevelCacheOver(t, newCountingRawCache(), l2, MultiLevelConfig{ChangeTrackingMaxKeys: 2})
+	ctx := context.Background()
+
+	_, err := ml.SetIfChanged(ctx, "user:1", "ada", CacheOptions{})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(ctx, "user:1", "grace", CacheOptions{}))
+
+	changed, err := ml.SetIfChanged(ctx, "user:1", "ada", CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	require.NoError(t, ml.Delete(ctx, "user:1"))
+	changed, err = ml.SetIfChanged(ctx, "user:1", "ada", CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, int32(4), l2.sets.Load())
+}
+
+func TestSetIfChangedTrackingIsBounded(t *testing.T) {
+	t.Parallel()
+
+	ml := newTestMultiLevelCacheOver(t, newCountingRawCache(), newCountingRawCache(), MultiLevelConfig{ChangeTrackingMaxKeys: 2})
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		_, err := ml.SetIfChanged(ctx, key, 1, CacheOptions{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, ml.changes.len())
+
+	// "a" was evicted from the tracker, so it is written again.
+	changed, err := ml.SetIfChanged(ctx, "a", 1, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, changed)
+}
@@ -0,0 +1,164 @@
+package cache_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration that reads and writes JSON as a Go duration string ("10m", "30s").
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON accepts a duration string, or a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("duration must be a string like \"10m\": %s", data)
+		}
+		*d = Duration(n)
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// String returns the names used for modes in configuration files.
+func (m CacheMode) String() string {
+	switch m {
+	case ModeBothLevels:
+		return "both-levels"
+	case ModeL1Only:
+		return "l1-only"
+	case ModeL2Only:
+		return "l2-only"
+	default:
+		return fmt.Sprintf("CacheMode(%d)", int(m))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m CacheMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *CacheMode) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "both-levels", "":
+		*m = ModeBothLevels
+	case "l1-only":
+		*m = ModeL1Only
+	case "l2-only":
+		*m = ModeL2Only
+	default:
+		return fmt.Errorf("unknown cache mode %q (want both-levels, l1-only or l2-only)", text)
+	}
+	return nil
+}
+
+// FileConfig is the JSON configuration file format. Cache fields map 1:1 to MultiLevelConfig;
+// the rest configure the backing stores. Zero values fall back to the library defaults.
+type FileConfig struct {
+	// Comment is free-form documentation; it is ignored when loading.
+	Comment string `json:"_comment,omitempty"`
+
+	Mode              CacheMode `json:"mode"`
+	WarmupTTL         Duration  `json:"warmup_ttl"`
+	L1DefaultTTL      Duration  `json:"l1_default_ttl"`
+	L2DefaultTTL      Duration  `json:"l2_default_ttl"`
+	MaxFlushPerMinute int       `json:"max_flush_per_minute"`
+	Version           string    `json:"version,omitempty"`
+	L2DegradeAfter    Duration  `json:"l2_degrade_after,omitempty"`
+
+	L2Compression        CompressionAlgorithm `json:"l2_compression,omitempty"`
+	L2CompressionMinSize int                  `json:"l2_compression_min_size,omitempty"`
+
+	RedisAddr          string   `json:"redis_addr"`
+	BigCacheShards     int      `json:"bigcache_shards"`
+	BigCacheLifeWindow Duration `json:"bigcache_life_window"`
+	L1SnapshotPath     string   `json:"l1_snapshot_path"`
+}
+
+// MultiLevel returns the MultiLevelConfig portion of the file.
+func (f FileConfig) MultiLevel() MultiLevelConfig {
+	return MultiLevelConfig{
+		Mode:              f.Mode,
+		WarmupTTL:         time.Duration(f.WarmupTTL),
+		L1DefaultTTL:      time.Duration(f.L1DefaultTTL),
+		L2DefaultTTL:      time.Duration(f.L2DefaultTTL),
+		MaxFlushPerMinute: f.MaxFlushPerMinute,
+		Version:           f.Version,
+		Degradation:       DegradationConfig{After: time.Duration(f.L2DegradeAfter)},
+		L2Compression: L2CompressionConfig{
+			Algorithm: f.L2Compression,
+			MinSize:   f.L2CompressionMinSize,
+		},
+	}
+}
+
+// DefaultFileConfig returns the configuration written by WriteDefaultConfig.
+func DefaultFileConfig() FileConfig {
+	return FileConfig{
+		Comment: "Durations use Go syntax (e.g. 30s, 5m). mode: both-levels | l1-only | l2-only. " +
+			"max_flush_per_minute: 0 disables the Flush/DeleteByPrefix rate limit. " +
+			"version: prefix folded into every key; bump it to invalidate all entries on deploy. " +
+			"l2_degrade_after: how long L2 may keep failing before the cache runs L1-only; empty disables. " +
+			"l1_snapshot_path: empty disables L1 persistence across restarts. " +
+			"l2_compression: none | gzip | snappy, applied to values of at least l2_compression_min_size bytes.",
+		Mode:               ModeBothLevels,
+		WarmupTTL:          Duration(5 * time.Minute),
+		L1DefaultTTL:       Duration(5 * time.Minute),
+		L2DefaultTTL:       Duration(5 * time.Minute),
+		RedisAddr:          "localhost:6379",
+		BigCacheShards:     1024,
+		BigCacheLifeWindow: Duration(10 * time.Minute),
+	}
+}
+
+// LoadFileConfig reads a JSON configuration file. Unknown fields are rejected to catch typos.
+func LoadFileConfig(path string) (FileConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return FileConfig{}, fmt.Errorf("open config: %w", err)
+	}
+	defer f.Close()
+
+	var cfg FileConfig
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return FileConfig{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadConfig reads a JSON configuration file and returns its MultiLevelConfig.
+func LoadConfig(path string) (MultiLevelConfig, error) {
+	cfg, err := LoadFileConfig(path)
+	if err != nil {
+		return MultiLevelConfig{}, err
+	}
+	return cfg.MultiLevel(), nil
+}
+
+// WriteDefaultConfig writes DefaultFileConfig to path as indented JSON.
+func WriteDefaultConfig(path string) error {
+	data, err := json.MarshalIndent(DefaultFileConfig(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
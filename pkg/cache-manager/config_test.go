@@ -0,0 +1,77 @@
+package cache_manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	want := FileConfig{
+		Mode:               ModeL2Only,
+		WarmupTTL:          Duration(30 * time.Second),
+		L1DefaultTTL:       Duration(time.Minute),
+		L2DefaultTTL:       Duration(10 * time.Minute),
+		MaxFlushPerMinute:  5,
+		RedisAddr:          "redis:6379",
+		BigCacheShards:     256,
+		BigCacheLifeWindow: Duration(time.Hour),
+		L1SnapshotPath:     "/var/lib/cache/l1.snapshot",
+	}
+
+	data, err := json.Marshal(want)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"l2_default_ttl":"10m0s"`)
+	require.Contains(t, string(data), `"mode":"l2-only"`)
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	got, err := LoadFileConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	ml, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, MultiLevelConfig{
+		Mode:              ModeL2Only,
+		WarmupTTL:         30 * time.Second,
+		L1DefaultTTL:      time.Minute,
+		L2DefaultTTL:      10 * time.Minute,
+		MaxFlushPerMinute: 5,
+	}, ml)
+}
+
+func TestWriteDefaultConfigIsLoadable(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	require.NoError(t, WriteDefaultConfig(path))
+
+	got, err := LoadFileConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, DefaultFileConfig(), got)
+}
+
+func TestLoadFileConfigRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cases := map[string]string{
+		"bad duration": `{"l1_default_ttl": "soon"}`,
+		"bad mode":     `{"mode": "l3-only"}`,
+		"unknown key":  `{"l1_ttl": "1m"}`,
+	}
+	for name, body := range cases {
+		path := filepath.Join(dir, name+".json")
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+		_, err := LoadFileConfig(path)
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,143 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// counterMirrorTTL caps how long L1 mirrors an L2 counter in ModeBothLevels.
+const counterMirrorTTL = time.Second
+
+// Incrementer is implemented by raw caches with a native atomic counter. The ttl is
+// applied when the counter is created and left alone afterwards.
+type Incrementer interface {
+	IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// Increment atomically adds delta to the counter at key and returns the new value. A
+// missing counter starts at zero and gets the level's TTL from opts.
+//
+// Counters are stored as decimal strings and bypass the serializer and L2 compression.
+// When L2 is targeted it is authoritative (Redis INCRBY) and L1 only keeps a mirror for
+// at most one second; in L1-only mode the read-modify-write runs under a per-key lock,
+// which is atomic within this process only.
+func (m *MultiLevelCache) Increment(ctx context.Context, key string, delta int64, opts CacheOptions) (int64, error) {
+	if m == nil {
+		return 0, &CacheError{Op: "incr", Key: key, Cause: ErrNotInitialized}
+	}
+	key = m.storeKey(key)
+
+	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
+		return 0, &CacheError{Op: "incr", Key: key, Cause: ErrLevelOverrideNotAllowed}
+	}
+	targetL1, targetL2 := m.determineCacheLevel()
+	targetL1, targetL2 = m.applyEndpointLevelOverrides(opts, targetL1, targetL2)
+	if !targetL1 && !targetL2 {
+		return 0, &CacheError{Op: "incr", Key: key, Cause: ErrNoLevelTargeted}
+	}
+	if targetL1 && m.l1 == nil {
+		return 0, &CacheError{Op: "incr", Level: LevelL1, Key: key, Cause: ErrLevelNotConfigured}
+	}
+	if targetL2 && m.l2 == nil {
+		return 0, &CacheError{Op: "incr", Level: LevelL2, Key: key, Cause: ErrLevelNotConfigured}
+	}
+
+	l1TTL, l2TTL := m.ttlsFor(key, opts)
+	m.changes.forget(key)
+
+	if !targetL2 {
+		value, err := m.incrementL1(ctx, key, delta, l1TTL)
+		if err != nil {
+			return 0, wrapError("incr", LevelL1, key, err)
+		}
+		return value, nil
+	}
+
+	incr, ok := m.l2.(Incrementer)
+	if !ok {
+		return 0, &CacheError{Op: "incr", Level: LevelL2, Key: key, Cause: errors.ErrUnsupported}
+	}
+	value, err := incr.IncrBy(ctx, key, delta, l2TTL)
+	if err != nil {
+		return 0, wrapError("incr", LevelL2, key, err)
+	}
+
+	if targetL1 {
+		// best-effort mirror; a failure only means the next GetCounter reads L2.
+		mirror := strconv.AppendInt(nil, value, 10)
+		if err := m.l1.Set(ctx, key, mirror, min(l1TTL, counterMirrorTTL)); err != nil {
+			_ = m.l1.Delete(ctx, key)
+		}
+	}
+	return value, nil
+}
+
+// Decrement is Increment with -delta.
+func (m *MultiLevelCache) Decrement(ctx context.Context, key string, delta int64, opts CacheOptions) (int64, error) {
+	return m.Increment(ctx, key, -delta, opts)
+}
+
+// GetCounter reads a counter written by Increment, checking the targeted levels in order.
+func (m *MultiLevelCache) GetCounter(ctx context.Context, key string, opts CacheOptions) (int64, bool, error) {
+	if m == nil {
+		return 0, false, &CacheError{Op: "get", Key: key, Cause: ErrNotInitialized}
+	}
+	key = m.storeKey(key)
+
+	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
+		return 0, false, &CacheError{Op: "get", Key: key, Cause: ErrLevelOverrideNotAllowed}
+	}
+	checkL1, checkL2 := m.determineCacheLevel()
+	checkL1, checkL2 = m.applyEndpointLevelOverrides(opts, checkL1, checkL2)
+
+	for _, lvl := range m.levels() {
+		if (lvl.name == LevelL1 && !checkL1) || (lvl.name == LevelL2 && !checkL2) {
+			continue
+		}
+		data, ok, err := lvl.cache.Get(ctx, key)
+		if err != nil {
+			return 0, false, wrapError("get", lvl.name, key, err)
+		}
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return 0, false, wrapError("get", lvl.name, key, err)
+		}
+		return value, true, nil
+	}
+	return 0, false, nil
+}
+
+// incrementL1 performs a locked read-modify-write of an L1 counter, keeping the existing
+// expiry when L1 supports KeepTTLSetter.
+func (m *MultiLevelCache) incrementL1(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	unlock := m.keyLocks.Lock(key)
+	defer unlock()
+
+	var current int64
+	data, ok, err := m.l1.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		if current, err = strconv.ParseInt(string(data), 10, 64); err != nil {
+			return 0, err
+		}
+	}
+
+	value := current + delta
+	encoded := strconv.AppendInt(nil, value, 10)
+	if kts, canKeep := m.l1.(KeepTTLSetter); canKeep && ok {
+		err = kts.SetKeepTTL(ctx, key, encoded, ttl)
+	} else {
+		err = m.l1.Set(ctx, key, encoded, ttl)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return value, nil
+}
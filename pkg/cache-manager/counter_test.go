@@ -0,0 +1,91 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+// hammerIncrement runs workers*perWorker increments of 1 for key concurrently.
+func hammerIncrement(t *testing.T, ml *MultiLevelCache, key string, workers, perWorker int) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				if _, err := ml.Increment(context.Background(), key, 1, CacheOptions{}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestIncrementBothLevelsIsExact(t *testing.T) {
+	t.Parallel()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:         ModeBothLevels,
+		L2DefaultTTL: time.Hour,
+	})
+	require.NoError(t, err)
+
+	hammerIncrement(t, ml, "views:1", 20, 50)
+
+	stored, err := mr.Get("views:1")
+	require.NoError(t, err)
+	require.Equal(t, "1000", stored)
+	require.Equal(t, time.Hour, mr.TTL("views:1"))
+
+	value, err := ml.Decrement(context.Background(), "views:1", 10, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(990), value)
+
+	got, found, err := ml.GetCounter(context.Background(), "views:1", CacheOptions{TargetL1: BoolPtr(false)})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, int64(990), got)
+}
+
+func TestIncrementL1OnlyIsExact(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	hammerIncrement(t, ml, "rate:ip", 20, 50)
+
+	got, found, err := ml.GetCounter(context.Background(), "rate:ip", CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, int64(1000), got)
+
+	_, found, err = ml.GetCounter(context.Background(), "rate:missing", CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, found)
+}
@@ -0,0 +1,17 @@
+package cache_manager
+
+import "context"
+
+// SetDefault is Set with empty options: each level gets its L1DefaultTTL or L2DefaultTTL
+// and the levels follow the configured mode. With tags it is SetWithTags.
+func (m *MultiLevelCache) SetDefault(ctx context.Context, key string, value any, tags ...string) error {
+	if len(tags) > 0 {
+		return m.SetWithTags(ctx, key, value, CacheOptions{}, tags...)
+	}
+	return m.Set(ctx, key, value, CacheOptions{})
+}
+
+// GetDefault is Get with empty options.
+func (m *MultiLevelCache) GetDefault(ctx context.Context, key string, dest any) (CacheGetResult, error) {
+	return m.Get(ctx, key, dest, CacheOptions{})
+}
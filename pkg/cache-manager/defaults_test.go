@@ -0,0 +1,68 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefaultUsesConfiguredTTLs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:         ModeBothLevels,
+		L1DefaultTTL: 30 * time.Second,
+		L2DefaultTTL: 10 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.SetDefault(ctx, "user:1", "alice"))
+	require.Equal(t, 30*time.Second, l1.ttl["user:1"])
+	require.Equal(t, 10*time.Minute, l2.ttl["user:1"])
+
+	var got string
+	res, err := ml.GetDefault(ctx, "user:1", &got)
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, CacheLevelL1, res.Level)
+	require.Equal(t, "alice", got)
+
+	res, err = ml.GetDefault(ctx, "user:2", &got)
+	require.NoError(t, err)
+	require.False(t, res.Found)
+}
+
+func TestSetDefaultWithTags(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, ml.SetDefault(ctx, "users:page:1", "page", "users:list"))
+	require.Equal(t, time.Minute, l1.ttl["users:page:1"])
+
+	deleted, err := ml.InvalidateTag(ctx, "users:list")
+	require.NoError(t, err)
+	require.Equal(t, []string{"users:page:1"}, deleted)
+	require.False(t, l2.has("users:page:1"))
+}
+
+func TestSetDefaultFollowsMode(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l2 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only, L2DefaultTTL: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.SetDefault(ctx, "user:1", "alice"))
+	require.Equal(t, time.Hour, l2.ttl["user:1"])
+
+	var nilCache *MultiLevelCache
+	require.ErrorIs(t, nilCache.SetDefault(ctx, "k", "v"), ErrNotInitialized)
+	_, err = nilCache.GetDefault(ctx, "k", new(string))
+	require.ErrorIs(t, err, ErrNotInitialized)
+}
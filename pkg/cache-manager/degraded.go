@@ -0,0 +1,238 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// L2State is the degradation state of L2, see DegradationConfig.
+type L2State int
+
+const (
+	// L2Normal sends L2 calls as usual.
+	L2Normal L2State = iota
+	// L2Degraded skips L2 entirely; only background probes reach it.
+	L2Degraded
+	// L2Recovering lets calls through again after a successful probe. The next L2
+	// success returns to L2Normal, the next failure back to L2Degraded.
+	L2Recovering
+)
+
+// String returns the state name used in stats and logs.
+func (s L2State) String() string {
+	switch s {
+	case L2Normal:
+		return "normal"
+	case L2Degraded:
+		return "degraded"
+	case L2Recovering:
+		return "recovering"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	defaultDegradedProbeInterval = 5 * time.Second
+	defaultDegradedProbeKey      = "__cache_probe__"
+)
+
+// DegradationConfig switches a cache to L1-only operation while L2 is down, so requests
+// stop paying an L2 timeout each. Get, Set and Delete report their L2 outcome; once L2
+// has failed for After without a single success the cache is degraded:
+//   - Get serves from L1 alone (a miss is a miss) and fails with ErrL2Degraded only when
+//     L2 is the sole targeted level.
+//   - Set writes L1 alone; it fails with ErrL2Degraded only when L2 is the sole target.
+//   - Delete removes the L1 copy and returns ErrL2Degraded, because the L2 copy survives.
+//
+// Other operations (counters, GetAndDelete, bulk and admin calls) are not gated.
+type DegradationConfig struct {
+	// After is how long L2 must keep failing before the cache degrades. 0 disables.
+	After time.Duration
+	// ProbeInterval is the pause between background L2 probes while degraded. Default 5s.
+	ProbeInterval time.Duration
+	// ProbeKey is read by probes; a miss counts as success. Default "__cache_probe__".
+	ProbeKey string
+	// OnTransition is called after every state change. It runs synchronously on the
+	// goroutine that caused the change and must not block.
+	OnTransition func(from, to L2State)
+}
+
+// l2Degradation is the Normal → Degraded → Recovering state machine. A nil
+// *l2Degradation always allows L2.
+type l2Degradation struct {
+	after         time.Duration
+	probeInterval time.Duration
+	probe         func(ctx context.Context) error
+	onTransition  func(from, to L2State)
+	clock         Clock
+
+	mu           sync.Mutex
+	state        L2State
+	failingSince time.Time // zero while the last L2 call succeeded
+
+	closed  chan struct{} // closed by close; stops probing
+	probing sync.WaitGroup
+}
+
+func newL2Degradation(cfg DegradationConfig, l2 RawCache, clock Clock) *l2Degradation {
+	if cfg.After <= 0 || l2 == nil {
+		return nil
+	}
+	interval := cfg.ProbeInterval
+	if interval <= 0 {
+		interval = defaultDegradedProbeInterval
+	}
+	probeKey := cfg.ProbeKey
+	if probeKey == "" {
+		probeKey = defaultDegradedProbeKey
+	}
+	return &l2Degradation{
+		after:         cfg.After,
+		probeInterval: interval,
+		probe: func(ctx context.Context) error {
+			_, _, err := l2.Get(ctx, probeKey)
+			return err
+		},
+		onTransition: cfg.OnTransition,
+		clock:        clock,
+		closed:       make(chan struct{}),
+	}
+}
+
+// close stops background probing and waits for a running probe to return. The state
+// machine keeps working; a later degradation just does not probe.
+func (d *l2Degradation) close() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	select {
+	case <-d.closed:
+	default:
+		close(d.closed)
+	}
+	d.mu.Unlock()
+	d.probing.Wait()
+}
+
+// allow reports whether L2 may be called.
+func (d *l2Degradation) allow() bool {
+	return d.current() != L2Degraded
+}
+
+func (d *l2Degradation) current() L2State {
+	if d == nil {
+		return L2Normal
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+// record feeds the outcome of an L2 call into the state machine. Cancellations by the
+// caller and calls throttled by L2MaxConcurrency say nothing about L2 health and are
+// ignored.
+func (d *l2Degradation) record(err error) {
+	if d == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrL2Throttled) {
+		return
+	}
+
+	d.mu.Lock()
+	from := d.state
+	switch {
+	case err == nil:
+		d.failingSince = time.Time{}
+		if d.state == L2Recovering {
+			d.state = L2Normal
+		}
+	case d.state == L2Recovering:
+		d.state = L2Degraded
+	case d.state == L2Normal:
+		now := d.clock.Now()
+		if d.failingSince.IsZero() {
+			d.failingSince = now
+		}
+		if now.Sub(d.failingSince) >= d.after {
+			d.state = L2Degraded
+		}
+	}
+	to := d.state
+	d.mu.Unlock()
+
+	d.transitioned(from, to)
+}
+
+// transitioned notifies the hook and starts probing on entering L2Degraded.
+func (d *l2Degradation) transitioned(from, to L2State) {
+	if from == to {
+		return
+	}
+	if to == L2Degraded {
+		d.mu.Lock()
+		select {
+		case <-d.closed:
+		default:
+			d.probing.Add(1)
+			go d.probeUntilHealthy()
+		}
+		d.mu.Unlock()
+	}
+	if d.onTransition != nil {
+		d.onTransition(from, to)
+	}
+}
+
+// probeUntilHealthy probes L2 every probeInterval and moves to L2Recovering on the first
+// success. It exits as soon as the cache is no longer degraded or is closed.
+func (d *l2Degradation) probeUntilHealthy() {
+	defer d.probing.Done()
+	ticker := time.NewTicker(d.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.closed:
+			return
+		case <-ticker.C:
+		}
+		if d.current() != L2Degraded {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), d.probeInterval)
+		err := d.probe(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		d.mu.Lock()
+		from := d.state
+		if d.state == L2Degraded {
+			d.state = L2Recovering
+			d.failingSince = time.Time{}
+		}
+		to := d.state
+		d.mu.Unlock()
+
+		d.transitioned(from, to)
+		return
+	}
+}
+
+// L2State reports whether L2 is in use, skipped or being retried. A disconnect reported
+// by a ConnectionNotifier L2 shows as L2Degraded. It is always L2Normal when neither
+// applies.
+func (m *MultiLevelCache) L2State() L2State {
+	if m.l2Disconnected.Load() {
+		return L2Degraded
+	}
+	return m.degradation.current()
+}
+
+// l2Available reports whether Get, Set and Delete may call L2.
+func (m *MultiLevelCache) l2Available() bool {
+	return !m.l2Disconnected.Load() && m.degradation.allow()
+}
@@ -0,0 +1,176 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// outageRawCache fails every call while down is set and counts calls, with probe reads
+// counted separately.
+type outageRawCache struct {
+	*memoryRawCache
+	down   atomic.Bool
+	calls  atomic.Int64
+	probes atomic.Int64
+}
+
+var errRedisOutage = errors.New("redis outage")
+
+func (c *outageRawCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if key == defaultDegradedProbeKey {
+		c.probes.Add(1)
+	} else {
+		c.calls.Add(1)
+	}
+	if c.down.Load() {
+		return nil, false, errRedisOutage
+	}
+	return c.memoryRawCache.Get(ctx, key)
+}
+
+func (c *outageRawCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.calls.Add(1)
+	if c.down.Load() {
+		return errRedisOutage
+	}
+	return c.memoryRawCache.Set(ctx, key, value, ttl)
+}
+
+func (c *outageRawCache) Delete(ctx context.Context, key string) error {
+	c.calls.Add(1)
+	if c.down.Load() {
+		return errRedisOutage
+	}
+	return c.memoryRawCache.Delete(ctx, key)
+}
+
+type transitionRecorder struct {
+	mu  sync.Mutex
+	got [][2]L2State
+}
+
+func (r *transitionRecorder) record(from, to L2State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, [2]L2State{from, to})
+}
+
+func (r *transitionRecorder) transitions() [][2]L2State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][2]L2State(nil), r.got...)
+}
+
+func TestDegradationScriptedOutageAndRecovery(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	l1, l2 := newMemoryRawCache(), &outageRawCache{memoryRawCache: newMemoryRawCache()}
+	var hooks transitionRecorder
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:  ModeBothLevels,
+		Clock: clock,
+		Degradation: DegradationConfig{
+			After:         30 * time.Second,
+			ProbeInterval: 5 * time.Millisecond,
+			OnTransition:  hooks.record,
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+
+	// Outage starts: failures shorter than After keep the cache in L2Normal.
+	l2.down.Store(true)
+	var got string
+	_, err = ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.ErrorIs(t, err, errRedisOutage)
+	clock.Advance(29 * time.Second)
+	_, err = ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.ErrorIs(t, err, errRedisOutage)
+	require.Equal(t, L2Normal, ml.L2State())
+
+	clock.Advance(time.Second)
+	_, err = ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.ErrorIs(t, err, errRedisOutage)
+	require.Equal(t, L2Degraded, ml.L2State())
+
+	// Degraded: L1 serves alone and L2 sees nothing but probes.
+	before := l2.calls.Load()
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "ada", got)
+	res, err = ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.NoError(t, ml.Set(ctx, "user:3", "bob", CacheOptions{}))
+	require.True(t, l1.has("user:3"))
+	require.ErrorIs(t, ml.Delete(ctx, "user:3"), ErrL2Degraded)
+	_, err = ml.Get(ctx, "user:1", &got, CacheOptions{TargetL1: BoolPtr(false)})
+	require.ErrorIs(t, err, ErrL2Degraded)
+
+	report, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "degraded", report.L2State)
+
+	require.Eventually(t, func() bool { return l2.probes.Load() >= 2 }, time.Second, time.Millisecond)
+	require.Equal(t, before, l2.calls.Load(), "only probes may reach L2 while degraded")
+
+	// Redis recovers: a probe moves to L2Recovering and the next L2 success to L2Normal.
+	l2.down.Store(false)
+	require.Eventually(t, func() bool { return ml.L2State() == L2Recovering }, time.Second, time.Millisecond)
+	_, err = ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, L2Normal, ml.L2State())
+	require.Greater(t, l2.calls.Load(), before)
+
+	require.Equal(t, [][2]L2State{
+		{L2Normal, L2Degraded},
+		{L2Degraded, L2Recovering},
+		{L2Recovering, L2Normal},
+	}, hooks.transitions())
+}
+
+func TestDegradationRecoveringFailureDegradesAgain(t *testing.T) {
+	t.Parallel()
+
+	l2 := &outageRawCache{memoryRawCache: newMemoryRawCache()}
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:        ModeBothLevels,
+		Degradation: DegradationConfig{After: time.Nanosecond, ProbeInterval: 5 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	l2.down.Store(true)
+	t.Cleanup(func() { l2.down.Store(false) }) // let the last prober exit
+	var got string
+	for ml.L2State() != L2Degraded {
+		_, _ = ml.Get(ctx, "k", &got, CacheOptions{})
+	}
+
+	l2.down.Store(false)
+	require.Eventually(t, func() bool { return ml.L2State() == L2Recovering }, time.Second, time.Millisecond)
+
+	l2.down.Store(true)
+	_, err = ml.Get(ctx, "k", &got, CacheOptions{})
+	require.ErrorIs(t, err, errRedisOutage)
+	require.Equal(t, L2Degraded, ml.L2State())
+}
+
+func TestDegradationDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.Equal(t, L2Normal, ml.L2State())
+	report, err := ml.Stats(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, report.L2State)
+}
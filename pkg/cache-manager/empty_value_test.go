@@ -0,0 +1,134 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/allegro/bigcache/v3"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+// passthroughSerializer stores []byte values as-is, so an empty value is a zero-length
+// payload in both levels.
+type passthroughSerializer struct{}
+
+func (passthroughSerializer) Marshal(value any) ([]byte, error) {
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("passthrough serializer needs []byte, got %T", value)
+	}
+	return b, nil
+}
+
+func (passthroughSerializer) Unmarshal(data []byte, dest any) error {
+	p, ok := dest.(*[]byte)
+	if !ok {
+		return fmt.Errorf("passthrough serializer needs *[]byte, got %T", dest)
+	}
+	*p = append([]byte{}, data...)
+	return nil
+}
+
+// newEmptyValueTestCache builds a cache over a real BigCache and miniredis so the L1
+// entry envelope and the Redis round trip are both exercised.
+func newEmptyValueTestCache(t *testing.T, serializer Serializer) (*MultiLevelCache, *BigCache) {
+	t.Helper()
+
+	bcConfig := bigcache.DefaultConfig(time.Hour)
+	bcConfig.Verbose = false
+	l1, err := NewBigCache(context.Background(), BigCacheConfig{Config: bcConfig})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+
+	ml, err := NewMultiLevelCache(l1, l2, serializer, MultiLevelConfig{Mode: ModeBothLevels, WarmupTTL: time.Minute})
+	require.NoError(t, err)
+	return ml, l1
+}
+
+func TestEmptyValuesAreHitsNotMisses(t *testing.T) {
+	t.Parallel()
+
+	type profile struct{ Name string }
+	tests := []struct {
+		name       string
+		serializer Serializer
+		value      any
+		newDest    func() any
+	}{
+		{"empty JSON object", JSONSerializer{}, map[string]any{}, func() any { return &map[string]any{} }},
+		{"empty array", JSONSerializer{}, []int{}, func() any { return &[]int{1} }},
+		{"empty string", JSONSerializer{}, "", func() any { s := "x"; return &s }},
+		{"nil pointer", JSONSerializer{}, (*profile)(nil), func() any { p := &profile{Name: "x"}; return &p }},
+		{"zero-length payload", passthroughSerializer{}, []byte{}, func() any { b := []byte("x"); return &b }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ml, l1 := newEmptyValueTestCache(t, tt.serializer)
+			ctx := context.Background()
+			require.NoError(t, ml.Set(ctx, "k", tt.value, CacheOptions{}))
+
+			check := func(level CacheLevelHit) {
+				t.Helper()
+				dest := tt.newDest()
+				res, err := ml.Get(ctx, "k", dest, CacheOptions{})
+				require.NoError(t, err)
+				require.Equal(t, CacheGetResult{Found: true, Level: level}, res)
+				require.Equal(t, normalizeEmpty(tt.value), normalizeEmpty(reflect.ValueOf(dest).Elem().Interface()))
+			}
+
+			check(CacheLevelL1)
+
+			// Served from L2, which warms L1 with the empty payload again.
+			require.NoError(t, l1.Delete(ctx, "k"))
+			check(CacheLevelL2)
+			_, found, err := l1.Get(ctx, "k")
+			require.NoError(t, err)
+			require.True(t, found, "warmup must store empty payloads in L1")
+			check(CacheLevelL1)
+		})
+	}
+}
+
+// normalizeEmpty treats nil and empty slices alike, since serializers differ on which
+// one an empty value decodes to.
+func normalizeEmpty(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.Len() == 0 {
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+	return v
+}
+
+func TestDecodeEntryAcceptsEmptyPayload(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	raw := encodeEntry(nil, time.Minute, 0, now)
+	require.Len(t, raw, entryHeaderSize)
+
+	payload, _, ok := decodeEntry(raw, now.UnixNano())
+	require.True(t, ok)
+	require.NotNil(t, payload)
+	require.Empty(t, payload)
+
+	_, _, ok = decodeEntry(raw[:entryHeaderSize-1], now.UnixNano())
+	require.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package cache_manager
+
+import (
+	"errors"
+	"strings"
+)
+
+// Cache level names used in CacheError.Level and elsewhere.
+const (
+	LevelL1 = "L1"
+	LevelL2 = "L2"
+)
+
+var (
+	// ErrNotInitialized indicates a nil or unconfigured cache was used.
+	ErrNotInitialized = errors.New("cache not initialized")
+	// ErrSerializerMissing indicates serializer dependency absent.
+	ErrSerializerMissing = errors.New("serializer is required")
+	// ErrRedisClientMissing indicates NewRedisCache was given a nil client.
+	ErrRedisClientMissing = errors.New("redis client is required")
+	// ErrLevelOverrideNotAllowed indicates TargetL1/TargetL2 were set on a single-level cache.
+	ErrLevelOverrideNotAllowed = errors.New("level overrides not allowed: both L1 and L2 must be configured to use TargetL1/TargetL2 options")
+	// ErrNoLevelTargeted indicates the options disabled every cache level.
+	ErrNoLevelTargeted = errors.New("at least one cache level must be targeted")
+	// ErrLevelNotConfigured indicates an operation targeted a level that has no cache.
+	ErrLevelNotConfigured = errors.New("target level not configured")
+	// ErrModeMismatch indicates the configured mode does not match the provided caches.
+	ErrModeMismatch = errors.New("cache mode does not match configured levels")
+	// ErrFlushRateLimited indicates Flush or DeleteByPrefix exceeded MaxFlushPerMinute.
+	ErrFlushRateLimited = errors.New("flush rate limit exceeded")
+	// ErrL2Degraded indicates L2 was skipped because the cache is degraded to L1-only or
+	// its ConnectionNotifier reported a lost connection.
+	ErrL2Degraded = errors.New("l2 degraded, skipped")
+	// ErrL2RateLimited indicates an L2 write was skipped because
+	// MultiLevelConfig.L2RateLimiter denied it.
+	ErrL2RateLimited = errors.New("l2 write rate limited, skipped")
+	// ErrL2Throttled indicates an L2 operation was skipped because
+	// MultiLevelConfig.L2MaxConcurrency operations were already in flight.
+	ErrL2Throttled = errors.New("l2 concurrency limit reached, skipped")
+	// ErrReadOnly indicates a Set or Delete skipped a level protected by
+	// MultiLevelConfig.ReadOnly or ReadOnlyL2.
+	ErrReadOnly = errors.New("cache level is read-only")
+	// ErrSerialization indicates MultiLevelConfig.ValidateOnWrite rejected a serialized
+	// payload that does not decode back.
+	ErrSerialization = errors.New("serialized payload failed validation")
+	// ErrKeyCollision indicates two different keys hashed to the same stored key.
+	ErrKeyCollision = errors.New("cache key collision")
+	// ErrKeyNotFound indicates an operation that needs an existing entry, such as
+	// Rename, found none.
+	ErrKeyNotFound = errors.New("cache key not found")
+	// ErrCacheMiss is returned by GetE when the key is not cached. Match it with
+	// errors.Is.
+	ErrCacheMiss = errors.New("cache miss")
+	// ErrCacheClosed indicates the cache was used after Close.
+	ErrCacheClosed = errors.New("cache closed")
+)
+
+// CacheError describes a failed cache operation. Use errors.As to inspect it and
+// errors.Is to match the sentinel or backend error in Cause.
+type CacheError struct {
+	Op    string // operation, e.g. "get", "set", "delete", "new"
+	Level string // LevelL1, LevelL2, or empty when not level-specific
+	Key   string // cache key, empty when not key-specific
+	Cause error
+}
+
+func (e *CacheError) Error() string {
+	var b strings.Builder
+	b.WriteString("cache ")
+	b.WriteString(e.Op)
+	if e.Level != "" {
+		b.WriteString(" ")
+		b.WriteString(e.Level)
+	}
+	if e.Key != "" {
+		b.WriteString(" key=")
+		b.WriteString(e.Key)
+	}
+	if e.Cause != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Cause.Error())
+	}
+	return b.String()
+}
+
+func (e *CacheError) Unwrap() error {
+	return e.Cause
+}
+
+// wrapError annotates err with the operation context unless it already is a CacheError.
+func wrapError(op, level, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *CacheError
+	if errors.As(err, &ce) {
+		return err
+	}
+	return &CacheError{Op: op, Level: level, Key: key, Cause: err}
+}
@@ -0,0 +1,104 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// failingRawCache returns err from every operation.
+type failingRawCache struct {
+	err error
+}
+
+func (f failingRawCache) Get(context.Context, string) ([]byte, bool, error) { return nil, false, f.err }
+func (f failingRawCache) Set(context.Context, string, []byte, time.Duration) error {
+	return f.err
+}
+func (f failingRawCache) Delete(context.Context, string) error { return f.err }
+
+func TestCacheErrorForLevelOverrideOnSingleLevelCache(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	err = ml.Set(context.Background(), "user:1", "v", CacheOptions{TargetL2: BoolPtr(true)})
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "set", ce.Op)
+	require.Equal(t, "user:1", ce.Key)
+	require.ErrorIs(t, err, ErrLevelOverrideNotAllowed)
+}
+
+func TestCacheErrorCarriesLevelForBackendFailures(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("connection refused")
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), failingRawCache{err: boom}, JSONSerializer{}, MultiLevelConfig{
+		Mode: ModeBothLevels,
+	})
+	require.NoError(t, err)
+
+	_, err = ml.Get(context.Background(), "user:1", &struct{}{}, CacheOptions{})
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "get", ce.Op)
+	require.Equal(t, LevelL2, ce.Level)
+	require.Equal(t, "user:1", ce.Key)
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, "cache get L2 key=user:1: connection refused", err.Error())
+}
+
+func TestCacheErrorWhenBothLevelsFailOnSet(t *testing.T) {
+	t.Parallel()
+
+	l1Err, l2Err := errors.New("l1 full"), errors.New("l2 down")
+	ml, err := NewMultiLevelCache(failingRawCache{err: l1Err}, failingRawCache{err: l2Err}, JSONSerializer{}, MultiLevelConfig{
+		Mode: ModeBothLevels,
+	})
+	require.NoError(t, err)
+
+	err = ml.Set(context.Background(), "user:1", "v", CacheOptions{})
+	require.ErrorIs(t, err, l1Err)
+	require.ErrorIs(t, err, l2Err)
+
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "set", ce.Op)
+	require.Empty(t, ce.Level)
+}
+
+func TestCacheErrorFromConstructorAndUninitializedCaches(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), nil, nil, MultiLevelConfig{})
+	require.ErrorIs(t, err, ErrSerializerMissing)
+
+	_, err = NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.ErrorIs(t, err, ErrModeMismatch)
+
+	_, err = NewRedisCache(nil)
+	require.ErrorIs(t, err, ErrRedisClientMissing)
+
+	var bc *BigCache
+	_, _, err = bc.Get(context.Background(), "k")
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, LevelL1, ce.Level)
+	require.ErrorIs(t, err, ErrNotInitialized)
+}
+
+func TestBigCacheDeleteMissingKeyIsNotAnError(t *testing.T) {
+	t.Parallel()
+
+	bc, err := NewBigCache(context.Background(), BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	require.NoError(t, bc.Delete(context.Background(), "missing"))
+}
@@ -0,0 +1,67 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+)
+
+// contentHashMagic prefixes payloads written with MultiLevelConfig.ContentHashes and is
+// followed by contentHashSize hash bytes. Payloads without it are read as-is.
+var contentHashMagic = []byte{0x00, 'h', 's', 'h'}
+
+// contentHashSize is how many bytes of the payload's SHA-256 are kept.
+const contentHashSize = 16
+
+// EntryMetadata describes a cached entry found by GetWithMetadata.
+type EntryMetadata struct {
+	// ETag is a quoted strong entity tag derived from the payload written by Set, or
+	// empty when the entry was written without MultiLevelConfig.ContentHashes.
+	ETag string
+	// Level is the level the entry was read from.
+	Level string
+	// Stale is set when an expired L1 copy was served, see ServeStaleOnError.
+	Stale bool
+}
+
+// MetadataGetter is implemented by caches that can return entry metadata with a Get.
+type MetadataGetter interface {
+	GetWithMetadata(ctx context.Context, key string, dest any, opts CacheOptions) (EntryMetadata, bool, error)
+}
+
+// GetWithMetadata is Get that also returns the entry's metadata. With a nil dest the
+// payload is not decoded, L1 is only warmed when no re-encoding is needed and the
+// Loader is not called, so it is a cheap existence and ETag check.
+func (m *MultiLevelCache) GetWithMetadata(ctx context.Context, key string, dest any, opts CacheOptions) (meta EntryMetadata, found bool, err error) {
+	if m == nil {
+		return EntryMetadata{}, false, &CacheError{Op: "get", Key: key, Cause: ErrNotInitialized}
+	}
+	defer m.recoverPanic("get", key, &err)
+	return m.get(ctx, key, dest, opts)
+}
+
+// withContentHash frames payload with the hash Set computed for it.
+func withContentHash(sum payloadSum, payload []byte) []byte {
+	framed := make([]byte, 0, len(contentHashMagic)+contentHashSize+len(payload))
+	framed = append(framed, contentHashMagic...)
+	framed = append(framed, sum[:contentHashSize]...)
+	return append(framed, payload...)
+}
+
+// splitContentHash returns the hash framed by withContentHash (nil if absent) and the
+// payload behind it.
+func splitContentHash(data []byte) ([]byte, []byte) {
+	headerSize := len(contentHashMagic) + contentHashSize
+	if len(data) < headerSize || !bytes.HasPrefix(data, contentHashMagic) {
+		return nil, data
+	}
+	return data[len(contentHashMagic):headerSize], data[headerSize:]
+}
+
+// etagOf formats a stored content hash as an HTTP entity tag.
+func etagOf(hash []byte) string {
+	if hash == nil {
+		return ""
+	}
+	return `"` + hex.EncodeToString(hash) + `"`
+}
@@ -0,0 +1,110 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentHashETagStableAcrossLevelsAndWarmup(t *testing.T) {
+	t.Parallel()
+
+	for name, cfg := range map[string]MultiLevelConfig{
+		"shared serializer": {ContentHashes: true},
+		"split serializers": {ContentHashes: true, L1Serializer: GobSerializer{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ml, l1, _ := newTestMultiLevelCache(t, cfg)
+			ctx := context.Background()
+			require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1, Name: "Ada"}, CacheOptions{}))
+
+			var got loadedUser
+			fromL1, found, err := ml.GetWithMetadata(ctx, "user:1", &got, CacheOptions{})
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, LevelL1, fromL1.Level)
+			require.Regexp(t, `^"[0-9a-f]{32}"$`, fromL1.ETag)
+			require.Equal(t, "Ada", got.Name)
+
+			require.NoError(t, l1.Delete(ctx, "user:1"))
+			fromL2, found, err := ml.GetWithMetadata(ctx, "user:1", &got, CacheOptions{})
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, LevelL2, fromL2.Level)
+			require.Equal(t, fromL1.ETag, fromL2.ETag)
+
+			warmed, found, err := ml.GetWithMetadata(ctx, "user:1", &got, CacheOptions{})
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, LevelL1, warmed.Level)
+			require.Equal(t, fromL1.ETag, warmed.ETag)
+			require.Equal(t, "Ada", got.Name)
+		})
+	}
+}
+
+func TestContentHashETagChangesWithPayload(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{ContentHashes: true})
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "k", "one", CacheOptions{}))
+	first, _, err := ml.GetWithMetadata(ctx, "k", nil, CacheOptions{})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(ctx, "k", "two", CacheOptions{}))
+	second, found, err := ml.GetWithMetadata(ctx, "k", nil, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotEqual(t, first.ETag, second.ETag)
+
+	// Plain Get and Inspect see the payload, not the hash envelope.
+	var got string
+	_, err = ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "two", got)
+	info, _, err := ml.Inspect(ctx, "k")
+	require.NoError(t, err)
+	require.JSONEq(t, `"two"`, string(info.Value))
+}
+
+func TestGetWithMetadataNilDestSkipsDecodingAndLoader(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{
+		ContentHashes: true,
+		Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+			t.Fatal("loader must not run for a metadata-only lookup")
+			return nil, 0, nil
+		}),
+	})
+	ctx := context.Background()
+
+	// Not valid JSON: decoding would fail.
+	require.NoError(t, l1.Set(ctx, "raw", withContentHash(payloadHash([]byte("x"), nil), []byte("{")), time.Minute))
+	meta, found, err := ml.GetWithMetadata(ctx, "raw", nil, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotEmpty(t, meta.ETag)
+
+	_, found, err = ml.GetWithMetadata(ctx, "missing", nil, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestGetWithMetadataWithoutContentHashes(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+
+	meta, found, err := ml.GetWithMetadata(ctx, "k", nil, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Empty(t, meta.ETag)
+}
@@ -0,0 +1,105 @@
+package cache_manager
+
+import "sync"
+
+// Event is a message published on an EventBus, e.g. a CacheDeleteEvent.
+type Event any
+
+// CacheDeleteEvent is published by MultiLevelCache.Delete on the topic
+// "cache.delete.<mode>", e.g. "cache.delete.both-levels", of MultiLevelConfig.EventBus.
+type CacheDeleteEvent struct {
+	// Key is the logical key, without InstanceName or Version.
+	Key string
+
+	origin *MultiLevelCache // the publishing cache, which skips its own events
+}
+
+// EventBus delivers events between the caches of one process, e.g. so a Delete on one
+// MultiLevelCache evicts the key from the L1 of the others. Handlers run synchronously in
+// Publish, so by the time a Delete returns every subscriber has evicted the key. The zero
+// value is ready to use.
+type EventBus struct {
+	mu     sync.RWMutex
+	topics map[string]map[uint64]func(Event)
+	nextID uint64
+}
+
+// NewEventBus returns an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe calls handler for every event published on topic until cancel is called.
+// cancel may be called more than once.
+func (b *EventBus) Subscribe(topic string, handler func(Event)) (cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.topics == nil {
+		b.topics = make(map[string]map[uint64]func(Event))
+	}
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[uint64]func(Event))
+	}
+	id := b.nextID
+	b.nextID++
+	b.topics[topic][id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.topics[topic], id)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+	}
+}
+
+// Publish calls the handlers subscribed to topic with event and returns once they have
+// all returned. Handlers may subscribe, cancel or publish themselves.
+func (b *EventBus) Publish(topic string, event Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.topics[topic]))
+	for _, h := range b.topics[topic] {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(event)
+	}
+}
+
+// deleteTopic is the EventBus topic Delete publishes on for mode.
+func deleteTopic(mode CacheMode) string {
+	return "cache.delete." + mode.String()
+}
+
+// subscribeDeletes evicts from L1 the keys deleted by the other caches on m.bus, until
+// Close.
+func (m *MultiLevelCache) subscribeDeletes() {
+	if m.bus == nil || m.l1 == nil {
+		return
+	}
+	m.busCancel = m.bus.Subscribe(deleteTopic(m.mode), func(ev Event) {
+		del, ok := ev.(CacheDeleteEvent)
+		if !ok || del.origin == m {
+			return
+		}
+		key := m.storeKey(del.Key)
+		m.changes.forget(key)
+		if err := m.l1.Delete(m.background, key); err != nil {
+			m.log.logger.Warn("cache bus delete l1 failed", "key", del.Key, "error", err)
+			return
+		}
+		m.l1Budget.forget(key)
+		m.log.Debug(m.background, "cache bus delete l1", "key", key)
+	})
+}
+
+// publishDelete tells the other caches on m.bus that key (a store key) was deleted.
+func (m *MultiLevelCache) publishDelete(key string) {
+	if m.bus == nil {
+		return
+	}
+	m.bus.Publish(deleteTopic(m.mode), CacheDeleteEvent{Key: m.logicalKey(key), origin: m})
+}
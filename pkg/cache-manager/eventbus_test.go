@@ -0,0 +1,69 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBusSubscribePublish(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus()
+	var got []Event
+	cancel := bus.Subscribe("topic", func(ev Event) { got = append(got, ev) })
+	bus.Publish("topic", "a")
+	bus.Publish("other", "b")
+	cancel()
+	cancel()
+	bus.Publish("topic", "c")
+	require.Equal(t, []Event{"a"}, got)
+}
+
+func TestEventBusDeleteEvictsOtherL1(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bus := NewEventBus()
+	l2 := newMemoryRawCache()
+	newCache := func(mode CacheMode) (*MultiLevelCache, *memoryRawCache) {
+		l1 := newMemoryRawCache()
+		var l2Level RawCache = l2
+		if mode == ModeL1Only {
+			l2Level = nil
+		}
+		ml, err := NewMultiLevelCache(l1, l2Level, JSONSerializer{}, MultiLevelConfig{
+			Mode:         mode,
+			WarmupTTL:    time.Minute,
+			L1DefaultTTL: time.Minute,
+			L2DefaultTTL: time.Minute,
+			EventBus:     bus,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ml.Close(ctx) })
+		return ml, l1
+	}
+	a, _ := newCache(ModeBothLevels)
+	b, bL1 := newCache(ModeBothLevels)
+	other, otherL1 := newCache(ModeL1Only)
+
+	require.NoError(t, a.Set(ctx, "user:1", "ada", CacheOptions{}))
+	var got string
+	res, err := b.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+	require.True(t, bL1.has("user:1"), "the L2 hit warmed b's L1")
+	require.NoError(t, other.Set(ctx, "user:1", "local", CacheOptions{}))
+
+	require.NoError(t, a.Delete(ctx, "user:1"))
+	require.False(t, bL1.has("user:1"), "a's delete evicted b's L1")
+	require.True(t, otherL1.has("user:1"), "caches of another mode are on another topic")
+
+	// A closed cache no longer listens
+	require.NoError(t, b.Set(ctx, "user:1", "ada", CacheOptions{}))
+	require.NoError(t, b.Close(ctx))
+	require.NoError(t, a.Delete(ctx, "user:1"))
+	require.True(t, bL1.has("user:1"))
+}
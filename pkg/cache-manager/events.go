@@ -0,0 +1,145 @@
+package cache_manager
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// eventBufferSize bounds the in-process event channel. Events published while the
+// buffer is full are dropped and counted rather than blocking cache calls.
+const eventBufferSize = 1000
+
+// Event results reported in CacheEvent.Result.
+const (
+	EventHit   = "hit"
+	EventMiss  = "miss"
+	EventOK    = "ok"
+	EventError = "error"
+)
+
+// CacheEvent describes a single Get, Set or Delete outcome on one cache level.
+// Level is empty when the outcome is not tied to a level, e.g. an overall miss.
+type CacheEvent struct {
+	Op     string    `json:"op"`
+	Key    string    `json:"key"`
+	Level  string    `json:"level,omitempty"`
+	Result string    `json:"result"`
+	TS     time.Time `json:"ts"`
+	// Version is MultiLevelConfig.Version of the emitting cache; Key excludes it.
+	Version string `json:"version,omitempty"`
+	// RequestID identifies the request that caused the operation, taken from the
+	// caller's context by MultiLevelConfig.RequestID.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// eventStream is a non-blocking, single-consumer queue of cache events.
+type eventStream struct {
+	ch      chan CacheEvent
+	dropped atomic.Uint64
+}
+
+func newEventStream(size int) *eventStream {
+	return &eventStream{ch: make(chan CacheEvent, size)}
+}
+
+func (s *eventStream) publish(ev CacheEvent) {
+	select {
+	case s.ch <- ev:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// emit reports a cache outcome to the metrics collector and audit logger and publishes
+// it as an event. Only the audit logger may block the caller.
+func (m *MultiLevelCache) emit(ctx context.Context, op, key, level, result string) {
+	if m.metrics != nil {
+		switch result {
+		case EventHit:
+			m.metrics.RecordHit(op, level)
+		case EventMiss:
+			m.metrics.RecordMiss(op, level)
+		case EventError:
+			m.metrics.RecordError(op, level)
+		}
+	}
+	if m.events == nil && m.audit == nil {
+		return
+	}
+	ev := CacheEvent{Op: op, Key: m.logicalKey(key), Level: level, Result: result, TS: m.clock.Now(), Version: m.version, RequestID: m.log.requestID(ctx)}
+	if m.audit != nil {
+		m.audit.Audit(ev)
+	}
+	if m.events != nil {
+		m.events.publish(ev)
+	}
+}
+
+// Events returns the channel of cache events. Each event is delivered to exactly one
+// receiver, so concurrent consumers split the stream between them.
+func (m *MultiLevelCache) Events() <-chan CacheEvent {
+	return m.events.ch
+}
+
+// DroppedEvents reports how many events were discarded because the buffer was full.
+func (m *MultiLevelCache) DroppedEvents() uint64 {
+	return m.events.dropped.Load()
+}
+
+// NewEventStreamHandler streams cache events as Server-Sent Events. Requests must carry
+// "Authorization: Bearer <token>"; an empty token rejects every request.
+func NewEventStreamHandler(m *MultiLevelCache, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validAdminToken(r, token) {
+			writeAdminError(w, http.StatusUnauthorized, "missing or invalid admin token")
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeAdminError(w, http.StatusInternalServerError, "streaming unsupported")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		events := m.Events()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev := <-events:
+				payload, err := json.Marshal(ev)
+				if err != nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// validAdminToken checks the bearer token in constant time.
+func validAdminToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -0,0 +1,84 @@
+package cache_manager
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamHandlerStreamsGet(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	srv := httptest.NewServer(NewEventStreamHandler(ml, "secret"))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var dest map[string]string
+	res, err := ml.Get(ctx, "user:1", &dest, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var ev CacheEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &ev))
+		require.Equal(t, "get", ev.Op)
+		require.Equal(t, "user:1", ev.Key)
+		require.Equal(t, EventMiss, ev.Result)
+		require.False(t, ev.TS.IsZero())
+		return
+	}
+	t.Fatalf("no event received: %v", scanner.Err())
+}
+
+func TestEventStreamHandlerRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	for _, token := range []string{"", "secret"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/cache/events", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		NewEventStreamHandler(ml, token).ServeHTTP(rec, req)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestMultiLevelCacheDropsEventsWhenFull(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+
+	for i := 0; i < eventBufferSize; i++ {
+		ml.emit(ctx, "get", "k", LevelL1, EventHit)
+	}
+	require.Zero(t, ml.DroppedEvents())
+
+	require.NoError(t, ml.Delete(ctx, "k"))
+	require.Equal(t, uint64(2), ml.DroppedEvents())
+}
@@ -0,0 +1,116 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CacheFactory creates one MultiLevelCache per namespace and mode on top of a shared
+// L1 and L2, e.g. one per tenant, so tenants share the BigCache and Redis
+// connections but not their keys.
+type CacheFactory struct {
+	l1, l2     RawCache
+	serializer Serializer
+	base       MultiLevelConfig
+
+	mu        sync.Mutex // serialises Create, so an instance is built once
+	instances sync.Map   // factoryKey -> *factoryInstance
+}
+
+// factoryKey identifies a Create configuration.
+type factoryKey struct {
+	namespace string
+	mode      CacheMode
+}
+
+type factoryInstance struct {
+	cache        *MultiLevelCache
+	l1TTL, l2TTL time.Duration
+}
+
+// NewCacheFactory returns a factory building caches over l1 and l2 from base. Either
+// level may be nil if no mode needing it is created.
+func NewCacheFactory(l1, l2 RawCache, serializer Serializer, base MultiLevelConfig) *CacheFactory {
+	return &CacheFactory{l1: l1, l2: l2, serializer: serializer, base: base}
+}
+
+// Create returns the cache for namespace in mode, building it on the first call. The
+// namespace becomes the InstanceName, so each namespace's keys are kept apart in the
+// shared levels, and the Namespace used in metrics unless base sets one. l1TTL and l2TTL
+// are the default TTLs; asking again for the same namespace and mode with other TTLs is
+// an error, as the existing cache would not honour them.
+func (f *CacheFactory) Create(namespace string, mode CacheMode, l1TTL, l2TTL time.Duration) (*MultiLevelCache, error) {
+	if namespace == "" {
+		return nil, &CacheError{Op: "new", Cause: errors.New("namespace is required")}
+	}
+	key := factoryKey{namespace: namespace, mode: mode}
+	if v, ok := f.instances.Load(key); ok {
+		return v.(*factoryInstance).reuse(key, l1TTL, l2TTL)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if v, ok := f.instances.Load(key); ok {
+		return v.(*factoryInstance).reuse(key, l1TTL, l2TTL)
+	}
+
+	cfg := f.base
+	cfg.Mode = mode
+	cfg.InstanceName = namespace
+	if cfg.Namespace == "" {
+		cfg.Namespace = namespace
+	}
+	cfg.L1DefaultTTL = l1TTL
+	cfg.L2DefaultTTL = l2TTL
+	cfg.CloseLevels = false // the levels are shared
+	l1, l2 := f.l1, f.l2
+	switch mode {
+	case ModeL1Only:
+		l2 = nil
+	case ModeL2Only:
+		l1 = nil
+	}
+	m, err := NewMultiLevelCache(l1, l2, f.serializer, cfg)
+	if err != nil {
+		return nil, err
+	}
+	f.instances.Store(key, &factoryInstance{cache: m, l1TTL: l1TTL, l2TTL: l2TTL})
+	return m, nil
+}
+
+func (i *factoryInstance) reuse(key factoryKey, l1TTL, l2TTL time.Duration) (*MultiLevelCache, error) {
+	if i.l1TTL != l1TTL || i.l2TTL != l2TTL {
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("namespace %q (%s) already created with TTLs %s/%s", key.namespace, key.mode, i.l1TTL, i.l2TTL)}
+	}
+	return i.cache, nil
+}
+
+// List returns the sorted namespaces with at least one cache.
+func (f *CacheFactory) List() []string {
+	seen := make(map[string]struct{})
+	f.instances.Range(func(k, _ any) bool {
+		seen[k.(factoryKey).namespace] = struct{}{}
+		return true
+	})
+	out := make([]string, 0, len(seen))
+	for ns := range seen {
+		out = append(out, ns)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Close closes every cache the factory created. The shared levels are left open for the
+// caller to close.
+func (f *CacheFactory) Close(ctx context.Context) error {
+	var errs []error
+	f.instances.Range(func(_, v any) bool {
+		errs = append(errs, v.(*factoryInstance).cache.Close(ctx))
+		return true
+	})
+	return errors.Join(errs...)
+}
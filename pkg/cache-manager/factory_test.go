@@ -0,0 +1,72 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheFactoryReusesInstances(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	f := NewCacheFactory(l1, l2, JSONSerializer{}, MultiLevelConfig{})
+	t.Cleanup(func() { _ = f.Close(ctx) })
+
+	a, err := f.Create("tenant-a", ModeBothLevels, time.Minute, time.Hour)
+	require.NoError(t, err)
+	again, err := f.Create("tenant-a", ModeBothLevels, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.Same(t, a, again)
+
+	aL1, err := f.Create("tenant-a", ModeL1Only, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.NotSame(t, a, aL1)
+	b, err := f.Create("tenant-b", ModeL2Only, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	_, err = f.Create("tenant-a", ModeBothLevels, time.Second, time.Hour)
+	require.ErrorContains(t, err, "already created")
+	_, err = f.Create("", ModeBothLevels, time.Minute, time.Hour)
+	require.Error(t, err)
+	require.Equal(t, []string{"tenant-a", "tenant-b"}, f.List())
+
+	// Namespaces keep their keys apart in the shared levels and use their TTLs.
+	require.NoError(t, a.Set(ctx, "user:1", "a", CacheOptions{}))
+	require.NoError(t, b.Set(ctx, "user:1", "b", CacheOptions{}))
+	require.Equal(t, time.Minute, l1.ttl["tenant-a:user:1"])
+	require.Equal(t, time.Hour, l2.ttl["tenant-b:user:1"])
+	require.False(t, l1.has("tenant-b:user:1"), "an L2-only cache does not write L1")
+
+	var got string
+	res, err := aL1.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "a", got)
+}
+
+func TestCacheFactoryConcurrentCreate(t *testing.T) {
+	t.Parallel()
+
+	f := NewCacheFactory(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{})
+	t.Cleanup(func() { _ = f.Close(context.Background()) })
+
+	caches := make(chan *MultiLevelCache, 16)
+	for range cap(caches) {
+		go func() {
+			m, err := f.Create("tenant", ModeBothLevels, time.Minute, time.Minute)
+			if err != nil {
+				m = nil
+			}
+			caches <- m
+		}()
+	}
+	first := <-caches
+	require.NotNil(t, first)
+	for range cap(caches) - 1 {
+		require.Same(t, first, <-caches)
+	}
+}
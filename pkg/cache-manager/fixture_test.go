@@ -0,0 +1,120 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFixture collects cache entries for a test case and seeds them into fresh
+// in-memory levels on Build.
+type TestFixture struct {
+	entries []fixtureEntry
+}
+
+type fixtureEntry struct {
+	key   string
+	value any
+	toL1  bool
+	toL2  bool
+}
+
+// AddL1 seeds key in L1 only.
+func (f *TestFixture) AddL1(key string, value any) *TestFixture {
+	f.entries = append(f.entries, fixtureEntry{key: key, value: value, toL1: true})
+	return f
+}
+
+// AddL2 seeds key in L2 only.
+func (f *TestFixture) AddL2(key string, value any) *TestFixture {
+	f.entries = append(f.entries, fixtureEntry{key: key, value: value, toL2: true})
+	return f
+}
+
+// AddBoth seeds key in both levels.
+func (f *TestFixture) AddBoth(key string, value any) *TestFixture {
+	f.entries = append(f.entries, fixtureEntry{key: key, value: value, toL1: true, toL2: true})
+	return f
+}
+
+// Build creates a both-levels cache over two memoryRawCaches, writes the collected entries
+// JSON-encoded with a one-minute TTL, and returns the cache and its levels for assertions.
+func (f *TestFixture) Build(t *testing.T) (*MultiLevelCache, *memoryRawCache, *memoryRawCache) {
+	t.Helper()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	for _, e := range f.entries {
+		data, err := json.Marshal(e.value)
+		require.NoError(t, err)
+		if e.toL1 {
+			require.NoError(t, l1.Set(ctx, e.key, data, time.Minute))
+		}
+		if e.toL2 {
+			require.NoError(t, l2.Set(ctx, e.key, data, time.Minute))
+		}
+	}
+	return ml, l1, l2
+}
+
+func TestTestFixtureSeedsLevels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		fixture   *TestFixture
+		key       string
+		wantValue string
+		wantL1    bool
+	}{
+		{
+			name:      "L1 entry is served directly",
+			fixture:   new(TestFixture).AddL1("user:1", "ada").AddL2("user:2", "grace"),
+			key:       "user:1",
+			wantValue: "ada",
+			wantL1:    true,
+		},
+		{
+			name:      "L2 entry warms L1",
+			fixture:   new(TestFixture).AddL1("user:1", "ada").AddL2("user:2", "grace"),
+			key:       "user:2",
+			wantValue: "grace",
+			wantL1:    true,
+		},
+		{
+			name:      "both levels",
+			fixture:   new(TestFixture).AddBoth("user:3", "linus"),
+			key:       "user:3",
+			wantValue: "linus",
+			wantL1:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ml, l1, _ := tt.fixture.Build(t)
+			var got string
+			res, err := ml.Get(context.Background(), tt.key, &got, CacheOptions{})
+			require.NoError(t, err)
+			require.True(t, res.Found)
+			require.Equal(t, tt.wantValue, got)
+			require.Equal(t, tt.wantL1, l1.has(tt.key))
+		})
+	}
+}
+
+func TestTestFixtureTwoEntryState(t *testing.T) {
+	t.Parallel()
+
+	_, l1, l2 := new(TestFixture).AddL1("user:1", "ada").AddL2("user:2", "grace").Build(t)
+
+	require.True(t, l1.has("user:1"))
+	require.False(t, l2.has("user:1"))
+	require.False(t, l1.has("user:2"))
+	require.True(t, l2.has("user:2"))
+}
@@ -0,0 +1,86 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ForgetReceipt records what ForgetKey removed, for a data-erasure request's paper trail.
+type ForgetReceipt struct {
+	Key       string    `json:"key"`
+	DeletedAt time.Time `json:"deleted_at"`
+	L1Deleted bool      `json:"l1_deleted"`
+	L2Deleted bool      `json:"l2_deleted"`
+}
+
+// Exister is implemented by raw caches that can confirm whether a key is present.
+type Exister interface {
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// errStillPresent reports a key that a level still holds after it was deleted.
+var errStillPresent = errors.New("key still present after delete")
+
+// ForgetKey evicts key from every configured level for a data-erasure (e.g. GDPR)
+// request and reports each outcome to the AuditLogger as a "forget" event. Unlike
+// Delete it always reaches L2, even when the cache is degraded, and verifies the
+// removal on levels implementing Exister.
+//
+// This is best-effort cache eviction, not secure erasure: BigCache and Redis free the
+// bytes but neither overwrites them, and keys written under another
+// MultiLevelConfig.Version are not touched.
+func (m *MultiLevelCache) ForgetKey(ctx context.Context, key string) (ForgetReceipt, error) {
+	if m == nil {
+		return ForgetReceipt{}, &CacheError{Op: "forget", Key: key, Cause: ErrNotInitialized}
+	}
+	receipt := ForgetReceipt{Key: key}
+	key = m.storeKey(key)
+
+	unlock := m.keyLocks.Lock(key)
+	defer unlock()
+	m.changes.forget(key)
+
+	var errs []error
+	if m.l1 != nil {
+		if err := forgetFrom(ctx, m.l1, key, m.l1.Delete); err != nil {
+			errs = append(errs, wrapError("forget", LevelL1, key, err))
+			m.emit(ctx, "forget", key, LevelL1, EventError)
+		} else {
+			receipt.L1Deleted = true
+			m.emit(ctx, "forget", key, LevelL1, EventOK)
+		}
+	}
+	if m.l2 != nil {
+		if err := forgetFrom(ctx, m.l2, key, m.deleteL2); err != nil {
+			errs = append(errs, wrapError("forget", LevelL2, key, err))
+			m.emit(ctx, "forget", key, LevelL2, EventError)
+		} else {
+			receipt.L2Deleted = true
+			m.emit(ctx, "forget", key, LevelL2, EventOK)
+		}
+	}
+
+	receipt.DeletedAt = m.clock.Now()
+	m.log.Debug(ctx, "cache forget key", "key", key, "l1_deleted", receipt.L1Deleted, "l2_deleted", receipt.L2Deleted)
+	return receipt, errors.Join(errs...)
+}
+
+// forgetFrom deletes key with del and, when level is an Exister, checks it is gone.
+func forgetFrom(ctx context.Context, level RawCache, key string, del func(context.Context, string) error) error {
+	if err := del(ctx, key); err != nil {
+		return err
+	}
+	exister, ok := level.(Exister)
+	if !ok {
+		return nil
+	}
+	exists, err := exister.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errStillPresent
+	}
+	return nil
+}
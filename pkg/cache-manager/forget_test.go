@@ -0,0 +1,114 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditLogger struct {
+	mu     sync.Mutex
+	events []CacheEvent
+}
+
+func (a *recordingAuditLogger) Audit(ev CacheEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, ev)
+}
+
+func TestForgetKeyRemovesBothLevelsAndAudits(t *testing.T) {
+	t.Parallel()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+
+	clock := newFakeClock()
+	audit := &recordingAuditLogger{}
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:    ModeBothLevels,
+		Audit:   audit,
+		Clock:   clock,
+		Version: "v2",
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+
+	receipt, err := ml.ForgetKey(ctx, "user:1")
+	require.NoError(t, err)
+	require.Equal(t, ForgetReceipt{Key: "user:1", DeletedAt: clock.Now(), L1Deleted: true, L2Deleted: true}, receipt)
+	require.False(t, l1.has("v2:user:1"))
+	require.False(t, mr.Exists("v2:user:1"))
+
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+	var forgets []CacheEvent
+	for _, ev := range audit.events {
+		if ev.Op == "forget" {
+			forgets = append(forgets, ev)
+		}
+	}
+	require.Len(t, forgets, 2)
+	for _, ev := range forgets {
+		require.Equal(t, "user:1", ev.Key)
+		require.Equal(t, EventOK, ev.Result)
+	}
+}
+
+// stickyRawCache ignores deletes and reports every key as present.
+type stickyRawCache struct{ *memoryRawCache }
+
+func (stickyRawCache) Delete(context.Context, string) error         { return nil }
+func (stickyRawCache) Exists(context.Context, string) (bool, error) { return true, nil }
+func (c stickyRawCache) Set(ctx context.Context, k string, v []byte, ttl time.Duration) error {
+	return c.memoryRawCache.Set(ctx, k, v, ttl)
+}
+
+func TestForgetKeyReportsLevelsThatKeepTheKey(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), stickyRawCache{newMemoryRawCache()}, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+
+	receipt, err := ml.ForgetKey(context.Background(), "user:1")
+	require.ErrorIs(t, err, errStillPresent)
+	require.True(t, receipt.L1Deleted)
+	require.False(t, receipt.L2Deleted)
+}
+
+func TestForgetKeyReachesDegradedL2(t *testing.T) {
+	t.Parallel()
+
+	l2 := &outageRawCache{memoryRawCache: newMemoryRawCache()}
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:        ModeBothLevels,
+		Degradation: DegradationConfig{After: time.Nanosecond, ProbeInterval: time.Hour},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	l2.down.Store(true)
+	var got string
+	for ml.L2State() != L2Degraded {
+		_, _ = ml.Get(ctx, "k", &got, CacheOptions{})
+	}
+
+	before := l2.calls.Load()
+	_, err = ml.ForgetKey(ctx, "k")
+	require.ErrorIs(t, err, errRedisOutage)
+	require.Greater(t, l2.calls.Load(), before)
+}
@@ -0,0 +1,40 @@
+package cache_manager
+
+import "context"
+
+// GetE is Get in error style: it returns nil on a hit and ErrCacheMiss on a miss, so a
+// miss cannot be mistaken for success by ignoring a bool. The two styles are
+//
+//	res, err := c.Get(ctx, key, &user, opts)
+//	if err != nil { ... }
+//	if !res.Found { ... }
+//
+//	err := c.GetE(ctx, key, &user, opts)
+//	if errors.Is(err, cache_manager.ErrCacheMiss) { ... } else if err != nil { ... }
+//
+// ErrCacheMiss is returned as is, never wrapped. A value filled by the Loader is a hit.
+func (m *MultiLevelCache) GetE(ctx context.Context, key string, dest any, opts CacheOptions) error {
+	if m == nil {
+		return &CacheError{Op: "get", Key: key, Cause: ErrNotInitialized}
+	}
+	_, found, err := m.get(ctx, key, dest, opts)
+	return missAsError(found, err)
+}
+
+// GetE is MultiLevelCache.GetE for any Cache: it calls cache.Get and returns
+// ErrCacheMiss when nothing was found.
+func GetE(ctx context.Context, cache Cache, key string, dest any, opts CacheOptions) error {
+	res, err := cache.Get(ctx, key, dest, opts)
+	return missAsError(res.Found, err)
+}
+
+// missAsError folds the found flag of a Get into its error.
+func missAsError(found bool, err error) error {
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrCacheMiss
+	}
+	return nil
+}
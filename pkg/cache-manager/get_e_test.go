@@ -0,0 +1,118 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEStyles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+
+	// Bool style.
+	var got string
+	res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	res, err = ml.Get(ctx, "missing", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+
+	// Error style.
+	got = ""
+	require.NoError(t, ml.GetE(ctx, "k", &got, CacheOptions{}))
+	require.Equal(t, "v", got)
+	require.ErrorIs(t, ml.GetE(ctx, "missing", &got, CacheOptions{}), ErrCacheMiss)
+	require.ErrorIs(t, GetE(ctx, ml, "missing", &got, CacheOptions{}), ErrCacheMiss)
+}
+
+func TestGetEReturnsCacheErrorsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("redis down")
+	ml, err := NewMultiLevelCache(nil, failingRawCache{err: errDown}, JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only})
+	require.NoError(t, err)
+
+	var got string
+	err = ml.GetE(context.Background(), "k", &got, CacheOptions{})
+	require.ErrorIs(t, err, errDown)
+	require.NotErrorIs(t, err, ErrCacheMiss, "a failed read is not a miss")
+}
+
+func TestGetELoaderFillIsAHit(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{
+		Mode:   ModeL1Only,
+		Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) { return "loaded", 0, nil }),
+	})
+	require.NoError(t, err)
+
+	var got string
+	require.NoError(t, ml.GetE(context.Background(), "k", &got, CacheOptions{}))
+	require.Equal(t, "loaded", got)
+}
+
+// TestErrCacheMissIsNeverWrappedOpaquely is a vet-style check over the package sources:
+// any fmt.Errorf that formats ErrCacheMiss must use %w, so errors.Is keeps matching.
+func TestErrCacheMissIsNeverWrappedOpaquely(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		require.NoError(t, err)
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isSelector(call.Fun, "fmt", "Errorf") || len(call.Args) < 2 {
+				return true
+			}
+			mentions := false
+			for _, arg := range call.Args[1:] {
+				ast.Inspect(arg, func(n ast.Node) bool {
+					if id, ok := n.(*ast.Ident); ok && id.Name == "ErrCacheMiss" {
+						mentions = true
+					}
+					return true
+				})
+			}
+			if !mentions {
+				return true
+			}
+			var format string
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+				format, _ = strconv.Unquote(lit.Value)
+			}
+			require.True(t, strings.Contains(format, "%w"),
+				"%s: ErrCacheMiss must be wrapped with %%w", fset.Position(call.Pos()))
+			return true
+		})
+	}
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg
+}
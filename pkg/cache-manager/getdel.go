@@ -0,0 +1,102 @@
+package cache_manager
+
+import (
+	"context"
+	"time"
+)
+
+// GetDeleter is implemented by raw caches that can read and remove a key in one atomic
+// step (Redis GETDEL).
+type GetDeleter interface {
+	GetDel(ctx context.Context, key string) ([]byte, bool, error)
+}
+
+// GetAndDelete reads key into dest and invalidates it, so that of several concurrent
+// callers only one receives the value. It is intended for one-shot tokens.
+//
+// When L2 is targeted it is the source of truth: the value is popped with GetDel (GETDEL
+// on Redis) and any L1 copy is dropped without being served, so cross-instance atomicity
+// is exactly as strong as the L2 GETDEL. When only L1 is targeted, get and delete run
+// under a per-key lock, which is atomic within this process only. Raw caches without
+// GetDeleter fall back to a locked Get followed by Delete.
+func (m *MultiLevelCache) GetAndDelete(ctx context.Context, key string, dest any, opts CacheOptions) (bool, error) {
+	if m == nil {
+		return false, &CacheError{Op: "getdel", Key: key, Cause: ErrNotInitialized}
+	}
+	defer m.observeLatency("getdel", time.Now())
+	key = m.storeKey(key)
+
+	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
+		return false, &CacheError{Op: "getdel", Key: key, Cause: ErrLevelOverrideNotAllowed}
+	}
+	checkL1, checkL2 := m.determineCacheLevel()
+	checkL1, checkL2 = m.applyEndpointLevelOverrides(opts, checkL1, checkL2)
+	if !checkL1 && !checkL2 {
+		return false, &CacheError{Op: "getdel", Key: key, Cause: ErrNoLevelTargeted}
+	}
+	if checkL1 && m.l1 == nil {
+		return false, &CacheError{Op: "getdel", Level: LevelL1, Key: key, Cause: ErrLevelNotConfigured}
+	}
+	if checkL2 && m.l2 == nil {
+		return false, &CacheError{Op: "getdel", Level: LevelL2, Key: key, Cause: ErrLevelNotConfigured}
+	}
+
+	unlock := m.keyLocks.Lock(key)
+	defer unlock()
+	m.changes.forget(key)
+
+	if checkL2 {
+		if m.l1 != nil {
+			if err := m.l1.Delete(ctx, key); err != nil {
+				m.log.Debug(ctx, "cache getdel l1 delete failed, continuing", "key", key, "error", err)
+			}
+		}
+		data, ok, err := popRaw(ctx, m.l2, key)
+		if err == nil && ok {
+			popped := data
+			if data, ok, err = m.resolveChunks(ctx, key, popped); err == nil && ok {
+				m.deleteChunks(ctx, key, popped)
+				data, err = decompressL2(data)
+			}
+		}
+		return m.finishPop(ctx, key, LevelL2, m.l2Serializer, data, ok, err, dest)
+	}
+
+	data, ok, err := popRaw(ctx, m.l1, key)
+	return m.finishPop(ctx, key, LevelL1, m.l1Serializer, data, ok, err, dest)
+}
+
+// popRaw atomically reads and removes key when cache supports it, otherwise it falls
+// back to Get followed by Delete. Callers hold the key lock.
+func popRaw(ctx context.Context, cache RawCache, key string) ([]byte, bool, error) {
+	if gd, ok := cache.(GetDeleter); ok {
+		return gd.GetDel(ctx, key)
+	}
+	data, ok, err := cache.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	if err := cache.Delete(ctx, key); err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (m *MultiLevelCache) finishPop(ctx context.Context, key, level string, serializer Serializer, data []byte, ok bool, err error, dest any) (bool, error) {
+	if err != nil {
+		m.emit(ctx, "getdel", key, level, EventError)
+		return false, wrapError("getdel", level, key, err)
+	}
+	if !ok {
+		m.emit(ctx, "getdel", key, level, EventMiss)
+		return false, nil
+	}
+	_, payload := splitContentHash(data)
+	if err := serializer.Unmarshal(payload, dest); err != nil {
+		m.emit(ctx, "getdel", key, level, EventError)
+		return false, wrapError("getdel", level, key, err)
+	}
+	m.log.Debug(ctx, "cache getdel popped key", "level", level, "key", key)
+	m.emit(ctx, "getdel", key, level, EventHit)
+	return true, nil
+}
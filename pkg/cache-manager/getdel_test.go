@@ -0,0 +1,82 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrentPops runs n GetAndDelete calls for key at once, spread over caches, and
+// returns how many received the value.
+func concurrentPops(t *testing.T, caches []*MultiLevelCache, key string, n int) int32 {
+	t.Helper()
+
+	var winners atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		ml := caches[i%len(caches)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			var got string
+			found, err := ml.GetAndDelete(context.Background(), key, &got, CacheOptions{})
+			require.NoError(t, err)
+			if found {
+				require.Equal(t, "token", got)
+				winners.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return winners.Load()
+}
+
+func TestGetAndDeleteSingleWinnerAcrossInstances(t *testing.T) {
+	t.Parallel()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+
+	// Two instances with their own L1 share one Redis, like two app replicas.
+	var caches []*MultiLevelCache
+	for i := 0; i < 2; i++ {
+		ml, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+		require.NoError(t, err)
+		require.NoError(t, ml.Set(context.Background(), "reset:abc", "token", CacheOptions{}))
+		caches = append(caches, ml)
+	}
+
+	require.Equal(t, int32(1), concurrentPops(t, caches, "reset:abc", 20))
+	require.False(t, mr.Exists("reset:abc"))
+	for _, ml := range caches {
+		require.False(t, ml.l1.(*memoryRawCache).has("reset:abc"))
+	}
+}
+
+func TestGetAndDeleteSingleWinnerL1Only(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(context.Background(), "cursor:1", "token", CacheOptions{L1TTL: time.Minute}))
+
+	require.Equal(t, int32(1), concurrentPops(t, []*MultiLevelCache{ml}, "cursor:1", 20))
+	require.False(t, l1.has("cursor:1"))
+}
@@ -6,3 +6,12 @@ func BoolPtr(b bool) *bool {
 	return &b
 }
 
+// UserCacheKey returns the cache key under which a user with the given id is stored.
+func UserCacheKey(id int) string {
+	return UserKey(id).String()
+}
+
+// UserKey is the Key form of UserCacheKey.
+func UserKey(id int) Key {
+	return NewKey("user").Int(id)
+}
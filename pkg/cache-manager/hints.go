@@ -0,0 +1,64 @@
+package cache_manager
+
+import (
+	"context"
+	"time"
+)
+
+// CacheHints carry cache behavior from an outer layer (HTTP middleware, a gRPC
+// interceptor) to MultiLevelCache calls made further down, without threading
+// CacheOptions through every signature. Get, Set and Delete merge them with the per-call
+// options, which take precedence.
+type CacheHints struct {
+	// Bypass skips cache reads and writes, like WithBypass(ctx, BypassRead|BypassWrite).
+	Bypass bool
+	// TTLOverride is used for both levels when CacheOptions.L1TTL/L2TTL are 0.
+	TTLOverride time.Duration
+	// TargetL1 and TargetL2 are used when the matching CacheOptions field is nil.
+	TargetL1 *bool
+	TargetL2 *bool
+	// Namespace prefixes keys as "<namespace>:<key>".
+	Namespace string
+}
+
+type cacheHintsKey struct{}
+
+// WithCacheHints returns a copy of ctx carrying hints, replacing any hints already set.
+func WithCacheHints(ctx context.Context, hints CacheHints) context.Context {
+	return context.WithValue(ctx, cacheHintsKey{}, hints)
+}
+
+// CacheHintsFromContext returns the hints stored by WithCacheHints.
+func CacheHintsFromContext(ctx context.Context) (CacheHints, bool) {
+	if ctx == nil {
+		return CacheHints{}, false
+	}
+	hints, ok := ctx.Value(cacheHintsKey{}).(CacheHints)
+	return hints, ok
+}
+
+// applyHints merges the hints carried by ctx into key and opts.
+func applyHints(ctx context.Context, key string, opts CacheOptions) (string, CacheOptions) {
+	hints, ok := CacheHintsFromContext(ctx)
+	if !ok {
+		return key, opts
+	}
+	if hints.Namespace != "" {
+		key = hints.Namespace + keySeparator + key
+	}
+	if hints.TTLOverride > 0 {
+		if opts.L1TTL == 0 {
+			opts.L1TTL = hints.TTLOverride
+		}
+		if opts.L2TTL == 0 {
+			opts.L2TTL = hints.TTLOverride
+		}
+	}
+	if opts.TargetL1 == nil {
+		opts.TargetL1 = hints.TargetL1
+	}
+	if opts.TargetL2 == nil {
+		opts.TargetL2 = hints.TargetL2
+	}
+	return key, opts
+}
@@ -0,0 +1,71 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheHintsTTLOverride(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := WithCacheHints(context.Background(), CacheHints{TTLOverride: 5 * time.Second})
+
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+	require.Equal(t, 5*time.Second, l1.ttl["k"])
+	require.Equal(t, 5*time.Second, l2.ttl["k"])
+
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{L2TTL: time.Hour}))
+	require.Equal(t, 5*time.Second, l1.ttl["k"])
+	require.Equal(t, time.Hour, l2.ttl["k"], "per-call options take precedence")
+}
+
+func TestCacheHintsTargetsAndNamespace(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := WithCacheHints(context.Background(), CacheHints{TargetL2: BoolPtr(false), Namespace: "tenant-a"})
+
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+	require.True(t, l1.has("tenant-a:k"))
+	require.False(t, l2.has("tenant-a:k"))
+
+	require.NoError(t, ml.Set(ctx, "both", "v", CacheOptions{TargetL2: BoolPtr(true)}))
+	require.True(t, l2.has("tenant-a:both"), "per-call options take precedence")
+
+	var got string
+	res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	res, err = ml.Get(context.Background(), "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found, "keys outside the namespace are separate")
+
+	require.NoError(t, ml.Delete(ctx, "k"))
+	require.False(t, l1.has("tenant-a:k"))
+
+	hints, ok := CacheHintsFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "tenant-a", hints.Namespace)
+	_, ok = CacheHintsFromContext(context.Background())
+	require.False(t, ok)
+}
+
+func TestCacheHintsBypass(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, ml.Set(context.Background(), "k", "v", CacheOptions{}))
+	ctx := WithCacheHints(context.Background(), CacheHints{Bypass: true})
+
+	var got string
+	res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.NoError(t, ml.Set(ctx, "new", "v", CacheOptions{}))
+	require.False(t, l1.has("new"))
+	require.False(t, l2.has("new"))
+}
@@ -0,0 +1,153 @@
+package cache_manager
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// HitRateConfig enables the per-prefix hit rates of MultiLevelConfig.HitRates.
+type HitRateConfig struct {
+	// Enabled records the outcome of every Get by key prefix.
+	Enabled bool
+	// Window is how far back HitRateByPrefix looks. Default 60s, with one-second
+	// resolution.
+	Window time.Duration
+	// LowRate is the hit rate under which a prefix is logged as a candidate for a longer
+	// TTL. Default 0.3.
+	LowRate float64
+	// LogInterval is how often prefixes under LowRate are logged. Default 1m.
+	LogInterval time.Duration
+}
+
+const (
+	defaultHitRateWindow      = 60 * time.Second
+	defaultLowHitRate         = 0.3
+	defaultHitRateLogInterval = time.Minute
+)
+
+// HitRateTracker keeps the hits and misses of each key prefix over a sliding window of
+// one-second buckets.
+type HitRateTracker struct {
+	buckets  int
+	clock    Clock
+	prefixes sync.Map // prefix -> *prefixHits
+}
+
+// prefixHits is the ring of per-second buckets of one prefix.
+type prefixHits struct {
+	mu      sync.Mutex
+	buckets []hitBucket
+}
+
+type hitBucket struct {
+	second       int64 // Unix second the counts belong to
+	hits, misses int64
+}
+
+// NewHitRateTracker creates a tracker over window (default 60s); a nil clock uses the
+// system clock.
+func NewHitRateTracker(window time.Duration, clock Clock) *HitRateTracker {
+	if window <= 0 {
+		window = defaultHitRateWindow
+	}
+	return &HitRateTracker{
+		buckets: max(int(window/time.Second), 1),
+		clock:   clockOrSystem(clock),
+	}
+}
+
+// Record counts a hit or miss for prefix.
+func (t *HitRateTracker) Record(prefix string, hit bool) {
+	v, ok := t.prefixes.Load(prefix)
+	if !ok {
+		v, _ = t.prefixes.LoadOrStore(prefix, &prefixHits{buckets: make([]hitBucket, t.buckets)})
+	}
+	p := v.(*prefixHits)
+	now := t.clock.Now().Unix()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b := &p.buckets[now%int64(t.buckets)]
+	if b.second != now {
+		*b = hitBucket{second: now}
+	}
+	if hit {
+		b.hits++
+	} else {
+		b.misses++
+	}
+}
+
+// Rates returns the hit rate (0..1) of every prefix with gets in the window.
+func (t *HitRateTracker) Rates() map[string]float64 {
+	now := t.clock.Now().Unix()
+	out := make(map[string]float64)
+	t.prefixes.Range(func(k, v any) bool {
+		p := v.(*prefixHits)
+		var hits, total int64
+		p.mu.Lock()
+		for _, b := range p.buckets {
+			if now-b.second < int64(t.buckets) {
+				hits += b.hits
+				total += b.hits + b.misses
+			}
+		}
+		p.mu.Unlock()
+		if total > 0 {
+			out[k.(string)] = float64(hits) / float64(total)
+		}
+		return true
+	})
+	return out
+}
+
+// keyPrefix returns the part of a logical key before its first separator, or the whole
+// key when it has none.
+func keyPrefix(key string) string {
+	prefix, _, _ := strings.Cut(key, keySeparator)
+	return prefix
+}
+
+// HitRateByPrefix returns the hit rate (0..1) of each key prefix, the part of the key
+// before the first ":", over MultiLevelConfig.HitRates.Window. It is nil unless
+// HitRates.Enabled is set.
+func (m *MultiLevelCache) HitRateByPrefix() map[string]float64 {
+	if m == nil || m.hitRates == nil {
+		return nil
+	}
+	return m.hitRates.Rates()
+}
+
+// startHitRateReporter logs the prefixes under cfg.LowRate every cfg.LogInterval until
+// Close.
+func (m *MultiLevelCache) startHitRateReporter(cfg HitRateConfig) {
+	low := cfg.LowRate
+	if low <= 0 {
+		low = defaultLowHitRate
+	}
+	interval := cfg.LogInterval
+	if interval <= 0 {
+		interval = defaultHitRateLogInterval
+	}
+
+	m.backgroundWork.Add(1)
+	go func() {
+		defer m.backgroundWork.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.background.Done():
+				return
+			case <-ticker.C:
+			}
+			for prefix, rate := range m.hitRates.Rates() {
+				if rate < low {
+					m.log.logger.Warn("cache prefix hit rate low, consider a longer ttl",
+						"prefix", prefix, "hit_rate", rate, "threshold", low)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHitRateByPrefix(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{
+		Mode:     ModeL1Only,
+		Clock:    clock,
+		HitRates: HitRateConfig{Enabled: true, Window: 10 * time.Second},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ml.Close(ctx) })
+	for i := range 70 {
+		require.NoError(t, ml.Set(ctx, fmt.Sprintf("user:%d", i), i, CacheOptions{}))
+	}
+
+	// 100 user gets over several seconds: 70 hits and 30 misses.
+	var got int
+	for i := range 100 {
+		if i%20 == 0 {
+			clock.Advance(time.Second)
+		}
+		_, err := ml.Get(ctx, fmt.Sprintf("user:%d", i), &got, CacheOptions{})
+		require.NoError(t, err)
+	}
+	for range 10 {
+		_, err := ml.Get(ctx, "order:1", &got, CacheOptions{})
+		require.NoError(t, err)
+	}
+
+	rates := ml.HitRateByPrefix()
+	require.InDelta(t, 0.7, rates["user"], 1e-9)
+	require.Zero(t, rates["order"])
+	require.Len(t, rates, 2)
+
+	// Gets older than the window no longer count.
+	clock.Advance(6 * time.Second)
+	_, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	rates = ml.HitRateByPrefix()
+	require.InDelta(t, 51.0/81.0, rates["user"], 1e-9, "the first second of gets slid out")
+	clock.Advance(time.Minute)
+	require.Empty(t, ml.HitRateByPrefix())
+}
+
+func TestHitRateDisabled(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.Nil(t, ml.HitRateByPrefix())
+}
+
+func TestHitRateLogsLowPrefixes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var logs bytes.Buffer
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{
+		Mode:     ModeL1Only,
+		Logger:   slog.New(slog.NewTextHandler(&logs, nil)),
+		HitRates: HitRateConfig{Enabled: true, LogInterval: 5 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(ctx, "hot:1", "v", CacheOptions{}))
+	var got string
+	for range 10 {
+		_, err := ml.Get(ctx, "hot:1", &got, CacheOptions{})
+		require.NoError(t, err)
+		_, err = ml.Get(ctx, "cold:1", &got, CacheOptions{})
+		require.NoError(t, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, ml.Close(ctx))
+	require.Contains(t, logs.String(), "prefix=cold")
+	require.NotContains(t, logs.String(), "prefix=hot")
+}
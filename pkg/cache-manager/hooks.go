@@ -0,0 +1,59 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+)
+
+// cacheHooks holds the MultiLevelConfig interception hooks; each may be nil.
+type cacheHooks struct {
+	beforeGet    func(ctx context.Context, key string) context.Context
+	afterGet     func(ctx context.Context, key string, found bool, level string, err error)
+	beforeSet    func(ctx context.Context, key string, opts CacheOptions)
+	afterSet     func(ctx context.Context, key, level string, err error)
+	beforeDelete func(ctx context.Context, key string)
+	afterDelete  func(ctx context.Context, key, level string, err error)
+}
+
+func newCacheHooks(cfg MultiLevelConfig) cacheHooks {
+	return cacheHooks{
+		beforeGet:    cfg.BeforeGet,
+		afterGet:     cfg.AfterGet,
+		beforeSet:    cfg.BeforeSet,
+		afterSet:     cfg.AfterSet,
+		beforeDelete: cfg.BeforeDelete,
+		afterDelete:  cfg.AfterDelete,
+	}
+}
+
+// afterSet reports the outcome of a Set for one level, or for no level ("") when it
+// failed before writing. key is a store key.
+func (m *MultiLevelCache) afterSet(ctx context.Context, key, level string, err error) {
+	if m.hooks.afterSet != nil {
+		m.hooks.afterSet(ctx, m.logicalKey(key), level, err)
+	}
+}
+
+// failSet reports a Set that failed before writing any level to AfterSet and returns err.
+func (m *MultiLevelCache) failSet(ctx context.Context, key string, err error) error {
+	if m.hooks.afterSet != nil {
+		m.hooks.afterSet(ctx, m.logicalKey(key), errorLevel(err), err)
+	}
+	return err
+}
+
+// afterDelete reports the outcome of a Delete for one level. key is a store key.
+func (m *MultiLevelCache) afterDelete(ctx context.Context, key, level string, err error) {
+	if m.hooks.afterDelete != nil {
+		m.hooks.afterDelete(ctx, m.logicalKey(key), level, err)
+	}
+}
+
+// errorLevel is the level a CacheError names, or "" for other errors.
+func errorLevel(err error) string {
+	var ce *CacheError
+	if errors.As(err, &ce) {
+		return ce.Level
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hookCall is one recorded hook invocation.
+type hookCall struct {
+	hook  string
+	key   string
+	found bool
+	level string
+	err   error
+}
+
+// hookRecorder installs every hook on a config and records their calls.
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []hookCall
+	// marked is the value AfterGet saw under markKey, set by BeforeGet.
+	marked []any
+}
+
+type markKey struct{}
+
+func (r *hookRecorder) record(c hookCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, c)
+}
+
+func (r *hookRecorder) take() []hookCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+// install returns cfg with every hook set to record into r.
+func (r *hookRecorder) install(cfg MultiLevelConfig) MultiLevelConfig {
+	cfg.BeforeGet = func(ctx context.Context, key string) context.Context {
+		r.record(hookCall{hook: "before_get", key: key})
+		return context.WithValue(ctx, markKey{}, key)
+	}
+	cfg.AfterGet = func(ctx context.Context, key string, found bool, level string, err error) {
+		r.record(hookCall{hook: "after_get", key: key, found: found, level: level, err: err})
+		r.mu.Lock()
+		r.marked = append(r.marked, ctx.Value(markKey{}))
+		r.mu.Unlock()
+	}
+	cfg.BeforeSet = func(_ context.Context, key string, _ CacheOptions) {
+		r.record(hookCall{hook: "before_set", key: key})
+	}
+	cfg.AfterSet = func(_ context.Context, key, level string, err error) {
+		r.record(hookCall{hook: "after_set", key: key, level: level, err: err})
+	}
+	cfg.BeforeDelete = func(_ context.Context, key string) {
+		r.record(hookCall{hook: "before_delete", key: key})
+	}
+	cfg.AfterDelete = func(_ context.Context, key, level string, err error) {
+		r.record(hookCall{hook: "after_delete", key: key, level: level, err: err})
+	}
+	return cfg
+}
+
+func TestHooksGet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rec := &hookRecorder{}
+	ml, _, _ := newTestMultiLevelCache(t, rec.install(MultiLevelConfig{InstanceName: "svc"}))
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{TargetL1: BoolPtr(false)}))
+	rec.take()
+
+	var got string
+	_, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	_, err = ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	_, err = ml.Get(ctx, "missing", &got, CacheOptions{})
+	require.NoError(t, err)
+
+	require.Equal(t, []hookCall{
+		{hook: "before_get", key: "k"},
+		{hook: "after_get", key: "k", found: true, level: LevelL2},
+		{hook: "before_get", key: "k"},
+		{hook: "after_get", key: "k", found: true, level: LevelL1},
+		{hook: "before_get", key: "missing"},
+		{hook: "after_get", key: "missing"},
+	}, rec.take())
+	require.Equal(t, []any{"k", "k", "missing"}, rec.marked, "AfterGet sees the context returned by BeforeGet")
+}
+
+func TestHooksGetError(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("redis down")
+	rec := &hookRecorder{}
+	ml := newTestMultiLevelCacheOver(t, newMemoryRawCache(), failingRawCache{err: errDown}, rec.install(MultiLevelConfig{InstanceName: "svc"}))
+
+	var got string
+	_, err := ml.Get(context.Background(), "k", &got, CacheOptions{})
+	require.ErrorIs(t, err, errDown)
+
+	calls := rec.take()
+	require.Len(t, calls, 2)
+	require.Equal(t, LevelL2, calls[1].level)
+	require.False(t, calls[1].found)
+	require.ErrorIs(t, calls[1].err, errDown)
+}
+
+func TestHooksSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errDown := errors.New("redis down")
+	rec := &hookRecorder{}
+	ml := newTestMultiLevelCacheOver(t, newMemoryRawCache(), failingRawCache{err: errDown}, rec.install(MultiLevelConfig{InstanceName: "svc"}))
+
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}), "L1 succeeded")
+	calls := rec.take()
+	require.Len(t, calls, 3)
+	require.Equal(t, hookCall{hook: "before_set", key: "k"}, calls[0])
+	require.Equal(t, hookCall{hook: "after_set", key: "k", level: LevelL1}, calls[1])
+	require.Equal(t, "after_set", calls[2].hook)
+	require.Equal(t, LevelL2, calls[2].level)
+	require.ErrorIs(t, calls[2].err, errDown)
+
+	err := ml.Set(ctx, "k", make(chan int), CacheOptions{})
+	require.Error(t, err)
+	calls = rec.take()
+	require.Len(t, calls, 2, "an encoding failure is reported once")
+	require.Equal(t, "", calls[1].level)
+	require.Equal(t, err, calls[1].err)
+}
+
+func TestHooksDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errDown := errors.New("redis down")
+	rec := &hookRecorder{}
+	ml := newTestMultiLevelCacheOver(t, newMemoryRawCache(), failingRawCache{err: errDown}, rec.install(MultiLevelConfig{InstanceName: "svc"}))
+
+	require.ErrorIs(t, ml.Delete(ctx, "k"), errDown)
+	calls := rec.take()
+	require.Len(t, calls, 3)
+	require.Equal(t, hookCall{hook: "before_delete", key: "k"}, calls[0])
+	require.Equal(t, hookCall{hook: "after_delete", key: "k", level: LevelL1}, calls[1])
+	require.Equal(t, LevelL2, calls[2].level)
+	require.ErrorIs(t, calls[2].err, errDown)
+}
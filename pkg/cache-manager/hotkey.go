@@ -0,0 +1,149 @@
+package cache_manager
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+const (
+	// hotKeysTracked is how many candidate keys each shard keeps counts for.
+	hotKeysTracked = 32
+	// hotKeysReported is how many keys ShardStats lists per shard.
+	hotKeysReported = 5
+)
+
+// ShardStats is the traffic of one BigCache shard seen by a HotKeyDetector.
+type ShardStats struct {
+	ShardID int   `json:"shard_id"`
+	Ops     int64 `json:"ops"`
+	// HotKeys are the busiest keys of the shard, busiest first. Counts are approximate
+	// once the shard has seen more distinct keys than it tracks.
+	HotKeys []string `json:"hot_keys"`
+}
+
+// HotKeyDetector counts Get and Set calls per BigCache shard, mapping keys to shards with
+// the same hash and mask bigcache uses, so operators can spot key patterns that pile onto
+// one shard lock. Each shard tracks its busiest keys with the space-saving algorithm in
+// a fixed amount of memory. Enable it with BigCacheConfig.TrackHotKeys.
+type HotKeyDetector struct {
+	hasher bigcache.Hasher
+	mask   uint64
+	shards []hotKeyShard
+}
+
+type hotKeyShard struct {
+	ops  atomic.Int64
+	mu   sync.Mutex
+	keys map[string]int64
+}
+
+// NewHotKeyDetector returns a detector for a bigcache with shards shards (a power of two,
+// as bigcache requires) hashing keys with hasher; nil uses bigcache's default FNV-1a.
+func NewHotKeyDetector(shards int, hasher bigcache.Hasher) *HotKeyDetector {
+	if hasher == nil {
+		hasher = fnv64a{}
+	}
+	d := &HotKeyDetector{hasher: hasher, mask: uint64(shards - 1), shards: make([]hotKeyShard, shards)}
+	for i := range d.shards {
+		d.shards[i].keys = make(map[string]int64, hotKeysTracked)
+	}
+	return d
+}
+
+// Record counts one operation on key.
+func (d *HotKeyDetector) Record(key string) {
+	if d == nil {
+		return
+	}
+	s := &d.shards[d.hasher.Sum64(key)&d.mask]
+	s.ops.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[key]; ok || len(s.keys) < hotKeysTracked {
+		s.keys[key]++
+		return
+	}
+	// Space-saving: the new key replaces the least counted one and inherits its count,
+	// which bounds how far any tracked count can overestimate.
+	minKey, minCount := "", int64(-1)
+	for k, n := range s.keys {
+		if minCount < 0 || n < minCount {
+			minKey, minCount = k, n
+		}
+	}
+	delete(s.keys, minKey)
+	s.keys[key] = minCount + 1
+}
+
+// TopShards returns up to n shards with the most operations, busiest first. Shards
+// without operations are left out.
+func (d *HotKeyDetector) TopShards(n int) []ShardStats {
+	if d == nil || n <= 0 {
+		return nil
+	}
+	stats := make([]ShardStats, 0, len(d.shards))
+	for i := range d.shards {
+		if ops := d.shards[i].ops.Load(); ops > 0 {
+			stats = append(stats, ShardStats{ShardID: i, Ops: ops})
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Ops != stats[j].Ops {
+			return stats[i].Ops > stats[j].Ops
+		}
+		return stats[i].ShardID < stats[j].ShardID
+	})
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	for i := range stats {
+		stats[i].HotKeys = d.shards[stats[i].ShardID].hotKeys()
+	}
+	return stats
+}
+
+// hotKeys returns the shard's busiest tracked keys, busiest first.
+func (s *hotKeyShard) hotKeys() []string {
+	s.mu.Lock()
+	type counted struct {
+		key string
+		n   int64
+	}
+	keys := make([]counted, 0, len(s.keys))
+	for k, n := range s.keys {
+		keys = append(keys, counted{k, n})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].n != keys[j].n {
+			return keys[i].n > keys[j].n
+		}
+		return keys[i].key < keys[j].key
+	})
+	out := make([]string, 0, min(len(keys), hotKeysReported))
+	for _, k := range keys[:min(len(keys), hotKeysReported)] {
+		out = append(out, k.key)
+	}
+	return out
+}
+
+// fnv64a is bigcache's default hasher, which it does not export.
+type fnv64a struct{}
+
+func (fnv64a) Sum64(key string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	var hash uint64 = offset64
+	for i := 0; i < len(key); i++ {
+		hash ^= uint64(key[i])
+		hash *= prime64
+	}
+	return hash
+}
@@ -0,0 +1,72 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotKeyDetectorUsesBigcacheHash(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"", "user:1", "users:both-levels:user:42"} {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		require.Equal(t, h.Sum64(), fnv64a{}.Sum64(key), key)
+	}
+}
+
+func TestHotKeyDetectorFindsBusyShard(t *testing.T) {
+	t.Parallel()
+
+	cfg := bigcache.DefaultConfig(time.Minute)
+	cfg.Shards = 8
+	cfg.Verbose = false
+	bc, err := NewBigCache(context.Background(), BigCacheConfig{Config: cfg, TrackHotKeys: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+	ctx := context.Background()
+
+	// Spread some traffic over every shard, then hammer one key.
+	for i := range 64 {
+		require.NoError(t, bc.Set(ctx, fmt.Sprintf("user:%d", i), []byte("v"), 0))
+	}
+	for range 100 {
+		_, _, err := bc.Get(ctx, "user:7")
+		require.NoError(t, err)
+	}
+
+	top := bc.HotKeys().TopShards(5)
+	require.Len(t, top, 5)
+	require.Equal(t, int(fnv64a{}.Sum64("user:7")&7), top[0].ShardID)
+	require.Equal(t, "user:7", top[0].HotKeys[0])
+	require.Greater(t, top[0].Ops, top[1].Ops)
+	require.LessOrEqual(t, len(top[0].HotKeys), hotKeysReported)
+
+	var total int64
+	for _, s := range bc.HotKeys().TopShards(8) {
+		total += s.Ops
+	}
+	require.Equal(t, int64(164), total)
+}
+
+func TestHotKeyDetectorKeepsHotKeyAmongManyCold(t *testing.T) {
+	t.Parallel()
+
+	d := NewHotKeyDetector(1, nil)
+	for i := range 10 * hotKeysTracked {
+		d.Record(fmt.Sprintf("cold:%d", i))
+		if i%4 == 0 {
+			d.Record("hot")
+		}
+	}
+	top := d.TopShards(1)
+	require.Equal(t, "hot", top[0].HotKeys[0])
+
+	require.Nil(t, (*BigCache)(nil).HotKeys().TopShards(5), "a disabled detector reports nothing")
+}
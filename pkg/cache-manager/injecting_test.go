@@ -0,0 +1,139 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ErrorInjectingCache fails the operations for which ShouldError returns an error and
+// delegates the rest to Inner. Op is "get", "set" or "delete".
+type ErrorInjectingCache struct {
+	Inner       RawCache
+	ShouldError func(op, key string) error
+}
+
+func (c *ErrorInjectingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := c.ShouldError("get", key); err != nil {
+		return nil, false, err
+	}
+	return c.Inner.Get(ctx, key)
+}
+
+func (c *ErrorInjectingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.ShouldError("set", key); err != nil {
+		return err
+	}
+	return c.Inner.Set(ctx, key, value, ttl)
+}
+
+func (c *ErrorInjectingCache) Delete(ctx context.Context, key string) error {
+	if err := c.ShouldError("delete", key); err != nil {
+		return err
+	}
+	return c.Inner.Delete(ctx, key)
+}
+
+// LatencyInjectingCache sleeps for Latency(op, key) before delegating to Inner. The sleep
+// is cut short, with the context error, when ctx is done first.
+type LatencyInjectingCache struct {
+	Inner   RawCache
+	Latency func(op, key string) time.Duration
+}
+
+func (c *LatencyInjectingCache) wait(ctx context.Context, op, key string) error {
+	timer := time.NewTimer(c.Latency(op, key))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *LatencyInjectingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := c.wait(ctx, "get", key); err != nil {
+		return nil, false, err
+	}
+	return c.Inner.Get(ctx, key)
+}
+
+func (c *LatencyInjectingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.wait(ctx, "set", key); err != nil {
+		return err
+	}
+	return c.Inner.Set(ctx, key, value, ttl)
+}
+
+func (c *LatencyInjectingCache) Delete(ctx context.Context, key string) error {
+	if err := c.wait(ctx, "delete", key); err != nil {
+		return err
+	}
+	return c.Inner.Delete(ctx, key)
+}
+
+func TestMultiLevelCacheFallsBackToL1WhenL2Fails(t *testing.T) {
+	t.Parallel()
+
+	errRedisDown := errors.New("redis down")
+	l1 := newMemoryRawCache()
+	l2 := &ErrorInjectingCache{
+		Inner: newMemoryRawCache(),
+		ShouldError: func(op, key string) error {
+			if key == "user:1" {
+				return errRedisDown
+			}
+			return nil
+		},
+	}
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The L2 write fails but the value still lands in L1 and is served from there.
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+	require.True(t, l1.has("user:1"))
+
+	var got string
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "ada", got)
+
+	// Once L1 no longer holds it, the L2 failure surfaces.
+	require.NoError(t, l1.Delete(ctx, "user:1"))
+	_, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.ErrorIs(t, err, errRedisDown)
+	require.ErrorIs(t, ml.Delete(ctx, "user:1"), errRedisDown)
+
+	// Other keys are unaffected.
+	require.NoError(t, ml.Set(ctx, "user:2", "grace", CacheOptions{}))
+	require.True(t, l2.Inner.(*memoryRawCache).has("user:2"))
+}
+
+func TestMultiLevelCacheGetTimesOutOnSlowL2(t *testing.T) {
+	t.Parallel()
+
+	inner := newMemoryRawCache()
+	require.NoError(t, inner.Set(context.Background(), "user:1", []byte(`"ada"`), time.Minute))
+	l2 := &LatencyInjectingCache{
+		Inner:   inner,
+		Latency: func(op, key string) time.Duration { return time.Second },
+	}
+	ml, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var got string
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, res.Found)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,270 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TTLInspector is implemented by raw caches that can report the remaining TTL of a key.
+type TTLInspector interface {
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+}
+
+// KeyLister is implemented by raw caches that can enumerate their keys.
+type KeyLister interface {
+	Keys(ctx context.Context, pattern string) ([]string, error)
+}
+
+// EvictionReporter is implemented by raw caches that count evictions by reason.
+type EvictionReporter interface {
+	Evictions() EvictionCounts
+}
+
+// EntryInfo describes where a key is cached and how long it will live.
+type EntryInfo struct {
+	Key string `json:"key"`
+	// Levels lists the cache levels currently holding the key ("L1", "L2").
+	Levels []string `json:"levels"`
+	// L1TTL and L2TTL are the remaining lifetimes; zero means no expiry or not present.
+	L1TTL time.Duration `json:"l1_ttl,omitempty"`
+	L2TTL time.Duration `json:"l2_ttl,omitempty"`
+	// Value is the serialized payload as stored in the first level that holds it.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// KeyCounts reports how many keys each configured level holds.
+type KeyCounts struct {
+	L1 int `json:"l1"`
+	L2 int `json:"l2"`
+}
+
+// Inspect looks the key up in every configured level without warming L1.
+func (m *MultiLevelCache) Inspect(ctx context.Context, key string) (EntryInfo, bool, error) {
+	if m == nil {
+		return EntryInfo{}, false, &CacheError{Op: "inspect", Key: key, Cause: ErrNotInitialized}
+	}
+
+	info := EntryInfo{Key: key}
+	key = m.storeKey(key)
+	for _, lvl := range m.levels() {
+		var data []byte
+		var ok bool
+		var err error
+		if lvl.name == LevelL2 {
+			data, ok, err = m.readL2(ctx, key)
+		} else {
+			data, ok, err = lvl.cache.Get(ctx, key)
+		}
+		if err != nil {
+			return EntryInfo{}, false, wrapError("inspect", lvl.name, key, err)
+		}
+		if !ok {
+			continue
+		}
+		info.Levels = append(info.Levels, lvl.name)
+		if info.Value == nil {
+			_, payload := splitContentHash(data)
+			info.Value = rawJSON(payload)
+		}
+		if inspector, ok := lvl.cache.(TTLInspector); ok {
+			ttl, _, err := inspector.TTL(ctx, key)
+			if err != nil {
+				return EntryInfo{}, false, err
+			}
+			if lvl.name == LevelL1 {
+				info.L1TTL = ttl
+			} else {
+				info.L2TTL = ttl
+			}
+		}
+	}
+
+	return info, len(info.Levels) > 0, nil
+}
+
+// Keys returns the sorted, de-duplicated keys matching pattern across all configured levels.
+// Levels that do not implement KeyLister are skipped.
+func (m *MultiLevelCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if m == nil {
+		return nil, &CacheError{Op: "keys", Cause: ErrNotInitialized}
+	}
+
+	seen := make(map[string]struct{})
+	for _, lvl := range m.levels() {
+		lister, ok := lvl.cache.(KeyLister)
+		if !ok {
+			continue
+		}
+		keys, err := lister.Keys(ctx, m.storePattern(pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			seen[m.logicalKey(k)] = struct{}{}
+		}
+	}
+
+	out := make([]string, 0, len(seen))
+	for k := range seen {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ListL1Keys returns the sorted L1 keys matching a path.Match glob such as "user:*",
+// without touching L2. Only keys of the configured Version are listed, without the prefix.
+func (m *MultiLevelCache) ListL1Keys(ctx context.Context, match string) ([]string, error) {
+	if m == nil {
+		return nil, &CacheError{Op: "keys", Cause: ErrNotInitialized}
+	}
+	if m.l1 == nil {
+		return nil, &CacheError{Op: "keys", Level: LevelL1, Cause: ErrLevelNotConfigured}
+	}
+	lister, ok := m.l1.(KeyLister)
+	if !ok {
+		return nil, &CacheError{Op: "keys", Level: LevelL1, Cause: errors.New("L1 cannot list keys")}
+	}
+
+	keys, err := lister.Keys(ctx, m.storePattern(match))
+	if err != nil {
+		return nil, wrapError("keys", LevelL1, "", err)
+	}
+	for i, k := range keys {
+		keys[i] = m.logicalKey(k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// CountKeys reports the number of keys held by each level that implements KeyLister.
+func (m *MultiLevelCache) CountKeys(ctx context.Context) (KeyCounts, error) {
+	if m == nil {
+		return KeyCounts{}, &CacheError{Op: "count", Cause: ErrNotInitialized}
+	}
+
+	var counts KeyCounts
+	for _, lvl := range m.levels() {
+		lister, ok := lvl.cache.(KeyLister)
+		if !ok {
+			continue
+		}
+		keys, err := lister.Keys(ctx, m.storePattern("*"))
+		if err != nil {
+			return KeyCounts{}, err
+		}
+		if lvl.name == LevelL1 {
+			counts.L1 = len(keys)
+		} else {
+			counts.L2 = len(keys)
+		}
+	}
+	return counts, nil
+}
+
+// Flush deletes every key from all levels and returns how many keys were removed.
+// It is subject to MaxFlushPerMinute and returns ErrFlushRateLimited when over budget.
+func (m *MultiLevelCache) Flush(ctx context.Context) (int, error) {
+	return m.DeleteByPrefix(ctx, "")
+}
+
+// DeleteByPrefix deletes every key starting with prefix from all levels and returns how many
+// keys were removed. It shares the Flush rate limit.
+func (m *MultiLevelCache) DeleteByPrefix(ctx context.Context, prefix string) (int, error) {
+	if m == nil {
+		return 0, &CacheError{Op: "flush", Cause: ErrNotInitialized}
+	}
+	if m.flushLimiter != nil && !m.flushLimiter.Allow() {
+		return 0, &CacheError{Op: "flush", Cause: ErrFlushRateLimited}
+	}
+
+	dropped, err := m.dropL2Namespace(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	keys, err := m.Keys(ctx, escapeGlob(prefix)+"*")
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := len(dropped)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if err := m.Delete(ctx, k); err != nil {
+			return deleted, err
+		}
+		if _, ok := dropped[k]; !ok {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// dropL2Namespace deletes prefix from L2 in one step when L2 is a NamespaceDropper that
+// keeps prefix as a namespace, and returns the logical keys it held. DeleteByPrefix then
+// only has the L1 copies left to delete one by one.
+func (m *MultiLevelCache) dropL2Namespace(ctx context.Context, prefix string) (map[string]struct{}, error) {
+	dropper, ok := m.l2.(NamespaceDropper)
+	if !ok || !m.LevelEnabled(LevelL2) || m.readOnly.l2 || !m.l2Available() {
+		return nil, nil
+	}
+	keys, ok, err := dropper.DropNamespace(ctx, m.storeKey(prefix))
+	if err != nil {
+		return nil, wrapError("flush", LevelL2, prefix, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	m.log.Debug(ctx, "cache flush dropped l2 namespace", "prefix", prefix, "keys", len(keys))
+	dropped := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		dropped[m.logicalKey(k)] = struct{}{}
+	}
+	return dropped, nil
+}
+
+type namedLevel struct {
+	name  string
+	cache RawCache
+}
+
+// levels returns the configured cache levels in lookup order.
+func (m *MultiLevelCache) levels() []namedLevel {
+	var out []namedLevel
+	if m.l1 != nil {
+		out = append(out, namedLevel{name: LevelL1, cache: m.l1})
+	}
+	if m.l2 != nil {
+		out = append(out, namedLevel{name: LevelL2, cache: m.l2})
+	}
+	return out
+}
+
+// rawJSON returns data as-is when it is valid JSON, otherwise as a JSON string.
+func rawJSON(data []byte) json.RawMessage {
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+	quoted, _ := json.Marshal(string(data))
+	return json.RawMessage(quoted)
+}
+
+// escapeGlob escapes glob metacharacters so s is matched literally.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
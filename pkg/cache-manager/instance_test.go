@@ -0,0 +1,65 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesSharingRawCachesAreIsolated(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	both, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, InstanceName: "both"})
+	require.NoError(t, err)
+	l1Only, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, InstanceName: "l1"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, both.Set(ctx, "user:1", "from both", CacheOptions{}))
+	require.NoError(t, l1Only.Set(ctx, "user:1", "from l1", CacheOptions{}))
+	require.True(t, l1.has("both:user:1"))
+	require.True(t, l1.has("l1:user:1"))
+	require.True(t, l2.has("both:user:1"))
+
+	var got string
+	_, err = both.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "from both", got)
+	_, err = l1Only.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "from l1", got)
+
+	require.NoError(t, l1Only.Delete(ctx, "user:1"))
+	require.False(t, l1.has("l1:user:1"))
+	require.True(t, l1.has("both:user:1"))
+
+	require.NoError(t, l1Only.Set(ctx, "user:2", "from l1", CacheOptions{}))
+	keys, err := both.Keys(ctx, "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"user:1"}, keys)
+
+	deleted, err := both.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	require.True(t, l1.has("l1:user:2"))
+}
+
+func TestInstanceNameComesBeforeVersion(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, InstanceName: "users", Version: "v2"})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Set(context.Background(), "user:1", 1, CacheOptions{}))
+	require.True(t, l1.has("users:v2:user:1"))
+}
+
+func TestNewMultiLevelCacheRejectsInstanceNameWithSeparator(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, InstanceName: "a:b"})
+	require.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/allegro/bigcache/v3"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeepTTLTestCache(t *testing.T) (*MultiLevelCache, *BigCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+
+	bcConfig := bigcache.DefaultConfig(time.Hour)
+	bcConfig.Verbose = false
+	l1, err := NewBigCache(context.Background(), BigCacheConfig{Config: bcConfig})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+	return ml, l1, mr
+}
+
+func TestSetPreserveTTLKeepsExpiry(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, mr := newKeepTTLTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "user:1", "v1", CacheOptions{L1TTL: 10 * time.Minute, L2TTL: 10 * time.Minute}))
+	mr.FastForward(4 * time.Minute)
+
+	require.NoError(t, ml.Set(ctx, "user:1", "v2", CacheOptions{L1TTL: time.Hour, L2TTL: time.Hour, PreserveTTL: true}))
+
+	require.Equal(t, 6*time.Minute, mr.TTL("user:1"))
+	stored, err := mr.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, `"v2"`, stored)
+
+	l1TTL, found, err := l1.TTL(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.LessOrEqual(t, l1TTL, 10*time.Minute)
+
+	var got string
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "v2", got)
+}
+
+func TestSetPreserveTTLFallsBackWhenAbsent(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, mr := newKeepTTLTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "user:2", "v1", CacheOptions{L1TTL: time.Minute, L2TTL: 2 * time.Minute, PreserveTTL: true}))
+
+	require.Equal(t, 2*time.Minute, mr.TTL("user:2"))
+	l1TTL, found, err := l1.TTL(ctx, "user:2")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.InDelta(t, time.Minute, l1TTL, float64(time.Second))
+}
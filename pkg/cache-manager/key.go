@@ -0,0 +1,217 @@
+package cache_manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// keySeparator joins the segments of a Key.
+const keySeparator = ":"
+
+// keyEscaper escapes the separator and the escape character inside segments so that
+// segments containing ':' cannot collide with a key that has more segments.
+var (
+	keyEscaper   = strings.NewReplacer("%", "%25", ":", "%3A")
+	keyUnescaper = strings.NewReplacer("%3A", ":", "%25", "%")
+)
+
+// Key builds canonical cache keys from typed segments, e.g.
+// NewKey("user").Int(42).String() == "user:42". Keys are immutable values; every
+// builder method returns a new Key.
+type Key struct {
+	segments []string
+}
+
+// NewKey starts a key with the given namespace segment.
+func NewKey(namespace string) Key {
+	return Key{segments: []string{namespace}}
+}
+
+// Str appends a string segment.
+func (k Key) Str(s string) Key {
+	return k.with(s)
+}
+
+// Int appends an integer segment.
+func (k Key) Int(n int) Key {
+	return k.with(strconv.Itoa(n))
+}
+
+// Int64 appends a 64-bit integer segment.
+func (k Key) Int64(n int64) Key {
+	return k.with(strconv.FormatInt(n, 10))
+}
+
+func (k Key) with(segment string) Key {
+	segments := make([]string, len(k.segments), len(k.segments)+1)
+	copy(segments, k.segments)
+	return Key{segments: append(segments, segment)}
+}
+
+// String returns the canonical form of the key, with separators inside segments escaped.
+func (k Key) String() string {
+	escaped := make([]string, len(k.segments))
+	for i, s := range k.segments {
+		escaped[i] = keyEscaper.Replace(s)
+	}
+	return strings.Join(escaped, keySeparator)
+}
+
+// Prefix returns a glob pattern (for Keys, SCAN or /keys) matching every key that extends
+// k by at least one segment. Glob metacharacters inside segments are matched literally.
+func (k Key) Prefix() string {
+	return escapeGlob(k.String()+keySeparator) + "*"
+}
+
+// Segments returns a copy of the key's unescaped segments, namespace first.
+func (k Key) Segments() []string {
+	return append([]string(nil), k.segments...)
+}
+
+// Segment returns the i-th unescaped segment, or "" when out of range.
+func (k Key) Segment(i int) string {
+	if i < 0 || i >= len(k.segments) {
+		return ""
+	}
+	return k.segments[i]
+}
+
+// IntSegment parses the i-th segment as an int.
+func (k Key) IntSegment(i int) (int, error) {
+	if i < 0 || i >= len(k.segments) {
+		return 0, fmt.Errorf("key %q has no segment %d", k.String(), i)
+	}
+	return strconv.Atoi(k.segments[i])
+}
+
+// Equal reports whether both keys have the same segments.
+func (k Key) Equal(other Key) bool {
+	if len(k.segments) != len(other.segments) {
+		return false
+	}
+	for i := range k.segments {
+		if k.segments[i] != other.segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// HasPrefix reports whether k starts with all segments of prefix.
+func (k Key) HasPrefix(prefix Key) bool {
+	if len(prefix.segments) > len(k.segments) {
+		return false
+	}
+	for i := range prefix.segments {
+		if k.segments[i] != prefix.segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseKey splits a canonical key string back into segments, reversing Key.String.
+func ParseKey(s string) (Key, error) {
+	if s == "" {
+		return Key{}, fmt.Errorf("empty cache key")
+	}
+	parts := strings.Split(s, keySeparator)
+	for i, p := range parts {
+		parts[i] = keyUnescaper.Replace(p)
+	}
+	return Key{segments: parts}, nil
+}
+
+// CollectionKey returns the key of one view of a collection, such as a page of a list:
+// base followed by the query-escaped params sorted by name, e.g.
+// CollectionKey("users:list", map[string]string{"offset": "20", "limit": "10"}) ==
+// "users:list?limit=10&offset=20". Equal params give equal keys whatever the map order,
+// and escaping keeps different params from colliding. Without params it returns base.
+func CollectionKey(base string, params map[string]string) string {
+	if len(params) == 0 {
+		return base
+	}
+	values := make(url.Values, len(params))
+	for name, value := range params {
+		values.Set(name, value)
+	}
+	return base + "?" + values.Encode()
+}
+
+// QueryFingerprint returns a stable key for a SQL query and its arguments: the hex
+// SHA-256 of the normalized query and the JSON-encoded args. Queries that differ only in
+// whitespace, letter case outside quotes or a trailing semicolon share a fingerprint;
+// quoted literals and identifiers are kept as written.
+func QueryFingerprint(query string, args ...any) (string, error) {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("query fingerprint args: %w", err)
+	}
+	sum := sha256.Sum256([]byte("query:" + normalizeSQL(query) + ":args:" + string(encodedArgs)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeSQL lowercases query and collapses whitespace runs into one space, except
+// inside '...' literals and "..." identifiers, and drops a trailing semicolon.
+func normalizeSQL(query string) string {
+	var b strings.Builder
+	var quote rune
+	space := false
+	for _, r := range strings.TrimSpace(query) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		default:
+			r = unicode.ToLower(r)
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(strings.TrimSuffix(b.String(), ";"))
+}
+
+// storeKeyPrefix returns the stored-key prefix for MultiLevelConfig.InstanceName and
+// MultiLevelConfig.Version.
+func storeKeyPrefix(instance, version string) string {
+	var prefix string
+	for _, part := range []string{instance, version} {
+		if part != "" {
+			prefix += part + keySeparator
+		}
+	}
+	return prefix
+}
+
+// storeKey maps a caller's key to the key stored in the raw caches.
+func (m *MultiLevelCache) storeKey(key string) string {
+	return m.keyPrefix + key
+}
+
+// logicalKey reverses storeKey.
+func (m *MultiLevelCache) logicalKey(stored string) string {
+	return strings.TrimPrefix(stored, m.keyPrefix)
+}
+
+// storePattern scopes a glob pattern to the keys of this instance and version.
+func (m *MultiLevelCache) storePattern(pattern string) string {
+	if pattern == "" {
+		pattern = "*"
+	}
+	return escapeGlob(m.keyPrefix) + pattern
+}
@@ -0,0 +1,234 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultKeyPageSize is the ScanKeys page size when limit is not positive.
+	defaultKeyPageSize = 100
+	// maxKeyPageSize caps the ScanKeys page size.
+	maxKeyPageSize = 1000
+)
+
+// errInvalidKeyCursor indicates a ScanKeys cursor that was not returned by ScanKeys.
+var errInvalidKeyCursor = errors.New("invalid key cursor")
+
+// KeyScanner is implemented by raw caches that can list their keys a page at a time.
+// An empty cursor starts the listing; an empty next cursor ends it.
+type KeyScanner interface {
+	ScanKeys(ctx context.Context, pattern, cursor string, count int) (keys []string, next string, err error)
+}
+
+var (
+	_ KeyScanner = (*BigCache)(nil)
+	_ KeyScanner = (*RedisCache)(nil)
+)
+
+// KeyPage is one page of ScanKeys.
+type KeyPage struct {
+	// Keys lists each key once, with every level holding it and the remaining TTLs.
+	Keys []EntryInfo `json:"keys"`
+	// Cursor continues the listing; it is empty once every key has been listed.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ScanKeys lists at most limit (default 100, at most 1000) keys matching pattern across
+// both levels, resuming from cursor. L1 keys are listed first, in key order, then the L2
+// keys not already listed with L1; a key found in both levels is reported once with both.
+// Each call does bounded work, so a huge keyspace is walked page by page rather than in
+// one call. Keys written or deleted during the walk may be missed or listed twice. An L2
+// page can fall short of limit, as keys already listed with L1 are dropped from it, or
+// run a little over, as SCAN batches are not split. Levels that do not implement
+// KeyScanner are skipped.
+func (m *MultiLevelCache) ScanKeys(ctx context.Context, pattern string, limit int, cursor string) (KeyPage, error) {
+	if m == nil {
+		return KeyPage{}, &CacheError{Op: "keys", Cause: ErrNotInitialized}
+	}
+	if limit <= 0 {
+		limit = defaultKeyPageSize
+	}
+	limit = min(limit, maxKeyPageSize)
+	start, pos, err := decodeKeyCursor(cursor)
+	if err != nil {
+		return KeyPage{}, &CacheError{Op: "keys", Cause: err}
+	}
+
+	page := KeyPage{Keys: []EntryInfo{}}
+	for _, lvl := range m.levels() {
+		if start != "" && lvl.name != start {
+			continue
+		}
+		start = ""
+		scanner, ok := lvl.cache.(KeyScanner)
+		if !ok {
+			continue
+		}
+		keys, next, err := scanner.ScanKeys(ctx, m.storePattern(pattern), pos, limit-len(page.Keys))
+		if err != nil {
+			return KeyPage{}, wrapError("keys", lvl.name, "", err)
+		}
+		pos = ""
+		for _, k := range keys {
+			info, ok, err := m.scannedKeyInfo(ctx, lvl.name, k)
+			if err != nil {
+				return KeyPage{}, err
+			}
+			if ok {
+				page.Keys = append(page.Keys, info)
+			}
+		}
+		if next != "" {
+			page.Cursor = encodeKeyCursor(lvl.name, next)
+			return page, nil
+		}
+		if len(page.Keys) >= limit {
+			page.Cursor = encodeKeyCursor(lvl.name, "")
+			return page, nil
+		}
+	}
+	return page, nil
+}
+
+// scannedKeyInfo reports the levels and TTLs of a key listed by level scanned. An L2 key
+// also in a listable L1 is skipped, as it was reported with the L1 keys.
+func (m *MultiLevelCache) scannedKeyInfo(ctx context.Context, scanned, key string) (EntryInfo, bool, error) {
+	info := EntryInfo{Key: m.logicalKey(key)}
+	for _, lvl := range m.levels() {
+		present := lvl.name == scanned
+		var ttl time.Duration
+		if inspector, ok := lvl.cache.(TTLInspector); ok {
+			var found bool
+			var err error
+			ttl, found, err = inspector.TTL(ctx, key)
+			if err != nil {
+				return EntryInfo{}, false, wrapError("keys", lvl.name, key, err)
+			}
+			present = present || found
+		}
+		if !present {
+			continue
+		}
+		if _, listable := lvl.cache.(KeyScanner); lvl.name == LevelL1 && scanned == LevelL2 && listable {
+			return EntryInfo{}, false, nil
+		}
+		info.Levels = append(info.Levels, lvl.name)
+		if lvl.name == LevelL1 {
+			info.L1TTL = ttl
+		} else {
+			info.L2TTL = ttl
+		}
+	}
+	return info, true, nil
+}
+
+// encodeKeyCursor makes the opaque ScanKeys cursor for resuming level at pos. An empty
+// pos resumes at the level after it.
+func encodeKeyCursor(level, pos string) string {
+	if pos == "" {
+		if level != LevelL1 {
+			return ""
+		}
+		level = LevelL2
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(level + ":" + pos))
+}
+
+func decodeKeyCursor(cursor string) (level, pos string, err error) {
+	if cursor == "" {
+		return "", "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", errInvalidKeyCursor
+	}
+	level, pos, ok := strings.Cut(string(raw), ":")
+	if !ok || (level != LevelL1 && level != LevelL2) {
+		return "", "", errInvalidKeyCursor
+	}
+	return level, pos, nil
+}
+
+// ScanKeys lists up to count live keys matching a path.Match glob, in key order, after
+// cursor, which is the last key of the previous page. Each call walks the whole cache,
+// but holds only the matching keys.
+func (b *BigCache) ScanKeys(ctx context.Context, pattern, cursor string, count int) ([]string, string, error) {
+	release := b.rlock()
+	if release == nil {
+		return nil, "", &CacheError{Op: "keys", Level: LevelL1, Cause: ErrNotInitialized}
+	}
+	defer release()
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	now := b.now()
+	var keys []string
+	it := b.cache.Iterator()
+	for it.SetNext() {
+		info, err := it.Value()
+		if err != nil {
+			continue
+		}
+		raw := info.Value()
+		if len(raw) < entryHeaderSize || entryExpired(raw, now) || info.Key() <= cursor {
+			continue
+		}
+		ok, err := path.Match(pattern, info.Key())
+		if err != nil {
+			return nil, "", &CacheError{Op: "keys", Level: LevelL1, Cause: err}
+		}
+		if ok {
+			keys = append(keys, info.Key())
+		}
+	}
+	sort.Strings(keys)
+	if count <= 0 || len(keys) <= count {
+		return keys, "", nil
+	}
+	keys = keys[:count]
+	return keys, keys[count-1], nil
+}
+
+// ScanKeys runs SCAN MATCH pattern from cursor, a SCAN cursor, until at least count keys
+// were collected or the scan completes.
+func (r *RedisCache) ScanKeys(ctx context.Context, pattern, cursor string, count int) ([]string, string, error) {
+	done, err := r.begin("keys", "")
+	if err != nil {
+		return nil, "", err
+	}
+	defer done()
+	if pattern == "" {
+		pattern = "*"
+	}
+	var pos uint64
+	if cursor != "" {
+		if pos, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+			return nil, "", &CacheError{Op: "keys", Level: LevelL2, Cause: fmt.Errorf("%w: %q", errInvalidKeyCursor, cursor)}
+		}
+	}
+	count = max(count, 1)
+
+	var keys []string
+	for {
+		batch, next, err := r.client.Scan(ctx, pos, pattern, int64(count-len(keys))).Result()
+		if err != nil {
+			return nil, "", &CacheError{Op: "keys", Level: LevelL2, Cause: err}
+		}
+		keys = append(keys, batch...)
+		if pos = next; pos == 0 {
+			return keys, "", nil
+		}
+		if len(keys) >= count {
+			return keys, strconv.FormatUint(pos, 10), nil
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// newKeyScanTestCache holds user:0..user:9 in L1 only, user:10..user:19 in both levels and
+// user:20..user:39 plus session:1 in L2 only.
+func newKeyScanTestCache(t *testing.T) *MultiLevelCache {
+	t.Helper()
+	ctx := context.Background()
+
+	cfg := bigcache.DefaultConfig(time.Hour)
+	cfg.Verbose = false
+	l1, err := NewBigCache(ctx, BigCacheConfig{Config: cfg})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+	l2, _ := newRenameTestRedis(t)
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:         ModeBothLevels,
+		L1DefaultTTL: time.Minute,
+		L2DefaultTTL: time.Hour,
+	})
+	require.NoError(t, err)
+
+	for i := range 40 {
+		opts := CacheOptions{}
+		switch {
+		case i < 10:
+			opts.TargetL2 = BoolPtr(false)
+		case i >= 20:
+			opts.TargetL1 = BoolPtr(false)
+		}
+		require.NoError(t, ml.Set(ctx, fmt.Sprintf("user:%d", i), i, opts))
+	}
+	require.NoError(t, ml.Set(ctx, "session:1", "s", CacheOptions{TargetL1: BoolPtr(false)}))
+	return ml
+}
+
+func TestScanKeysPagesThroughBothLevels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml := newKeyScanTestCache(t)
+
+	seen := make(map[string]EntryInfo)
+	cursor, pages := "", 0
+	for {
+		page, err := ml.ScanKeys(ctx, "user:*", 7, cursor)
+		require.NoError(t, err)
+		pages++
+		for _, info := range page.Keys {
+			_, dup := seen[info.Key]
+			require.False(t, dup, "%s listed twice", info.Key)
+			seen[info.Key] = info
+		}
+		if page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+		require.Less(t, pages, 20, "the listing does not end")
+	}
+	require.Len(t, seen, 40)
+	require.Greater(t, pages, 40/7)
+
+	l1Only, both, l2Only := seen["user:3"], seen["user:15"], seen["user:30"]
+	require.Equal(t, []string{LevelL1}, l1Only.Levels)
+	require.InDelta(t, time.Minute, l1Only.L1TTL, float64(time.Second))
+	require.Zero(t, l1Only.L2TTL)
+	require.Equal(t, []string{LevelL1, LevelL2}, both.Levels)
+	require.InDelta(t, time.Hour, both.L2TTL, float64(time.Second))
+	require.Equal(t, []string{LevelL2}, l2Only.Levels)
+	require.Zero(t, l2Only.L1TTL)
+}
+
+func TestScanKeysFiltersAndValidates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml := newKeyScanTestCache(t)
+
+	page, err := ml.ScanKeys(ctx, "session:*", 0, "")
+	require.NoError(t, err)
+	require.Empty(t, page.Cursor)
+	require.Len(t, page.Keys, 1)
+	require.Equal(t, "session:1", page.Keys[0].Key)
+
+	page, err = ml.ScanKeys(ctx, "user:1?", 100, "")
+	require.NoError(t, err)
+	require.Len(t, page.Keys, 10)
+	require.Empty(t, page.Cursor)
+
+	page, err = ml.ScanKeys(ctx, "missing:*", 10, "")
+	require.NoError(t, err)
+	require.Empty(t, page.Keys)
+
+	_, err = ml.ScanKeys(ctx, "*", 10, "not a cursor")
+	require.ErrorIs(t, err, errInvalidKeyCursor)
+}
+
+func TestKeysHandler(t *testing.T) {
+	t.Parallel()
+
+	ml := newKeyScanTestCache(t)
+	handler := NewKeysHandler(ml, "secret")
+	get := func(target, token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/cache/keys/scan?pattern=user:*&limit=25", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page KeyPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.GreaterOrEqual(t, len(page.Keys), 20, "all L1 keys fit in the first page")
+	require.NotEmpty(t, page.Cursor)
+
+	rec = get("/cache/keys/scan?pattern=user:*&limit=100&cursor="+page.Cursor, "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var next KeyPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &next))
+	require.Len(t, append(page.Keys, next.Keys...), 40)
+	require.Empty(t, next.Cursor)
+
+	require.Equal(t, http.StatusBadRequest, get("/cache/keys/scan?pattern=%5B", "secret").Code)
+	require.Equal(t, http.StatusBadRequest, get("/cache/keys/scan?limit=x", "secret").Code)
+	require.Equal(t, http.StatusBadRequest, get("/cache/keys/scan?cursor=bogus", "secret").Code)
+	require.Equal(t, http.StatusUnauthorized, get("/cache/keys/scan", "").Code)
+}
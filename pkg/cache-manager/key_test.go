@@ -0,0 +1,163 @@
+package cache_manager
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "user:42", NewKey("user").Int(42).String())
+	require.Equal(t, UserCacheKey(7), UserKey(7).String())
+	require.Equal(t, "order:9000000000:line:3", NewKey("order").Int64(9_000_000_000).Str("line").Int(3).String())
+}
+
+func TestKeyEscapesSeparators(t *testing.T) {
+	t.Parallel()
+
+	// Without escaping both keys would be "session:a:b".
+	joined := NewKey("session").Str("a:b")
+	split := NewKey("session").Str("a").Str("b")
+	require.Equal(t, "session:a%3Ab", joined.String())
+	require.NotEqual(t, joined.String(), split.String())
+	require.Equal(t, "tag:100%25:x%253A", NewKey("tag").Str("100%").Str("x%3A").String())
+
+	for _, k := range []Key{joined, split, NewKey("tag").Str("100%").Str("x%3A"), NewKey("").Str("")} {
+		parsed, err := ParseKey(k.String())
+		require.NoError(t, err)
+		require.True(t, parsed.Equal(k), "round trip of %q gave %v", k.String(), parsed.Segments())
+	}
+}
+
+func TestKeyEqual(t *testing.T) {
+	t.Parallel()
+
+	base := NewKey("user")
+	a := base.Int(1)
+	b := base.Int(2)
+	require.True(t, a.Equal(NewKey("user").Str("1")))
+	require.False(t, a.Equal(b))
+	require.False(t, a.Equal(base))
+	require.Equal(t, []string{"user"}, base.Segments(), "builder methods must not mutate the receiver")
+}
+
+func TestKeyPrefixMatchesSegmentsWithSeparators(t *testing.T) {
+	t.Parallel()
+
+	tenant := NewKey("tenant").Str("eu:west")
+	inside := tenant.Str("user").Int(1)
+	outside := NewKey("tenant").Str("eu").Str("west").Str("user")
+	glob := NewKey("tenant").Str("a*b")
+
+	require.True(t, inside.HasPrefix(tenant))
+	require.False(t, outside.HasPrefix(tenant))
+	require.False(t, tenant.HasPrefix(inside))
+
+	matches := func(pattern string, k Key) bool {
+		ok, err := path.Match(pattern, k.String())
+		require.NoError(t, err)
+		return ok
+	}
+	require.True(t, matches(tenant.Prefix(), inside))
+	require.False(t, matches(tenant.Prefix(), outside))
+	require.False(t, matches(tenant.Prefix(), tenant))
+	require.True(t, matches(glob.Prefix(), glob.Int(1)))
+	require.False(t, matches(glob.Prefix(), NewKey("tenant").Str("aXb").Int(1)))
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	for _, k := range []Key{inside, outside} {
+		require.NoError(t, ml.Set(ctx, k.String(), "v", CacheOptions{}))
+	}
+	keys, err := l1.Keys(ctx, tenant.Prefix())
+	require.NoError(t, err)
+	require.Equal(t, []string{inside.String()}, keys)
+
+	parsed, err := ParseKey(keys[0])
+	require.NoError(t, err)
+	id, err := parsed.IntSegment(3)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+	require.Equal(t, "eu:west", parsed.Segment(1))
+}
+
+func TestParseKeyRejectsEmpty(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseKey("")
+	require.Error(t, err)
+}
+
+func TestCollectionKeyIsCanonical(t *testing.T) {
+	t.Parallel()
+
+	page1 := CollectionKey("users:list", map[string]string{"offset": "0", "limit": "10"})
+	require.Equal(t, "users:list?limit=10&offset=0", page1)
+	require.Equal(t, page1, CollectionKey("users:list", map[string]string{"limit": "10", "offset": "0"}))
+	require.NotEqual(t, page1, CollectionKey("users:list", map[string]string{"limit": "10", "offset": "10"}))
+
+	// Escaping keeps a value containing a separator from posing as two parameters.
+	require.NotEqual(t,
+		CollectionKey("users:list", map[string]string{"q": "a&limit=10"}),
+		CollectionKey("users:list", map[string]string{"q": "a", "limit": "10"}))
+	require.Equal(t, "users:list", CollectionKey("users:list", nil))
+}
+
+func TestQueryFingerprintIsStableAcrossFormatting(t *testing.T) {
+	t.Parallel()
+
+	base, err := QueryFingerprint("SELECT id, name FROM users WHERE id = $1", 42)
+	require.NoError(t, err)
+	require.Len(t, base, 64)
+
+	for _, q := range []string{
+		"select id, name from users where id = $1",
+		"  SELECT id,  name\n\tFROM users\nWHERE id = $1;  ",
+	} {
+		fp, err := QueryFingerprint(q, 42)
+		require.NoError(t, err)
+		require.Equal(t, base, fp, q)
+	}
+}
+
+func TestQueryFingerprintDistinguishesQueriesAndArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		query string
+		args  []any
+	}{
+		{"SELECT * FROM users WHERE id = $1", []any{1}},
+		{"SELECT * FROM users WHERE id = $1", []any{2}},
+		{"SELECT * FROM users WHERE id = $1", []any{"1"}},
+		{"SELECT * FROM users WHERE id = $1", nil},
+		{"SELECT * FROM orders WHERE id = $1", []any{1}},
+		{"SELECT * FROM users WHERE name = 'Ada'", nil},
+		{"SELECT * FROM users WHERE name = 'ada'", nil},
+		{"SELECT * FROM users WHERE name = 'a  b'", nil},
+		{"SELECT * FROM users WHERE name = 'a b'", nil},
+		{"SELECT * FROM users WHERE a = $1 AND b = $2", []any{"x:y", "z"}},
+		{"SELECT * FROM users WHERE a = $1 AND b = $2", []any{"x", "y:z"}},
+	}
+
+	seen := make(map[string]int)
+	for i, c := range cases {
+		fp, err := QueryFingerprint(c.query, c.args...)
+		require.NoError(t, err)
+		if prev, dup := seen[fp]; dup {
+			t.Fatalf("cases %d and %d share fingerprint %s", prev, i, fp)
+		}
+		seen[fp] = i
+	}
+}
+
+func TestQueryFingerprintRejectsUnencodableArgs(t *testing.T) {
+	t.Parallel()
+
+	_, err := QueryFingerprint("SELECT 1", make(chan int))
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package cache_manager
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// keyedMutexShards is the number of maps KeyedMutex spreads its keys over.
+const keyedMutexShards = 64
+
+// KeyedMutex provides one mutex per key for read-modify-write sequences, so unrelated
+// keys never contend. Entries are reference counted and removed once no goroutine holds
+// or waits on them, so the map only grows with the number of keys in use. The zero value
+// is ready to use.
+type KeyedMutex struct {
+	shards [keyedMutexShards]keyedMutexShard
+}
+
+type keyedMutexShard struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int // holders and waiters, guarded by the shard mutex
+}
+
+// Lock blocks until key is free and returns the function that releases it.
+func (k *KeyedMutex) Lock(key string) (unlock func()) {
+	s := k.shard(key)
+	l := s.acquire(key)
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		s.release(key, l)
+	}
+}
+
+// TryLock takes key only if it is free. It reports whether the lock was taken; unlock
+// is nil when it was not.
+func (k *KeyedMutex) TryLock(key string) (unlock func(), ok bool) {
+	s := k.shard(key)
+	l := s.acquire(key)
+	if !l.mu.TryLock() {
+		s.release(key, l)
+		return nil, false
+	}
+	return func() {
+		l.mu.Unlock()
+		s.release(key, l)
+	}, true
+}
+
+// len reports how many keys currently have an entry.
+func (k *KeyedMutex) len() int {
+	n := 0
+	for i := range k.shards {
+		s := &k.shards[i]
+		s.mu.Lock()
+		n += len(s.locks)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+func (k *KeyedMutex) shard(key string) *keyedMutexShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &k.shards[h.Sum32()%keyedMutexShards]
+}
+
+func (s *keyedMutexShard) acquire(key string) *keyedLock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks == nil {
+		s.locks = make(map[string]*keyedLock)
+	}
+	l, ok := s.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		s.locks[key] = l
+	}
+	l.refs++
+	return l
+}
+
+func (s *keyedMutexShard) release(key string, l *keyedLock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(s.locks, key)
+	}
+}
@@ -0,0 +1,117 @@
+package cache_manager
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedMutexSerialisesSameKeyAcrossThousandsOfKeys(t *testing.T) {
+	t.Parallel()
+
+	const keys, workers, rounds = 2000, 8, 50
+	var km KeyedMutex
+	counters := make([]int, keys) // written without atomics; the race detector checks the locking
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				for i := 0; i < keys; i++ {
+					k := (i + w*keys/workers) % keys
+					unlock := km.Lock(fmt.Sprintf("key:%d", k))
+					counters[k]++
+					unlock()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for k, n := range counters {
+		require.Equal(t, workers*rounds, n, "key %d", k)
+	}
+	require.Zero(t, km.len(), "released keys must not keep entries")
+}
+
+func TestKeyedMutexTryLock(t *testing.T) {
+	t.Parallel()
+
+	var km KeyedMutex
+	unlock, ok := km.TryLock("a")
+	require.True(t, ok)
+
+	_, ok = km.TryLock("a")
+	require.False(t, ok)
+
+	unlockB, ok := km.TryLock("b")
+	require.True(t, ok)
+	unlockB()
+
+	unlock()
+	unlock, ok = km.TryLock("a")
+	require.True(t, ok)
+	unlock()
+	require.Zero(t, km.len())
+}
+
+// benchmarkLockedWork simulates a short read-modify-write inside the critical section.
+func benchmarkLockedWork(buf []byte) {
+	for i := range buf {
+		buf[i]++
+	}
+}
+
+func BenchmarkKeyedMutexContended(b *testing.B) {
+	var km KeyedMutex
+	keys := make([]string, 1024)
+	bufs := make([][]byte, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+		bufs[i] = make([]byte, 4096)
+	}
+
+	var next sync.Mutex
+	seed := 0
+	b.RunParallel(func(pb *testing.PB) {
+		next.Lock()
+		i := seed * 97
+		seed++
+		next.Unlock()
+		for pb.Next() {
+			k := i % len(keys)
+			unlock := km.Lock(keys[k])
+			benchmarkLockedWork(bufs[k])
+			unlock()
+			i++
+		}
+	})
+}
+
+func BenchmarkGlobalMutexContended(b *testing.B) {
+	var mu sync.Mutex
+	bufs := make([][]byte, 1024)
+	for i := range bufs {
+		bufs[i] = make([]byte, 4096)
+	}
+
+	var next sync.Mutex
+	seed := 0
+	b.RunParallel(func(pb *testing.PB) {
+		next.Lock()
+		i := seed * 97
+		seed++
+		next.Unlock()
+		for pb.Next() {
+			k := i % len(bufs)
+			mu.Lock()
+			benchmarkLockedWork(bufs[k])
+			mu.Unlock()
+			i++
+		}
+	})
+}
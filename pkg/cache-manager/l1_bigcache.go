@@ -1,9 +1,14 @@
 package cache_manager
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/allegro/bigcache/v3"
@@ -11,12 +16,88 @@ import (
 
 // BigCache wraps github.com/allegro/bigcache for L1 caching.
 type BigCache struct {
-	cache *bigcache.BigCache
+	// mu guards the cache and resizing pointers: operations hold it for reading, Resize
+	// takes it for writing to copy an entry or swap caches.
+	mu       sync.RWMutex
+	cache    *bigcache.BigCache
+	resizing *bigcache.BigCache // the replacement being filled by Resize, else nil
+	resizeMu sync.Mutex         // one Resize (or Close) at a time
+	config   bigcache.Config    // as passed to bigcache.New, for Resize
+	ctx      context.Context    // as passed to NewBigCache, for Resize
+
+	restorePath string
+	onEvict     func(key string, reason EvictionReason, size int)
+	listeners   atomic.Pointer[[]func(string, EvictionReason, int)] // OnEviction callbacks
+	listenerMu  sync.Mutex                                          // serialises OnEviction
+	evictions   [evictionReasonCount]atomic.Uint64
+	copyOnRead  bool
+	writeLocks  KeyedMutex // serialises writes with CopyOnRead rewrites
+	clock       Clock
+	hotKeys     *HotKeyDetector // nil unless TrackHotKeys
+	staleGrace  int64           // nanoseconds, BigCacheConfig.StaleGrace
 }
 
 // BigCacheConfig allows customizing the underlying cache.
 type BigCacheConfig struct {
 	Config bigcache.Config
+	// RestorePath enables L1 persistence across restarts. When set, entries are
+	// restored from this file on construction and snapshotted to it on Close.
+	RestorePath string
+	// OnEvict is called whenever bigcache removes an entry, with the reason and the
+	// payload size. It runs on bigcache's write path and must be cheap. Eviction counts
+	// are also available from Evictions. Both are unavailable when Config sets
+	// OnRemoveWithMetadata, which bigcache gives precedence.
+	OnEvict func(key string, reason EvictionReason, size int)
+	// CopyOnRead makes TTLs sliding: a Get that finds less than half of an entry's
+	// original TTL left rewrites the entry with a fresh expiry of the full original TTL.
+	// Entries without a TTL are left alone. Writes then take a per-key lock so a rewrite
+	// never replaces a newer value.
+	CopyOnRead bool
+	// Clock decides entry expiry. nil uses the system clock; tests can pass a fake.
+	// bigcache's own LifeWindow and CleanWindow still run on real time.
+	Clock Clock
+	// TrackHotKeys counts Get and Set calls per shard; read them with HotKeys.
+	TrackHotKeys bool
+	// StaleGrace keeps expired entries this long past their expiry, so GetStale can
+	// still serve them (see MultiLevelConfig.ServeStaleOnError). Get reports them as
+	// missing all the same. At most MaxStaleGrace; bigcache's LifeWindow still applies.
+	StaleGrace time.Duration
+}
+
+// EvictionReason explains why an L1 entry was removed.
+type EvictionReason int
+
+const (
+	// EvictionExpired means the entry had expired, either by bigcache's LifeWindow or by
+	// its own TTL, including entries pushed out for space that were already dead.
+	EvictionExpired EvictionReason = iota
+	// EvictionNoSpace means a live entry was dropped because the cache hit HardMaxCacheSize
+	// or the entry did not fit in a shard; frequent ones suggest L1 is undersized.
+	EvictionNoSpace
+	// EvictionDeleted means the entry was removed by an explicit Delete.
+	EvictionDeleted
+
+	evictionReasonCount = iota
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionExpired:
+		return "expired"
+	case EvictionNoSpace:
+		return "no_space"
+	case EvictionDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// EvictionCounts reports how many L1 entries were removed for each reason.
+type EvictionCounts struct {
+	Expired uint64 `json:"expired"`
+	NoSpace uint64 `json:"no_space"`
+	Deleted uint64 `json:"deleted"`
 }
 
 // NewBigCache constructs a BigCache instance.
@@ -48,89 +129,427 @@ func NewBigCache(ctx context.Context, cfg BigCacheConfig) (*BigCache, error) {
 	config.Verbose = cfg.Config.Verbose
 	config.Hasher = cfg.Config.Hasher
 	config.Logger = cfg.Config.Logger
-	config.OnRemove = cfg.Config.OnRemove
 	config.OnRemoveWithMetadata = cfg.Config.OnRemoveWithMetadata
-	config.OnRemoveWithReason = cfg.Config.OnRemoveWithReason
+
+	if cfg.StaleGrace < 0 || cfg.StaleGrace > MaxStaleGrace {
+		return nil, &CacheError{Op: "new", Level: LevelL1, Cause: fmt.Errorf("stale grace %s must be between 0 and %s", cfg.StaleGrace, MaxStaleGrace)}
+	}
+
+	b := &BigCache{restorePath: cfg.RestorePath, onEvict: cfg.OnEvict, copyOnRead: cfg.CopyOnRead, clock: clockOrSystem(cfg.Clock), staleGrace: int64(cfg.StaleGrace)}
+	config.OnRemoveWithReason = b.onRemove(cfg.Config.OnRemove, cfg.Config.OnRemoveWithReason)
 
 	bc, err := bigcache.New(ctx, config)
 	if err != nil {
-		return nil, err
+		return nil, &CacheError{Op: "new", Level: LevelL1, Cause: err}
+	}
+	b.cache, b.config, b.ctx = bc, config, ctx
+	if cfg.TrackHotKeys {
+		b.hotKeys = NewHotKeyDetector(config.Shards, config.Hasher)
+	}
+	if b.restorePath != "" {
+		b.restoreSnapshot()
+	}
+
+	return b, nil
+}
+
+// onRemove builds the bigcache removal callback that classifies and counts evictions,
+// then forwards to any callbacks set on the user's bigcache.Config.
+func (b *BigCache) onRemove(userOnRemove func(string, []byte), userOnRemoveWithReason func(string, []byte, bigcache.RemoveReason)) func(string, []byte, bigcache.RemoveReason) {
+	return func(key string, entry []byte, reason bigcache.RemoveReason) {
+		evictReason := EvictionDeleted
+		switch reason {
+		case bigcache.Expired:
+			evictReason = EvictionExpired
+		case bigcache.NoSpace:
+			evictReason = EvictionNoSpace
+			if len(entry) >= entryHeaderSize && entryExpired(entry, b.now()) {
+				evictReason = EvictionExpired
+			}
+		}
+		b.evictions[evictReason].Add(1)
+
+		size := max(0, len(entry)-entryHeaderSize)
+		if b.onEvict != nil {
+			b.onEvict(key, evictReason, size)
+		}
+		if listeners := b.listeners.Load(); listeners != nil {
+			for _, fn := range *listeners {
+				fn(key, evictReason, size)
+			}
+		}
+		if userOnRemove != nil {
+			userOnRemove(key, entry)
+		}
+		if userOnRemoveWithReason != nil {
+			userOnRemoveWithReason(key, entry, reason)
+		}
+	}
+}
+
+// Stats returns bigcache's hit, miss, delete and collision counters.
+func (b *BigCache) Stats(ctx context.Context) (LevelStats, error) {
+	release := b.rlock()
+	if release == nil {
+		return LevelStats{}, &CacheError{Op: "stats", Level: LevelL1, Cause: ErrNotInitialized}
+	}
+	defer release()
+	s := b.cache.Stats()
+	return LevelStats{
+		Hits:       s.Hits,
+		Misses:     s.Misses,
+		DelHits:    s.DelHits,
+		DelMisses:  s.DelMisses,
+		Collisions: s.Collisions,
+	}, nil
+}
+
+// HotKeys returns the per-shard traffic counter, or nil unless
+// BigCacheConfig.TrackHotKeys is set.
+func (b *BigCache) HotKeys() *HotKeyDetector {
+	if b == nil {
+		return nil
 	}
+	return b.hotKeys
+}
 
-	return &BigCache{cache: bc}, nil
+// Evictions returns the number of entries removed per reason since construction.
+func (b *BigCache) Evictions() EvictionCounts {
+	return EvictionCounts{
+		Expired: b.evictions[EvictionExpired].Load(),
+		NoSpace: b.evictions[EvictionNoSpace].Load(),
+		Deleted: b.evictions[EvictionDeleted].Load(),
+	}
 }
 
-// Close shuts down the cache.
+// Close shuts down the cache, writing a snapshot first when RestorePath is set.
 func (b *BigCache) Close() error {
-	if b == nil || b.cache == nil {
+	if b == nil {
+		return nil
+	}
+	b.resizeMu.Lock()
+	defer b.resizeMu.Unlock()
+	release := b.rlock()
+	if release == nil {
 		return nil
 	}
-	return b.cache.Close()
+	defer release()
+	var snapErr error
+	if b.restorePath != "" {
+		snapErr = b.writeSnapshot()
+	}
+	return errors.Join(snapErr, b.cache.Close())
 }
 
 // Get returns payload if present and not expired.
 func (b *BigCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
-	if b == nil || b.cache == nil {
-		return nil, false, errors.New("bigcache not initialized")
+	payload, _, ok, err := b.GetWithPriority(ctx, key)
+	return payload, ok, err
+}
+
+// GetWithPriority returns the payload together with the priority it was stored with.
+func (b *BigCache) GetWithPriority(ctx context.Context, key string) ([]byte, int8, bool, error) {
+	release := b.rlock()
+	if release == nil {
+		return nil, 0, false, &CacheError{Op: "get", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
 	}
+	defer release()
+	b.hotKeys.Record(key)
 
 	data, err := b.cache.Get(key)
 	if err != nil {
 		if errors.Is(err, bigcache.ErrEntryNotFound) {
-			return nil, false, nil
+			return nil, 0, false, nil
 		}
-		return nil, false, err
+		return nil, 0, false, &CacheError{Op: "get", Level: LevelL1, Key: key, Cause: err}
 	}
 
-	payload, ok := decodeEntry(data)
+	now := b.now()
+	payload, priority, ok := decodeEntry(data, now)
 	if !ok {
-		_ = b.cache.Delete(key)
-		return nil, false, nil
+		if b.removable(data, now) {
+			_ = b.delete(key)
+		}
+		return nil, 0, false, nil
+	}
+	if b.copyOnRead {
+		b.slideExpiry(key, data, payload, priority)
+	}
+
+	return payload, priority, true, nil
+}
+
+// slideExpiry rewrites the entry with a full original TTL once less than half of it is
+// left. A failed rewrite keeps the old entry, so it is not reported.
+func (b *BigCache) slideExpiry(key string, raw, payload []byte, priority int8) {
+	expiry, originalTTL := entryExpiry(raw), entryOriginalTTL(raw)
+	if expiry == 0 || originalTTL <= 0 {
+		return
+	}
+	now := b.clock.Now()
+	if remaining := time.Duration(expiry - now.UnixNano()); remaining >= originalTTL/2 {
+		return
+	}
+
+	unlock := b.writeLocks.Lock(key)
+	defer unlock()
+	if current, err := b.cache.Get(key); err != nil || !bytes.Equal(current, raw) {
+		return // written or removed since it was read
+	}
+	_ = b.set(key, encodeEntryAt(payload, now.Add(originalTTL).UnixNano(), originalTTL, priority))
+}
+
+// rlock read-locks b against a Resize swap and returns the unlock func, or nil when b
+// was not built by NewBigCache.
+func (b *BigCache) rlock() func() {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	if b.cache == nil {
+		b.mu.RUnlock()
+		return nil
+	}
+	return b.mu.RUnlock
+}
+
+// set writes an encoded entry, to the replacement cache too while Resize fills it. The
+// caller holds b.mu.
+func (b *BigCache) set(key string, entry []byte) error {
+	if b.resizing != nil {
+		_ = b.resizing.Set(key, entry)
+	}
+	return b.cache.Set(key, entry)
+}
+
+// delete removes key, from the replacement cache too while Resize fills it. The caller
+// holds b.mu.
+func (b *BigCache) delete(key string) error {
+	if b.resizing != nil {
+		_ = b.resizing.Delete(key)
 	}
+	return b.cache.Delete(key)
+}
+
+// now returns the current time of the configured clock in UnixNano.
+func (b *BigCache) now() int64 {
+	return b.clock.Now().UnixNano()
+}
 
-	return payload, true, nil
+// lockWrite takes the per-key write lock when CopyOnRead is enabled.
+func (b *BigCache) lockWrite(key string) func() {
+	if !b.copyOnRead {
+		return func() {}
+	}
+	return b.writeLocks.Lock(key)
 }
 
 // Set stores payload with TTL metadata.
 func (b *BigCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	if b == nil || b.cache == nil {
-		return errors.New("bigcache not initialized")
+	return b.SetWithPriority(ctx, key, value, ttl, 0)
+}
+
+// SetWithPriority stores payload with TTL metadata and a priority.
+// Entries with priority > 0 expire for reads like any other, but BigCacheJanitor and
+// PurgeExpired skip them, so they stay in L1 until deleted or evicted by bigcache
+// itself (LifeWindow / HardMaxCacheSize).
+func (b *BigCache) SetWithPriority(ctx context.Context, key string, value []byte, ttl time.Duration, priority int8) error {
+	release := b.rlock()
+	if release == nil {
+		return &CacheError{Op: "set", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
 	}
+	defer release()
+	b.hotKeys.Record(key)
 
-	entry := encodeEntry(value, ttl)
-	return b.cache.Set(key, entry)
+	entry := encodeEntry(value, ttl, priority, b.clock.Now())
+	unlock := b.lockWrite(key)
+	defer unlock()
+	if err := b.set(key, entry); err != nil {
+		return &CacheError{Op: "set", Level: LevelL1, Key: key, Cause: err}
+	}
+	return nil
 }
 
 // Delete removes an entry.
 func (b *BigCache) Delete(ctx context.Context, key string) error {
-	if b == nil || b.cache == nil {
-		return errors.New("bigcache not initialized")
+	release := b.rlock()
+	if release == nil {
+		return &CacheError{Op: "delete", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
 	}
-	return b.cache.Delete(key)
+	defer release()
+	unlock := b.lockWrite(key)
+	defer unlock()
+	if err := b.delete(key); err != nil && !errors.Is(err, bigcache.ErrEntryNotFound) {
+		return &CacheError{Op: "delete", Level: LevelL1, Key: key, Cause: err}
+	}
+	return nil
 }
 
-func encodeEntry(payload []byte, ttl time.Duration) []byte {
+// Entries are stored as [8 bytes expiry UnixNano, 0 = no TTL][8 bytes original TTL in
+// nanoseconds][1 byte priority][payload].
+const (
+	entryOriginalTTLOffset = 8
+	entryPriorityOffset    = 16
+	entryHeaderSize        = 17
+)
+
+// encodeEntry encodes payload to expire ttl after now.
+func encodeEntry(payload []byte, ttl time.Duration, priority int8, now time.Time) []byte {
 	expiry := int64(0)
 	if ttl > 0 {
-		expiry = time.Now().Add(ttl).UnixNano()
+		expiry = now.Add(ttl).UnixNano()
+	} else {
+		ttl = 0
 	}
+	return encodeEntryAt(payload, expiry, ttl, priority)
+}
 
-	out := make([]byte, 8+len(payload))
-	binary.LittleEndian.PutUint64(out[:8], uint64(expiry))
-	copy(out[8:], payload)
+// encodeEntryAt encodes payload with an absolute expiry (UnixNano, 0 = no TTL) and the
+// TTL the entry was originally stored with.
+func encodeEntryAt(payload []byte, expiry int64, originalTTL time.Duration, priority int8) []byte {
+	out := make([]byte, entryHeaderSize+len(payload))
+	binary.LittleEndian.PutUint64(out[:entryOriginalTTLOffset], uint64(expiry))
+	binary.LittleEndian.PutUint64(out[entryOriginalTTLOffset:entryPriorityOffset], uint64(originalTTL))
+	out[entryPriorityOffset] = byte(priority)
+	copy(out[entryHeaderSize:], payload)
 	return out
 }
 
-func decodeEntry(raw []byte) ([]byte, bool) {
-	if len(raw) < 8 {
-		return nil, false
+// decodeEntry returns a copy of the payload and its priority, or ok=false when the
+// entry is malformed or expired at now (UnixNano).
+func decodeEntry(raw []byte, now int64) ([]byte, int8, bool) {
+	if len(raw) < entryHeaderSize {
+		return nil, 0, false
+	}
+	if entryExpired(raw, now) {
+		return nil, 0, false
+	}
+	cp := make([]byte, len(raw)-entryHeaderSize)
+	copy(cp, raw[entryHeaderSize:])
+	return cp, entryPriority(raw), true
+}
+
+// entryExpiry reads the expiry header (UnixNano, 0 = no TTL) from an encoded entry.
+func entryExpiry(raw []byte) int64 {
+	return int64(binary.LittleEndian.Uint64(raw[:entryOriginalTTLOffset]))
+}
+
+// entryOriginalTTL reads the TTL the entry was stored with (0 = no TTL).
+func entryOriginalTTL(raw []byte) time.Duration {
+	return time.Duration(binary.LittleEndian.Uint64(raw[entryOriginalTTLOffset:entryPriorityOffset]))
+}
+
+// entryPriority reads the priority byte from an encoded entry.
+func entryPriority(raw []byte) int8 {
+	return int8(raw[entryPriorityOffset])
+}
+
+// removable reports whether an encoded entry is malformed, or has priority <= 0 and
+// is expired for longer than the stale grace window at now, so a sweep can delete it.
+func (b *BigCache) removable(raw []byte, now int64) bool {
+	if len(raw) < entryHeaderSize {
+		return true
+	}
+	return entryPriority(raw) <= 0 && entryExpired(raw, now-b.staleGrace)
+}
+
+// entryExpired reports whether an encoded entry is past its expiry at now.
+func entryExpired(raw []byte, now int64) bool {
+	expiry := entryExpiry(raw)
+	return expiry > 0 && now > expiry
+}
+
+// SetKeepTTL stores value under key with the expiry and priority of the existing live entry,
+// or with ttl and no priority when the key is absent or expired.
+func (b *BigCache) SetKeepTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	release := b.rlock()
+	if release == nil {
+		return &CacheError{Op: "set", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
+	}
+	defer release()
+
+	unlock := b.lockWrite(key)
+	defer unlock()
+	entry := encodeEntry(value, ttl, 0, b.clock.Now())
+	if raw, err := b.cache.Get(key); err == nil && len(raw) >= entryHeaderSize && !entryExpired(raw, b.now()) {
+		entry = encodeEntryAt(value, entryExpiry(raw), entryOriginalTTL(raw), entryPriority(raw))
 	}
-	expiry := int64(binary.LittleEndian.Uint64(raw[:8]))
-	if expiry > 0 && time.Now().UnixNano() > expiry {
-		return nil, false
+	if err := b.set(key, entry); err != nil {
+		return &CacheError{Op: "set", Level: LevelL1, Key: key, Cause: err}
 	}
-	cp := make([]byte, len(raw)-8)
-	copy(cp, raw[8:])
-	return cp, true
+	return nil
 }
 
+// Expire rewrites the entry header of key with a new TTL, keeping its payload and priority.
+// It reports false when the key is missing or already expired.
+func (b *BigCache) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	payload, priority, found, err := b.GetWithPriority(ctx, key)
+	if err != nil || !found {
+		return false, err
+	}
+	if err := b.SetWithPriority(ctx, key, payload, ttl, priority); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TTL reports the remaining lifetime of key. A zero duration with found=true means no expiry.
+func (b *BigCache) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	release := b.rlock()
+	if release == nil {
+		return 0, false, &CacheError{Op: "ttl", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
+	}
+	defer release()
+
+	raw, err := b.cache.Get(key)
+	if err != nil {
+		if errors.Is(err, bigcache.ErrEntryNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, &CacheError{Op: "ttl", Level: LevelL1, Key: key, Cause: err}
+	}
+	now := b.now()
+	if len(raw) < entryHeaderSize || entryExpired(raw, now) {
+		return 0, false, nil
+	}
+
+	expiry := entryExpiry(raw)
+	if expiry == 0 {
+		return 0, true, nil
+	}
+	return time.Duration(expiry - now), true, nil
+}
+
+// Keys returns the non-expired keys matching the glob pattern (path.Match syntax).
+func (b *BigCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	release := b.rlock()
+	if release == nil {
+		return nil, &CacheError{Op: "keys", Level: LevelL1, Cause: ErrNotInitialized}
+	}
+	defer release()
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	now := b.now()
+	var keys []string
+	it := b.cache.Iterator()
+	for it.SetNext() {
+		info, err := it.Value()
+		if err != nil {
+			continue
+		}
+		raw := info.Value()
+		if len(raw) < entryHeaderSize || entryExpired(raw, now) {
+			continue
+		}
+		ok, err := path.Match(pattern, info.Key())
+		if err != nil {
+			return nil, &CacheError{Op: "keys", Level: LevelL1, Cause: err}
+		}
+		if ok {
+			keys = append(keys, info.Key())
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,85 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func newCopyOnReadBigCache(t *testing.T, clock Clock, copyOnRead bool) *BigCache {
+	t.Helper()
+
+	cfg := bigcache.DefaultConfig(time.Minute)
+	cfg.Verbose = false
+	return newFakeClockBigCache(t, clock, BigCacheConfig{Config: cfg, CopyOnRead: copyOnRead})
+}
+
+func TestBigCacheCopyOnReadExtendsTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	sliding := newCopyOnReadBigCache(t, clock, true)
+	fixed := newCopyOnReadBigCache(t, clock, false)
+	for _, bc := range []*BigCache{sliding, fixed} {
+		require.NoError(t, bc.Set(ctx, "session:1", []byte("v"), 200*time.Millisecond))
+	}
+
+	// Less than half of the TTL is left, so the sliding read rewrites the expiry.
+	clock.Advance(120 * time.Millisecond)
+	for _, bc := range []*BigCache{sliding, fixed} {
+		_, found, err := bc.Get(ctx, "session:1")
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+
+	ttl, found, err := sliding.TTL(ctx, "session:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 200*time.Millisecond, ttl)
+
+	ttl, found, err = fixed.TTL(ctx, "session:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 80*time.Millisecond, ttl)
+
+	// Past the original expiry only the sliding entry is still there.
+	clock.Advance(120 * time.Millisecond)
+	_, found, err = sliding.Get(ctx, "session:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	_, found, err = fixed.Get(ctx, "session:1")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestBigCacheCopyOnReadKeepsFreshEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc := newCopyOnReadBigCache(t, newFakeClock(), true)
+	require.NoError(t, bc.Set(ctx, "user:1", []byte("v"), time.Minute))
+	require.NoError(t, bc.Set(ctx, "config", []byte("v"), 0))
+
+	raw, err := bc.cache.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, entryOriginalTTL(raw))
+
+	_, found, err := bc.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	after, err := bc.cache.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, raw, after, "entries with more than half their TTL left are not rewritten")
+
+	_, found, err = bc.Get(ctx, "config")
+	require.NoError(t, err)
+	require.True(t, found)
+	ttl, found, err := bc.TTL(ctx, "config")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Zero(t, ttl)
+}
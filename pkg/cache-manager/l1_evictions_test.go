@@ -0,0 +1,77 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigCacheReportsNoSpaceEvictions(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	reasons := map[EvictionReason]int{}
+	var sizes []int
+
+	cfg := bigcache.DefaultConfig(time.Hour)
+	cfg.Shards = 1
+	cfg.HardMaxCacheSize = 1 // MB
+	cfg.Verbose = false
+	bc, err := NewBigCache(context.Background(), BigCacheConfig{
+		Config: cfg,
+		OnEvict: func(key string, reason EvictionReason, size int) {
+			mu.Lock()
+			defer mu.Unlock()
+			reasons[reason]++
+			sizes = append(sizes, size)
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	ctx := context.Background()
+	payload := bytes.Repeat([]byte("x"), 10*1024)
+	for i := 0; i < 300; i++ {
+		require.NoError(t, bc.Set(ctx, fmt.Sprintf("key:%d", i), payload, time.Hour))
+	}
+	require.NoError(t, bc.Delete(ctx, "key:299"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Positive(t, reasons[EvictionNoSpace])
+	require.Equal(t, 1, reasons[EvictionDeleted])
+	require.Contains(t, sizes, len(payload))
+
+	counts := bc.Evictions()
+	require.Equal(t, uint64(reasons[EvictionNoSpace]), counts.NoSpace)
+	require.Equal(t, uint64(1), counts.Deleted)
+}
+
+func TestBigCacheClassifiesDeadEntriesAsExpired(t *testing.T) {
+	t.Parallel()
+
+	cfg := bigcache.DefaultConfig(time.Hour)
+	cfg.Shards = 1
+	cfg.HardMaxCacheSize = 1
+	cfg.Verbose = false
+	bc, err := NewBigCache(context.Background(), BigCacheConfig{Config: cfg})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	ctx := context.Background()
+	payload := bytes.Repeat([]byte("x"), 10*1024)
+	// Entries whose own TTL has already passed are still in bigcache when space runs out.
+	for i := 0; i < 300; i++ {
+		require.NoError(t, bc.Set(ctx, fmt.Sprintf("key:%d", i), payload, time.Nanosecond))
+	}
+
+	counts := bc.Evictions()
+	require.Positive(t, counts.Expired)
+	require.Zero(t, counts.NoSpace)
+}
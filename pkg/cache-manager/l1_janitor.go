@@ -0,0 +1,114 @@
+package cache_manager
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BigCacheJanitor periodically sweeps expired entries out of a BigCache instead of
+// waiting for them to be read or aged out by bigcache's LifeWindow. Entries stored
+// with priority > 0 are skipped even when past their expiry.
+type BigCacheJanitor struct {
+	cache    *BigCache
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewBigCacheJanitor creates a janitor for cache. Call Start to begin periodic sweeps.
+func NewBigCacheJanitor(cache *BigCache, interval time.Duration) *BigCacheJanitor {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &BigCacheJanitor{
+		cache:    cache,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs Sweep every interval until Stop is called.
+func (j *BigCacheJanitor) Start() {
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if removed := j.Sweep(); removed > 0 {
+					slog.Debug("l1 janitor sweep", "removed", removed)
+				}
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts periodic sweeps and waits for an in-flight sweep to finish.
+func (j *BigCacheJanitor) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stop)
+		<-j.done
+	})
+}
+
+// Sweep removes every expired, non-priority entry and returns how many were removed.
+func (j *BigCacheJanitor) Sweep() int {
+	removed, _ := j.cache.PurgeExpired(context.Background())
+	return int(removed)
+}
+
+// PurgeExpired removes every expired, non-priority entry, once past StaleGrace, and
+// returns how many were removed. It is the janitor's sweep as an on-demand call; bigcache's own CleanWindow
+// only honors LifeWindow, not the per-key TTLs in the entry headers. When ctx ends
+// mid-scan, the entries found so far are still removed and ctx's error is returned.
+func (b *BigCache) PurgeExpired(ctx context.Context) (int64, error) {
+	release := b.rlock()
+	if release == nil {
+		return 0, nil
+	}
+	now := b.now()
+	var expired []string
+	it := b.cache.Iterator()
+	for ctx.Err() == nil && it.SetNext() {
+		info, err := it.Value()
+		if err != nil {
+			continue
+		}
+		if b.removable(info.Value(), now) {
+			expired = append(expired, info.Key())
+		}
+	}
+	release()
+
+	var removed int64
+	for _, key := range expired {
+		if b.purgeIfRemovable(key, now) {
+			removed++
+		}
+	}
+	return removed, ctx.Err()
+}
+
+// purgeIfRemovable deletes key if it is still removable at now. It holds b.mu
+// exclusively, as Resize does, so no Set can land between the re-check and the delete
+// and lose its fresh value.
+func (b *BigCache) purgeIfRemovable(key string, now int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cache == nil {
+		return false
+	}
+	raw, err := b.cache.Get(key)
+	if err != nil || !b.removable(raw, now) {
+		return false
+	}
+	return b.delete(key) == nil
+}
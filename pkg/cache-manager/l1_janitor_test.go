@@ -0,0 +1,68 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigCacheJanitorSkipsHighPriorityEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	bc, err := NewBigCache(ctx, BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute), Clock: clock})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	require.NoError(t, bc.SetWithPriority(ctx, "config:flags", []byte("critical"), 20*time.Millisecond, 10))
+	require.NoError(t, bc.Set(ctx, "user:1", []byte("regular"), 20*time.Millisecond))
+	require.NoError(t, bc.Set(ctx, "user:2", []byte("fresh"), time.Minute))
+
+	clock.Advance(40 * time.Millisecond)
+
+	removed := NewBigCacheJanitor(bc, time.Minute).Sweep()
+	require.Equal(t, 1, removed)
+
+	raw, err := bc.cache.Get("config:flags")
+	require.NoError(t, err, "high-priority entry should survive the sweep")
+	require.Equal(t, int8(10), entryPriority(raw))
+	require.Equal(t, []byte("critical"), raw[entryHeaderSize:])
+
+	// The sweep keeps it, but reads still honor its TTL
+	_, _, ok, err := bc.GetWithPriority(ctx, "config:flags")
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok, err = bc.TTL(ctx, "config:flags")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = bc.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = bc.Get(ctx, "user:2")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestMultiLevelCacheSetPassesPriorityToL1(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc, err := NewBigCache(ctx, BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	ml, err := NewMultiLevelCache(bc, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(ctx, "config:flags", "on", CacheOptions{Priority: 5}))
+
+	_, priority, ok, err := bc.GetWithPriority(ctx, "config:flags")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int8(5), priority)
+}
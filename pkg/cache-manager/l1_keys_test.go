@@ -0,0 +1,98 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// newL1KeysTestCache returns a both-levels cache over a real BigCache seeded with 30 user
+// keys and 20 session keys. The session keys are also in L2.
+func newL1KeysTestCache(t *testing.T) (*MultiLevelCache, *memoryRawCache) {
+	t.Helper()
+
+	ctx := context.Background()
+	bcConfig := bigcache.DefaultConfig(time.Minute)
+	bcConfig.Verbose = false
+	l1, err := NewBigCache(ctx, BigCacheConfig{Config: bcConfig})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	l2 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+
+	for i := 0; i < 30; i++ {
+		require.NoError(t, ml.Set(ctx, UserCacheKey(i), i, CacheOptions{TargetL2: BoolPtr(false)}))
+	}
+	for i := 0; i < 20; i++ {
+		require.NoError(t, ml.Set(ctx, fmt.Sprintf("session:%d", i), i, CacheOptions{}))
+	}
+	require.NoError(t, l2.Set(ctx, "user:l2-only", []byte("1"), time.Minute))
+	return ml, l2
+}
+
+func TestListL1Keys(t *testing.T) {
+	t.Parallel()
+
+	ml, _ := newL1KeysTestCache(t)
+	ctx := context.Background()
+
+	keys, err := ml.ListL1Keys(ctx, "user:*")
+	require.NoError(t, err)
+	require.Len(t, keys, 30)
+	require.IsIncreasing(t, keys)
+	require.NotContains(t, keys, "user:l2-only")
+
+	all, err := ml.ListL1Keys(ctx, "*")
+	require.NoError(t, err)
+	require.Len(t, all, 50)
+
+	none, err := ml.ListL1Keys(ctx, "order:*")
+	require.NoError(t, err)
+	require.Empty(t, none)
+}
+
+func TestL1KeysHandler(t *testing.T) {
+	t.Parallel()
+
+	ml, _ := newL1KeysTestCache(t)
+	handler := NewL1KeysHandler(ml, "secret")
+
+	get := func(target, token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/cache/keys?match=session:1*", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var keys []string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &keys))
+	require.Equal(t, []string{"session:1", "session:10", "session:11", "session:12", "session:13",
+		"session:14", "session:15", "session:16", "session:17", "session:18", "session:19"}, keys)
+
+	rec = get("/cache/keys", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &keys))
+	require.Len(t, keys, 50)
+
+	rec = get("/cache/keys?match=missing:*", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[]`, rec.Body.String())
+
+	require.Equal(t, http.StatusBadRequest, get("/cache/keys?match=%5B", "secret").Code)
+	require.Equal(t, http.StatusUnauthorized, get("/cache/keys", "").Code)
+	require.Equal(t, http.StatusUnauthorized, get("/cache/keys", "wrong").Code)
+}
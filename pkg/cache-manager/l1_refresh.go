@@ -0,0 +1,50 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+// GetAndRefresh returns the payload of key and restarts its TTL as newTTL (0 = no
+// expiry) in one step, keeping its priority, so the TTL cannot be extended on a value a
+// concurrent Set or Delete already replaced. It reports false, without writing, when
+// the key is missing or expired.
+//
+// bigcache exposes no shard lock to hold across its Get and Set, so GetAndRefresh takes
+// BigCache's own lock exclusively, the way Resize copies an entry: every other BigCache
+// call waits for one bigcache read and one write. That is cheap per call but serialises
+// the whole cache, so use it where the read-and-extend must be atomic, not on every read.
+func (b *BigCache) GetAndRefresh(ctx context.Context, key string, newTTL time.Duration) ([]byte, bool, error) {
+	if b == nil {
+		return nil, false, &CacheError{Op: "refresh", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cache == nil {
+		return nil, false, &CacheError{Op: "refresh", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
+	}
+	b.hotKeys.Record(key)
+
+	raw, err := b.cache.Get(key)
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, &CacheError{Op: "refresh", Level: LevelL1, Key: key, Cause: err}
+	}
+	now := b.clock.Now()
+	payload, priority, ok := decodeEntry(raw, now.UnixNano())
+	if !ok {
+		if b.removable(raw, now.UnixNano()) {
+			_ = b.delete(key)
+		}
+		return nil, false, nil
+	}
+	if err := b.set(key, encodeEntry(payload, newTTL, priority, now)); err != nil {
+		return nil, false, &CacheError{Op: "refresh", Level: LevelL1, Key: key, Cause: err}
+	}
+	return payload, true, nil
+}
@@ -0,0 +1,89 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigCacheGetAndRefreshExtendsTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{})
+	require.NoError(t, bc.SetWithPriority(ctx, "k", []byte("value"), 10*time.Second, 0))
+
+	clock.Advance(8 * time.Second)
+	data, ok, err := bc.GetAndRefresh(ctx, "k", 30*time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("value"), data)
+
+	clock.Advance(20 * time.Second) // 28s after the Set, past its original 10s TTL
+	data, ok, err = bc.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, ok, "the refreshed TTL keeps the entry")
+	require.Equal(t, []byte("value"), data)
+	ttl, _, err := bc.TTL(ctx, "k")
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, ttl)
+
+	clock.Advance(11 * time.Second)
+	_, ok, err = bc.GetAndRefresh(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok, "an expired entry is not revived")
+	_, ok, err = bc.GetAndRefresh(ctx, "missing", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestBigCacheGetAndRefreshKeepsPriority(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc := newFakeClockBigCache(t, newFakeClock(), BigCacheConfig{})
+	require.NoError(t, bc.SetWithPriority(ctx, "k", []byte("v"), time.Second, 3))
+
+	_, ok, err := bc.GetAndRefresh(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, priority, ok, err := bc.GetWithPriority(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int8(3), priority)
+}
+
+func TestBigCacheGetAndRefreshDoesNotUndoWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc := newFakeClockBigCache(t, newFakeClock(), BigCacheConfig{})
+	require.NoError(t, bc.Set(ctx, "k", []byte("v0"), time.Minute))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 500; i++ {
+			require.NoError(t, bc.Set(ctx, "k", []byte(fmt.Sprint("v", i)), time.Minute))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 500 {
+			_, _, err := bc.GetAndRefresh(ctx, "k", time.Hour)
+			require.NoError(t, err)
+		}
+	}()
+	wg.Wait()
+
+	data, ok, err := bc.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("v500"), data, "a refresh never writes back an older value")
+}
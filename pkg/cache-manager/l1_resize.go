@@ -0,0 +1,70 @@
+package cache_manager
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+// Resize changes HardMaxCacheSize (in MB, 0 = unlimited) without a restart. bigcache
+// cannot resize in place, so Resize builds a new bigcache with the new limit, copies the
+// live entries into it and swaps it in. Writes and deletes made while it copies go to
+// both caches, so nothing is lost or resurrected; reads are served by the old cache
+// until the swap. When shrinking, entries that do not fit are evicted by bigcache as
+// EvictionNoSpace. Hit and miss counters restart with the new cache.
+func (b *BigCache) Resize(newMaxMB int) error {
+	if newMaxMB < 0 {
+		return &CacheError{Op: "resize", Level: LevelL1, Cause: errors.New("max size must not be negative")}
+	}
+	if b == nil {
+		return &CacheError{Op: "resize", Level: LevelL1, Cause: ErrNotInitialized}
+	}
+	b.resizeMu.Lock()
+	defer b.resizeMu.Unlock()
+
+	b.mu.Lock()
+	old, cfg := b.cache, b.config
+	b.mu.Unlock()
+	if old == nil {
+		return &CacheError{Op: "resize", Level: LevelL1, Cause: ErrNotInitialized}
+	}
+	cfg.HardMaxCacheSize = newMaxMB
+	next, err := bigcache.New(b.ctx, cfg)
+	if err != nil {
+		return &CacheError{Op: "resize", Level: LevelL1, Cause: err}
+	}
+
+	b.mu.Lock()
+	b.resizing = next
+	b.mu.Unlock()
+
+	now := b.now()
+	it := old.Iterator()
+	for it.SetNext() {
+		info, err := it.Value()
+		if err != nil {
+			continue
+		}
+		raw := info.Value()
+		if len(raw) < entryHeaderSize || entryExpired(raw, now) {
+			continue
+		}
+		key := info.Key()
+		// Under the write lock no operation runs, so the entry is copied only if it is
+		// still the one iterated and the new cache has not received a newer write.
+		b.mu.Lock()
+		if current, err := old.Get(key); err == nil && bytes.Equal(current, raw) {
+			if _, err := next.Get(key); errors.Is(err, bigcache.ErrEntryNotFound) {
+				_ = next.Set(key, raw)
+			}
+		}
+		b.mu.Unlock()
+	}
+
+	b.mu.Lock()
+	b.cache, b.resizing = next, nil
+	b.config = cfg
+	b.mu.Unlock()
+	return old.Close()
+}
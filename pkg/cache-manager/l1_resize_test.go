@@ -0,0 +1,92 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func newResizeTestBigCache(t *testing.T, maxMB int) *BigCache {
+	t.Helper()
+	cfg := bigcache.DefaultConfig(time.Minute)
+	cfg.Shards = 16
+	cfg.HardMaxCacheSize = maxMB
+	bc, err := NewBigCache(context.Background(), BigCacheConfig{Config: cfg})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+	return bc
+}
+
+func TestBigCacheResizeKeepsEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc := newResizeTestBigCache(t, 8)
+	for i := range 200 {
+		require.NoError(t, bc.SetWithPriority(ctx, fmt.Sprintf("k%d", i), []byte(fmt.Sprint(i)), time.Minute, int8(i%2)))
+	}
+
+	require.NoError(t, bc.Resize(32))
+	require.Equal(t, 32, bc.config.HardMaxCacheSize)
+
+	for i := range 200 {
+		data, priority, ok, err := bc.GetWithPriority(ctx, fmt.Sprintf("k%d", i))
+		require.NoError(t, err)
+		require.True(t, ok, "k%d", i)
+		require.Equal(t, []byte(fmt.Sprint(i)), data)
+		require.Equal(t, int8(i%2), priority)
+	}
+	ttl, ok, err := bc.TTL(ctx, "k0")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.InDelta(t, time.Minute, ttl, float64(time.Second), "the expiry is carried over")
+
+	require.ErrorContains(t, bc.Resize(-1), "negative")
+}
+
+func TestBigCacheResizeDuringWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc := newResizeTestBigCache(t, 8)
+	for i := range 1000 {
+		require.NoError(t, bc.Set(ctx, fmt.Sprintf("old%d", i), []byte("v"), time.Minute))
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	written := 0
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ; ; written++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_ = bc.Set(ctx, fmt.Sprintf("new%d", written), []byte("v"), time.Minute)
+			_ = bc.Delete(ctx, fmt.Sprintf("old%d", written%1000))
+		}
+	}()
+	require.NoError(t, bc.Resize(16))
+	require.NoError(t, bc.Resize(64))
+	close(stop)
+	wg.Wait()
+
+	for i := range written {
+		_, ok, err := bc.Get(ctx, fmt.Sprintf("new%d", i))
+		require.NoError(t, err)
+		require.True(t, ok, "write new%d made during a resize was lost", i)
+	}
+	for i := range min(written, 1000) {
+		_, ok, err := bc.Get(ctx, fmt.Sprintf("old%d", i))
+		require.NoError(t, err)
+		require.False(t, ok, "old%d deleted during a resize came back", i)
+	}
+}
@@ -0,0 +1,127 @@
+package cache_manager
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is bumped whenever the on-disk snapshot layout changes.
+// Snapshots written with a different version are ignored on restore.
+const snapshotVersion = 2
+
+type l1Snapshot struct {
+	Version int
+	Entries []l1SnapshotEntry
+}
+
+type l1SnapshotEntry struct {
+	Key     string
+	Payload []byte
+	// ExpiresAt is the absolute expiry in UnixNano, or 0 when the entry has no TTL.
+	ExpiresAt int64
+	// OriginalTTL is the TTL the entry was stored with, used by CopyOnRead. Snapshots
+	// written before it existed restore it as 0, which disables sliding for the entry.
+	OriginalTTL time.Duration
+	Priority    int8
+}
+
+// writeSnapshot iterates the cache and persists every non-expired entry to restorePath.
+// The file is written to a temporary sibling and renamed so a crash never leaves a partial snapshot.
+func (b *BigCache) writeSnapshot() error {
+	snap := l1Snapshot{Version: snapshotVersion}
+	now := b.now()
+
+	it := b.cache.Iterator()
+	for it.SetNext() {
+		info, err := it.Value()
+		if err != nil {
+			continue
+		}
+		raw := info.Value()
+		if len(raw) < entryHeaderSize || entryExpired(raw, now) {
+			continue
+		}
+		payload := make([]byte, len(raw)-entryHeaderSize)
+		copy(payload, raw[entryHeaderSize:])
+		snap.Entries = append(snap.Entries, l1SnapshotEntry{
+			Key:         info.Key(),
+			Payload:     payload,
+			ExpiresAt:   entryExpiry(raw),
+			OriginalTTL: entryOriginalTTL(raw),
+			Priority:    entryPriority(raw),
+		})
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(b.restorePath), filepath.Base(b.restorePath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := gob.NewEncoder(w).Encode(snap); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), b.restorePath); err != nil {
+		return fmt.Errorf("rename snapshot: %w", err)
+	}
+
+	slog.Info("l1 snapshot written", "path", b.restorePath, "entries", len(snap.Entries))
+	return nil
+}
+
+// restoreSnapshot loads entries from restorePath and re-sets those whose TTL has not elapsed.
+// A missing, corrupt or version-mismatched snapshot is logged and ignored.
+func (b *BigCache) restoreSnapshot() {
+	f, err := os.Open(b.restorePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("l1 snapshot unreadable, starting cold", "path", b.restorePath, "error", err)
+		}
+		return
+	}
+	defer f.Close()
+
+	var snap l1Snapshot
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&snap); err != nil {
+		slog.Warn("l1 snapshot corrupt, starting cold", "path", b.restorePath, "error", err)
+		return
+	}
+	if snap.Version != snapshotVersion {
+		slog.Warn("l1 snapshot version mismatch, starting cold",
+			"path", b.restorePath,
+			"version", snap.Version,
+			"expected", snapshotVersion)
+		return
+	}
+
+	now := b.now()
+	restored := 0
+	for _, e := range snap.Entries {
+		entry := encodeEntryAt(e.Payload, e.ExpiresAt, e.OriginalTTL, e.Priority)
+		if entryExpired(entry, now) {
+			continue
+		}
+		if err := b.cache.Set(e.Key, entry); err != nil {
+			slog.Warn("l1 snapshot entry restore failed", "key", e.Key, "error", err)
+			continue
+		}
+		restored++
+	}
+
+	slog.Info("l1 snapshot restored", "path", b.restorePath, "entries", restored, "skipped", len(snap.Entries)-restored)
+}
@@ -0,0 +1,89 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigCacheSnapshotRestoresSurvivingEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "l1.snapshot")
+	clock := newFakeClock()
+	cfg := BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute), RestorePath: path, Clock: clock}
+
+	bc, err := NewBigCache(ctx, cfg)
+	require.NoError(t, err)
+	require.NoError(t, bc.Set(ctx, "long", []byte("survives"), time.Minute))
+	require.NoError(t, bc.Set(ctx, "short", []byte("expires"), 50*time.Millisecond))
+	require.NoError(t, bc.Close())
+
+	clock.Advance(70 * time.Millisecond)
+
+	restarted, err := NewBigCache(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = restarted.Close() })
+
+	data, ok, err := restarted.Get(ctx, "long")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("survives"), data)
+
+	_, ok, err = restarted.Get(ctx, "short")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestBigCacheSnapshotIgnoresCorruptFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "l1.snapshot")
+	require.NoError(t, os.WriteFile(path, []byte("not a snapshot"), 0o600))
+
+	bc, err := NewBigCache(ctx, BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute), RestorePath: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	_, ok, err := bc.Get(ctx, "anything")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+// Not parallel: restoreSnapshot logs through slog.Default, which the test swaps out.
+func TestBigCacheSnapshotIgnoresOtherVersion(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "l1.snapshot")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, gob.NewEncoder(f).Encode(l1Snapshot{
+		Version: snapshotVersion + 1,
+		Entries: []l1SnapshotEntry{{Key: "user:1", Payload: []byte("ada")}},
+	}))
+	require.NoError(t, f.Close())
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	bc, err := NewBigCache(ctx, BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute), RestorePath: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+
+	_, ok, err := bc.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Zero(t, bc.cache.Len())
+	require.Contains(t, logs.String(), "l1 snapshot version mismatch")
+}
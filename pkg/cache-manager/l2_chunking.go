@@ -0,0 +1,203 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MultiKeyCache is implemented by raw caches that can read, write and delete several keys
+// in one round trip (Redis pipelines). Missing keys are returned as nil by MGet.
+type MultiKeyCache interface {
+	MGet(ctx context.Context, keys []string) ([][]byte, error)
+	MSet(ctx context.Context, keys []string, values [][]byte, ttl time.Duration) error
+	MDelete(ctx context.Context, keys []string) error
+}
+
+// chunkManifestMagic prefixes the manifest stored under the original key of a chunked value.
+var chunkManifestMagic = []byte{0x00, 'c', 'h', 'k'}
+
+// chunkTTLMargin keeps chunks alive slightly longer than their manifest, so a reader that
+// still sees the manifest also finds the chunks.
+const chunkTTLMargin = time.Minute
+
+// errChunkMissing marks a chunked value whose chunks are incomplete or corrupt.
+var errChunkMissing = errors.New("chunked value incomplete")
+
+// chunkManifest describes a value split across chunk keys.
+type chunkManifest struct {
+	Chunks int    `json:"chunks"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func chunkKey(key string, i int) string {
+	return fmt.Sprintf("%s:__chunk:%d", key, i)
+}
+
+func (c chunkManifest) keys(key string) []string {
+	keys := make([]string, c.Chunks)
+	for i := range keys {
+		keys[i] = chunkKey(key, i)
+	}
+	return keys
+}
+
+// writeChunked splits data into L2ChunkThreshold-sized chunks, writes them in one batch,
+// then writes the manifest under key. Leftover chunks of a previous, larger value are
+// left to expire with their TTL.
+func (m *MultiLevelCache) writeChunked(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	sum := sha256.Sum256(data)
+	manifest := chunkManifest{
+		Chunks: (len(data) + m.l2ChunkThreshold - 1) / m.l2ChunkThreshold,
+		Size:   len(data),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+
+	values := make([][]byte, manifest.Chunks)
+	for i := range values {
+		end := min((i+1)*m.l2ChunkThreshold, len(data))
+		values[i] = data[i*m.l2ChunkThreshold : end]
+	}
+	chunkTTL := ttl
+	if chunkTTL > 0 {
+		chunkTTL += chunkTTLMargin
+	}
+	if err := mset(ctx, m.l2, manifest.keys(key), values, chunkTTL); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	m.log.Debug(ctx, "cache set l2 value chunked", "key", key, "size", len(data), "chunks", manifest.Chunks)
+	return m.l2.Set(ctx, key, append(bytes.Clone(chunkManifestMagic), encoded...), ttl)
+}
+
+// resolveChunks returns data unchanged unless it is a chunk manifest, in which case the
+// chunks are fetched and verified. Incomplete or corrupt values are removed and reported
+// as a miss.
+func (m *MultiLevelCache) resolveChunks(ctx context.Context, key string, data []byte) ([]byte, bool, error) {
+	manifest, ok := parseChunkManifest(data)
+	if !ok {
+		return data, true, nil
+	}
+
+	value, err := m.readChunks(ctx, key, manifest)
+	if errors.Is(err, errChunkMissing) {
+		m.log.Debug(ctx, "cache get chunked l2 value incomplete, treating as miss", "key", key)
+		_ = mdelete(ctx, m.l2, append(manifest.keys(key), key))
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (m *MultiLevelCache) readChunks(ctx context.Context, key string, manifest chunkManifest) ([]byte, error) {
+	chunks, err := mget(ctx, m.l2, manifest.keys(key))
+	if err != nil {
+		return nil, err
+	}
+
+	value := make([]byte, 0, manifest.Size)
+	for _, chunk := range chunks {
+		if chunk == nil {
+			return nil, errChunkMissing
+		}
+		value = append(value, chunk...)
+	}
+	sum := sha256.Sum256(value)
+	if len(value) != manifest.Size || hex.EncodeToString(sum[:]) != manifest.SHA256 {
+		return nil, errChunkMissing
+	}
+	return value, nil
+}
+
+// deleteL2 removes key from L2 along with its chunks when chunking is enabled.
+func (m *MultiLevelCache) deleteL2(ctx context.Context, key string) error {
+	if !m.l2Gate.acquire(ctx) {
+		return ErrL2Throttled
+	}
+	defer m.l2Gate.release()
+
+	if m.l2ChunkThreshold <= 0 {
+		return m.l2.Delete(ctx, key)
+	}
+
+	data, ok, err := m.l2.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	keys := []string{key}
+	if manifest, chunked := parseChunkManifest(data); ok && chunked {
+		keys = append(manifest.keys(key), key)
+	}
+	return mdelete(ctx, m.l2, keys)
+}
+
+// deleteChunks removes the chunks behind a manifest that was already popped from L2.
+func (m *MultiLevelCache) deleteChunks(ctx context.Context, key string, data []byte) {
+	if manifest, ok := parseChunkManifest(data); ok {
+		_ = mdelete(ctx, m.l2, manifest.keys(key))
+	}
+}
+
+func parseChunkManifest(data []byte) (chunkManifest, bool) {
+	if !bytes.HasPrefix(data, chunkManifestMagic) {
+		return chunkManifest{}, false
+	}
+	var manifest chunkManifest
+	if err := json.Unmarshal(data[len(chunkManifestMagic):], &manifest); err != nil || manifest.Chunks <= 0 {
+		return chunkManifest{}, false
+	}
+	return manifest, true
+}
+
+func mget(ctx context.Context, cache RawCache, keys []string) ([][]byte, error) {
+	if mk, ok := cache.(MultiKeyCache); ok {
+		return mk.MGet(ctx, keys)
+	}
+	out := make([][]byte, len(keys))
+	for i, key := range keys {
+		data, ok, err := cache.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out[i] = data
+		}
+	}
+	return out, nil
+}
+
+func mset(ctx context.Context, cache RawCache, keys []string, values [][]byte, ttl time.Duration) error {
+	if mk, ok := cache.(MultiKeyCache); ok {
+		return mk.MSet(ctx, keys, values, ttl)
+	}
+	for i, key := range keys {
+		if err := cache.Set(ctx, key, values[i], ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mdelete(ctx context.Context, cache RawCache, keys []string) error {
+	if mk, ok := cache.(MultiKeyCache); ok {
+		return mk.MDelete(ctx, keys)
+	}
+	for _, key := range keys {
+		if err := cache.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
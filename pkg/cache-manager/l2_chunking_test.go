@@ -0,0 +1,91 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+func newChunkingTestCache(t *testing.T) (*MultiLevelCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+
+	ml, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:             ModeL2Only,
+		L2ChunkThreshold: 100,
+	})
+	require.NoError(t, err)
+	return ml, mr
+}
+
+func TestL2ChunkingRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ml, mr := newChunkingTestCache(t)
+	ctx := context.Background()
+	value := strings.Repeat("large-", 60) // 362 bytes as JSON
+
+	require.NoError(t, ml.Set(ctx, "blob", value, CacheOptions{}))
+
+	manifest, err := mr.Get("blob")
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix([]byte(manifest), chunkManifestMagic))
+	for i := 0; i < 4; i++ {
+		require.True(t, mr.Exists(chunkKey("blob", i)))
+	}
+	require.False(t, mr.Exists(chunkKey("blob", 4)))
+
+	var got string
+	res, err := ml.Get(ctx, "blob", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, value, got)
+
+	require.NoError(t, ml.Delete(ctx, "blob"))
+	require.Empty(t, mr.Keys())
+}
+
+func TestL2ChunkingMissingChunkIsMiss(t *testing.T) {
+	t.Parallel()
+
+	ml, mr := newChunkingTestCache(t)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "blob", strings.Repeat("x", 300), CacheOptions{}))
+
+	mr.Del(chunkKey("blob", 1))
+
+	var got string
+	res, err := ml.Get(ctx, "blob", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.Empty(t, mr.Keys(), "manifest and remaining chunks should be cleaned up")
+}
+
+func TestL2ChunkingCorruptChunkIsMiss(t *testing.T) {
+	t.Parallel()
+
+	ml, mr := newChunkingTestCache(t)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "blob", strings.Repeat("x", 300), CacheOptions{}))
+
+	require.NoError(t, mr.Set(chunkKey("blob", 0), strings.Repeat("y", 100)))
+
+	var got string
+	res, err := ml.Get(ctx, "blob", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+}
@@ -0,0 +1,61 @@
+package cache_manager
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// defaultL2AcquireTimeout is how long an L2 call waits for a free slot when
+// L2MaxConcurrency is set and L2AcquireTimeout is not.
+const defaultL2AcquireTimeout = 50 * time.Millisecond
+
+// l2Gate caps the number of L2 operations in flight. A call that cannot get a slot
+// within wait is throttled instead of queueing behind a slow Redis.
+type l2Gate struct {
+	sem       *semaphore.Weighted
+	wait      time.Duration
+	inFlight  atomic.Int64
+	throttled atomic.Int64
+}
+
+// newL2Gate returns nil, which lets every call through, when limit is not positive.
+func newL2Gate(limit int, wait time.Duration) *l2Gate {
+	if limit <= 0 {
+		return nil
+	}
+	if wait <= 0 {
+		wait = defaultL2AcquireTimeout
+	}
+	return &l2Gate{sem: semaphore.NewWeighted(int64(limit)), wait: wait}
+}
+
+// acquire takes a slot, waiting at most g.wait or until ctx ends. It reports false,
+// and counts a throttle, when no slot was free in time. Every successful acquire
+// must be paired with release.
+func (g *l2Gate) acquire(ctx context.Context) bool {
+	if g == nil {
+		return true
+	}
+	if !g.sem.TryAcquire(1) {
+		waitCtx, cancel := context.WithTimeout(ctx, g.wait)
+		err := g.sem.Acquire(waitCtx, 1)
+		cancel()
+		if err != nil {
+			g.throttled.Add(1)
+			return false
+		}
+	}
+	g.inFlight.Add(1)
+	return true
+}
+
+func (g *l2Gate) release() {
+	if g == nil {
+		return
+	}
+	g.inFlight.Add(-1)
+	g.sem.Release(1)
+}
@@ -0,0 +1,128 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// slowRawCache is a memoryRawCache whose operations take delay and that records the
+// highest number of operations it ever saw at once.
+type slowRawCache struct {
+	*memoryRawCache
+	delay   time.Duration
+	current atomic.Int64
+	peak    atomic.Int64
+}
+
+func (c *slowRawCache) enter() func() {
+	n := c.current.Add(1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(c.delay)
+	return func() { c.current.Add(-1) }
+}
+
+func (c *slowRawCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	defer c.enter()()
+	return c.memoryRawCache.Get(ctx, key)
+}
+
+func (c *slowRawCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	defer c.enter()()
+	return c.memoryRawCache.Set(ctx, key, value, ttl)
+}
+
+func (c *slowRawCache) Delete(ctx context.Context, key string) error {
+	defer c.enter()()
+	return c.memoryRawCache.Delete(ctx, key)
+}
+
+func TestL2MaxConcurrencyCapsInFlightOperations(t *testing.T) {
+	t.Parallel()
+
+	l2 := &slowRawCache{memoryRawCache: newMemoryRawCache(), delay: 20 * time.Millisecond}
+	ml, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:             ModeL2Only,
+		L2MaxConcurrency: 8,
+		L2AcquireTimeout: 5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{L2TTL: time.Minute}))
+
+	var wg sync.WaitGroup
+	var hits, misses atomic.Int64
+	for i := 0; i < 500; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var got string
+			res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+			require.NoError(t, err, "a throttled read is a miss, not an error")
+			if res.Found {
+				hits.Add(1)
+			} else {
+				misses.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, l2.peak.Load(), int64(8))
+	require.Positive(t, hits.Load())
+	require.Positive(t, misses.Load())
+
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, stats.L2Throttled)
+	require.Equal(t, misses.Load(), *stats.L2Throttled)
+	require.Equal(t, int64(0), *stats.L2InFlight)
+}
+
+func TestL2MaxConcurrencyThrottlesWrites(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	l2 := &slowRawCache{memoryRawCache: newMemoryRawCache(), delay: 50 * time.Millisecond}
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		L2MaxConcurrency: 1,
+		L2AcquireTimeout: time.Millisecond,
+		Degradation:      DegradationConfig{After: time.Nanosecond},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	done := make(chan error)
+	go func() { done <- ml.Set(ctx, "first", "v", CacheOptions{}) }()
+	require.Eventually(t, func() bool { return l2.current.Load() == 1 }, time.Second, time.Millisecond)
+
+	opts := CacheOptions{TargetL1: BoolPtr(false), TargetL2: BoolPtr(true)}
+	err = ml.Set(ctx, "second", "v", opts)
+	require.ErrorIs(t, err, ErrL2Throttled)
+	require.ErrorIs(t, ml.Delete(ctx, "second"), ErrL2Throttled)
+	require.NoError(t, <-done)
+
+	require.Equal(t, L2Normal, ml.L2State(), "throttling must not degrade L2")
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), *stats.L2Throttled)
+}
+
+func TestStatsOmitsL2ConcurrencyWhenUnlimited(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	stats, err := ml.Stats(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, stats.L2InFlight)
+	require.Nil(t, stats.L2Throttled)
+}
@@ -3,6 +3,7 @@ package cache_manager
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,33 +12,104 @@ import (
 // RedisCache is the L2 cache backed by Redis.
 type RedisCache struct {
 	client *redis.Client
+	// PingInterval is how often StartMonitor pings Redis. Default 5s.
+	PingInterval time.Duration
+	monitor      redisMonitor
+
+	// closeMu orders begin against Close, so no operation starts after Close began
+	// waiting for inflight.
+	closeMu  sync.RWMutex
+	closed   bool
+	inflight sync.WaitGroup
 }
 
 // NewRedisCache builds a Redis-backed cache.
 func NewRedisCache(client *redis.Client) (*RedisCache, error) {
 	if client == nil {
-		return nil, errors.New("redis client is required")
+		return nil, &CacheError{Op: "new", Level: LevelL2, Cause: ErrRedisClientMissing}
 	}
 	return &RedisCache{client: client}, nil
 }
 
+// begin registers an operation with Close. It fails with ErrNotInitialized for a nil
+// cache and ErrCacheClosed after Close; otherwise the caller must call done when the
+// operation has finished with the client.
+func (r *RedisCache) begin(op, key string) (done func(), err error) {
+	if r == nil || r.client == nil {
+		return nil, &CacheError{Op: op, Level: LevelL2, Key: key, Cause: ErrNotInitialized}
+	}
+	r.closeMu.RLock()
+	defer r.closeMu.RUnlock()
+	if r.closed {
+		return nil, &CacheError{Op: op, Level: LevelL2, Key: key, Cause: ErrCacheClosed}
+	}
+	r.inflight.Add(1)
+	return r.inflight.Done, nil
+}
+
+// Close stops the monitor, waits for running operations to finish, or until ctx is done,
+// and closes the Redis client. Operations started after Close return ErrCacheClosed.
+// When ctx ends first the client is closed anyway, failing the stragglers, and ctx's
+// error is returned. Close is idempotent.
+func (r *RedisCache) Close(ctx context.Context) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	r.closeMu.Lock()
+	if r.closed {
+		r.closeMu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.closeMu.Unlock()
+
+	r.StopMonitor()
+	drained := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(drained)
+	}()
+	var drainErr error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		drainErr = &CacheError{Op: "close", Level: LevelL2, Cause: ctx.Err()}
+	}
+	if err := r.client.Close(); err != nil {
+		return errors.Join(drainErr, &CacheError{Op: "close", Level: LevelL2, Cause: err})
+	}
+	return drainErr
+}
+
+// Closed reports whether Close has been called.
+func (r *RedisCache) Closed() bool {
+	if r == nil {
+		return false
+	}
+	r.closeMu.RLock()
+	defer r.closeMu.RUnlock()
+	return r.closed
+}
+
 // Get fetches a key returning raw bytes when present.
 func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
-	if r == nil || r.client == nil {
-		return nil, false, errors.New("redis cache not initialized")
+	done, err := r.begin("get", key)
+	if err != nil {
+		return nil, false, err
 	}
+	defer done()
 
 	cmd := r.client.Get(ctx, key)
 	if err := cmd.Err(); err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, false, nil
 		}
-		return nil, false, err
+		return nil, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: err}
 	}
 
 	data, err := cmd.Bytes()
 	if err != nil {
-		return nil, false, err
+		return nil, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: err}
 	}
 
 	return data, true, nil
@@ -45,21 +117,308 @@ func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error)
 
 // Set stores the payload with the provided TTL.
 func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	if r == nil || r.client == nil {
-		return errors.New("redis cache not initialized")
+	done, err := r.begin("set", key)
+	if err != nil {
+		return err
+	}
+	defer done()
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return &CacheError{Op: "set", Level: LevelL2, Key: key, Cause: err}
 	}
-	return r.client.Set(ctx, key, value, ttl).Err()
+	return nil
+}
+
+// SetNX stores the payload with the provided TTL only if key does not exist, and reports
+// whether it did.
+func (r *RedisCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	done, err := r.begin("setnx", key)
+	if err != nil {
+		return false, err
+	}
+	defer done()
+	ok, err := r.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, &CacheError{Op: "setnx", Level: LevelL2, Key: key, Cause: err}
+	}
+	return ok, nil
 }
 
 // Delete removes key from Redis.
 func (r *RedisCache) Delete(ctx context.Context, key string) error {
-	if r == nil || r.client == nil {
-		return errors.New("redis cache not initialized")
+	done, err := r.begin("delete", key)
+	if err != nil {
+		return err
+	}
+	defer done()
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		return &CacheError{Op: "delete", Level: LevelL2, Key: key, Cause: err}
+	}
+	return nil
+}
+
+// Exists reports whether key is present (EXISTS).
+func (r *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	done, err := r.begin("exists", key)
+	if err != nil {
+		return false, err
+	}
+	defer done()
+	n, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, &CacheError{Op: "exists", Level: LevelL2, Key: key, Cause: err}
+	}
+	return n > 0, nil
+}
+
+// TTL reports the remaining lifetime of key. A zero duration with found=true means no expiry.
+func (r *RedisCache) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	done, err := r.begin("ttl", key)
+	if err != nil {
+		return 0, false, err
+	}
+	defer done()
+
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, false, &CacheError{Op: "ttl", Level: LevelL2, Key: key, Cause: err}
+	}
+	switch ttl {
+	case -2: // key does not exist
+		return 0, false, nil
+	case -1: // key exists without an expiry
+		return 0, true, nil
+	}
+	return ttl, true, nil
+}
+
+// Stats reports the server-wide keyspace hits and misses from INFO stats.
+func (r *RedisCache) Stats(ctx context.Context) (LevelStats, error) {
+	done, err := r.begin("stats", "")
+	if err != nil {
+		return LevelStats{}, err
+	}
+	defer done()
+
+	info, err := r.client.Info(ctx, "stats").Result()
+	if err != nil {
+		return LevelStats{}, &CacheError{Op: "stats", Level: LevelL2, Cause: err}
+	}
+	return parseRedisInfoStats(info), nil
+}
+
+// MGet returns the values of keys in one round trip; missing keys are nil.
+func (r *RedisCache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	done, err := r.begin("mget", "")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, &CacheError{Op: "mget", Level: LevelL2, Cause: err}
+	}
+	out := make([][]byte, len(vals))
+	for i, v := range vals {
+		if s, ok := v.(string); ok {
+			out[i] = []byte(s)
+		}
+	}
+	return out, nil
+}
+
+// MTTL returns the remaining TTLs of keys using one pipeline: 0 for a key without an
+// expiry and a negative duration for a missing key.
+func (r *RedisCache) MTTL(ctx context.Context, keys []string) ([]time.Duration, error) {
+	done, err := r.begin("ttl", "")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	cmds := make([]*redis.DurationCmd, len(keys))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.PTTL(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &CacheError{Op: "ttl", Level: LevelL2, Cause: err}
+	}
+	out := make([]time.Duration, len(keys))
+	for i, cmd := range cmds {
+		switch ttl := cmd.Val(); ttl {
+		case -2: // key does not exist
+			out[i] = -1
+		case -1: // key exists without an expiry
+			out[i] = 0
+		default:
+			out[i] = ttl
+		}
+	}
+	return out, nil
+}
+
+// MSet writes values under keys with a shared TTL using one pipeline.
+func (r *RedisCache) MSet(ctx context.Context, keys []string, values [][]byte, ttl time.Duration) error {
+	done, err := r.begin("mset", "")
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			pipe.Set(ctx, key, values[i], ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return &CacheError{Op: "mset", Level: LevelL2, Cause: err}
+	}
+	return nil
+}
+
+// MDelete removes keys with a single DEL.
+func (r *RedisCache) MDelete(ctx context.Context, keys []string) error {
+	done, err := r.begin("delete", "")
+	if err != nil {
+		return err
+	}
+	defer done()
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return &CacheError{Op: "delete", Level: LevelL2, Cause: err}
+	}
+	return nil
+}
+
+// incrByScript increments a counter and sets its TTL only when it has none, i.e. on creation.
+var incrByScript = redis.NewScript(`
+local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) == -1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return v
+`)
+
+// IncrBy atomically adds delta to the integer at key, creating it with ttl when missing.
+func (r *RedisCache) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	done, err := r.begin("incr", key)
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+
+	value, err := incrByScript.Run(ctx, r.client, []string{key}, delta, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, &CacheError{Op: "incr", Level: LevelL2, Key: key, Cause: err}
+	}
+	return value, nil
+}
+
+// GetDel atomically returns and deletes key using GETDEL.
+func (r *RedisCache) GetDel(ctx context.Context, key string) ([]byte, bool, error) {
+	done, err := r.begin("getdel", key)
+	if err != nil {
+		return nil, false, err
+	}
+	defer done()
+
+	data, err := r.client.GetDel(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, &CacheError{Op: "getdel", Level: LevelL2, Key: key, Cause: err}
+	}
+	return data, true, nil
+}
+
+// SetKeepTTL overwrites an existing key with SET ... XX KEEPTTL so its expiry is unchanged.
+// When the key does not exist it is created with ttl instead.
+func (r *RedisCache) SetKeepTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	done, err := r.begin("set", key)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	err = r.client.SetArgs(ctx, key, value, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if errors.Is(err, redis.Nil) {
+		err = r.client.SetArgs(ctx, key, value, redis.SetArgs{Mode: "NX", TTL: ttl}).Err()
+		if errors.Is(err, redis.Nil) {
+			// created concurrently; overwrite it without touching its expiry
+			err = r.client.SetArgs(ctx, key, value, redis.SetArgs{KeepTTL: true}).Err()
+		}
+	}
+	if err != nil {
+		return &CacheError{Op: "set", Level: LevelL2, Key: key, Cause: err}
+	}
+	return nil
+}
+
+// Expire sets a new TTL on an existing key. It reports false when the key does not exist.
+func (r *RedisCache) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	done, err := r.begin("expire", key)
+	if err != nil {
+		return false, err
+	}
+	defer done()
+
+	ok, err := r.client.PExpire(ctx, key, ttl).Result()
+	if err != nil {
+		return false, &CacheError{Op: "expire", Level: LevelL2, Key: key, Cause: err}
+	}
+	return ok, nil
+}
+
+// Keys returns the keys matching the Redis glob pattern, using SCAN to avoid blocking the server.
+func (r *RedisCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	done, err := r.begin("keys", "")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	var keys []string
+	iter := r.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, &CacheError{Op: "keys", Level: LevelL2, Cause: err}
+	}
+	return keys, nil
+}
+
+// ListKeys is Keys capped at max keys (max <= 0 means no cap); the scan stops once max
+// keys have been collected.
+func (r *RedisCache) ListKeys(ctx context.Context, pattern string, max int) ([]string, error) {
+	done, err := r.begin("keys", "")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	var keys []string
+	iter := r.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for (max <= 0 || len(keys) < max) && iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, &CacheError{Op: "keys", Level: LevelL2, Cause: err}
 	}
-	return r.client.Del(ctx, key).Err()
+	return keys, nil
 }
 
 // SubscribeInvalidations is a placeholder for future pub/sub invalidation support.
 func (r *RedisCache) SubscribeInvalidations(ctx context.Context, channel string, handler func(context.Context, string)) error {
-	return errors.New("pub/sub invalidation not implemented")
+	return &CacheError{Op: "subscribe", Level: LevelL2, Cause: errors.ErrUnsupported}
 }
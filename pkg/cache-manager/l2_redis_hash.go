@@ -0,0 +1,396 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NamespaceDropper is implemented by raw caches that can delete every key under a prefix
+// in one step, such as RedisHashCache for its hash namespaces.
+type NamespaceDropper interface {
+	// DropNamespace deletes every key starting with prefix and returns them. ok is false
+	// when prefix is not a namespace the cache can drop at once.
+	DropNamespace(ctx context.Context, prefix string) (keys []string, ok bool, err error)
+}
+
+var (
+	_ RawCache         = (*RedisHashCache)(nil)
+	_ TTLInspector     = (*RedisHashCache)(nil)
+	_ KeyLister        = (*RedisHashCache)(nil)
+	_ ExpiredPurger    = (*RedisHashCache)(nil)
+	_ NamespaceDropper = (*RedisHashCache)(nil)
+)
+
+// RedisHashConfig configures NewRedisHashCache.
+type RedisHashConfig struct {
+	// Namespaces lists the key prefixes, as stored (including the InstanceName and
+	// Version prefix of a MultiLevelCache), whose keys are kept as fields of one hash per
+	// prefix, e.g. "svc:user:" stores "svc:user:42" as field "42" of hash "svc:user:".
+	Namespaces []string
+	// JanitorInterval is how often StartJanitor purges expired fields. Default 1m.
+	JanitorInterval time.Duration
+	// Clock is used for field expiries; nil uses the system clock. Every process sharing
+	// the hashes must have roughly the same time.
+	Clock Clock
+}
+
+// RedisHashCache is an L2 raw cache that stores the keys of configured namespaces as
+// fields of a Redis hash (HSET ns field value) instead of top-level keys, which takes far
+// less memory for millions of tiny values thanks to the compact hash encoding. Keys
+// outside the namespaces are passed to the wrapped RedisCache unchanged.
+//
+// Redis cannot expire hash fields, so each namespace has a companion sorted set of field
+// expiries. Get drops an expired field when it reads it, and PurgeExpired, run by the
+// janitor, removes the rest. Deleting a namespace with DropNamespace is a single DEL.
+type RedisHashCache struct {
+	redis           *RedisCache
+	namespaces      []string // longest first, so nested namespaces match the innermost
+	janitorInterval time.Duration
+	clock           Clock
+	janitor         struct {
+		mu     sync.Mutex
+		cancel context.CancelFunc // nil while stopped
+		done   chan struct{}
+	}
+}
+
+const defaultHashJanitorInterval = time.Minute
+
+// hashExpirySuffix names the sorted set holding the field expiries of a namespace.
+const hashExpirySuffix = "__expiry"
+
+// hashPurgeBatch caps the fields one purge script removes, keeping it short and within
+// Lua's unpack limit.
+const hashPurgeBatch = 1000
+
+// expireFieldScript removes field ARGV[1] when its expiry is at or before ARGV[2], so a
+// Set racing with the lazy sweep is not lost.
+var expireFieldScript = redis.NewScript(`
+local s = redis.call('ZSCORE', KEYS[2], ARGV[1])
+if s and tonumber(s) <= tonumber(ARGV[2]) then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+	redis.call('ZREM', KEYS[2], ARGV[1])
+	return 1
+end
+return 0`)
+
+// purgeFieldsScript removes up to ARGV[2] fields whose expiry is at or before ARGV[1].
+var purgeFieldsScript = redis.NewScript(`
+local fields = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #fields > 0 then
+	redis.call('HDEL', KEYS[1], unpack(fields))
+	redis.call('ZREM', KEYS[2], unpack(fields))
+end
+return #fields`)
+
+// NewRedisHashCache wraps r with hash-per-namespace storage for cfg.Namespaces.
+func NewRedisHashCache(r *RedisCache, cfg RedisHashConfig) (*RedisHashCache, error) {
+	if r == nil {
+		return nil, &CacheError{Op: "new", Level: LevelL2, Cause: ErrRedisClientMissing}
+	}
+	namespaces := slices.Clone(cfg.Namespaces)
+	for _, ns := range namespaces {
+		if ns == "" {
+			return nil, &CacheError{Op: "new", Level: LevelL2, Cause: errors.New("hash namespace must not be empty")}
+		}
+	}
+	slices.SortFunc(namespaces, func(a, b string) int { return len(b) - len(a) })
+	interval := cfg.JanitorInterval
+	if interval <= 0 {
+		interval = defaultHashJanitorInterval
+	}
+	return &RedisHashCache{
+		redis:           r,
+		namespaces:      namespaces,
+		janitorInterval: interval,
+		clock:           clockOrSystem(cfg.Clock),
+	}, nil
+}
+
+// route returns the namespace key belongs to and its field, or ok=false for a key stored
+// as a top-level key.
+func (h *RedisHashCache) route(key string) (ns, field string, ok bool) {
+	for _, ns := range h.namespaces {
+		if len(key) > len(ns) && strings.HasPrefix(key, ns) {
+			return ns, key[len(ns):], true
+		}
+	}
+	return "", "", false
+}
+
+// internalKey reports whether key is one of the hashes or expiry sets this cache keeps.
+func (h *RedisHashCache) internalKey(key string) bool {
+	for _, ns := range h.namespaces {
+		if key == ns || key == ns+hashExpirySuffix {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *RedisHashCache) nowMillis() int64 {
+	return h.clock.Now().UnixMilli()
+}
+
+// Get fetches a key; an expired field is removed and reported as a miss.
+func (h *RedisHashCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ns, field, ok := h.route(key)
+	if !ok {
+		return h.redis.Get(ctx, key)
+	}
+	done, err := h.redis.begin("get", key)
+	if err != nil {
+		return nil, false, err
+	}
+	defer done()
+
+	pipe := h.redis.client.Pipeline()
+	value := pipe.HGet(ctx, ns, field)
+	expiry := pipe.ZScore(ctx, ns+hashExpirySuffix, field)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: err}
+	}
+	data, err := value.Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: err}
+	}
+	if at, err := expiry.Result(); err == nil {
+		now := h.nowMillis()
+		if int64(at) <= now {
+			if err := expireFieldScript.Run(ctx, h.redis.client, []string{ns, ns + hashExpirySuffix}, field, now).Err(); err != nil {
+				return nil, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: err}
+			}
+			return nil, false, nil
+		}
+	}
+	return data, true, nil
+}
+
+// Set stores the payload; a non-positive ttl stores it without expiry.
+func (h *RedisHashCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ns, field, ok := h.route(key)
+	if !ok {
+		return h.redis.Set(ctx, key, value, ttl)
+	}
+	done, err := h.redis.begin("set", key)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	pipe := h.redis.client.TxPipeline()
+	pipe.HSet(ctx, ns, field, value)
+	if ttl > 0 {
+		pipe.ZAdd(ctx, ns+hashExpirySuffix, redis.Z{Score: float64(h.clock.Now().Add(ttl).UnixMilli()), Member: field})
+	} else {
+		pipe.ZRem(ctx, ns+hashExpirySuffix, field)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return &CacheError{Op: "set", Level: LevelL2, Key: key, Cause: err}
+	}
+	return nil
+}
+
+// Delete removes key.
+func (h *RedisHashCache) Delete(ctx context.Context, key string) error {
+	ns, field, ok := h.route(key)
+	if !ok {
+		return h.redis.Delete(ctx, key)
+	}
+	done, err := h.redis.begin("delete", key)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	pipe := h.redis.client.TxPipeline()
+	pipe.HDel(ctx, ns, field)
+	pipe.ZRem(ctx, ns+hashExpirySuffix, field)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return &CacheError{Op: "delete", Level: LevelL2, Key: key, Cause: err}
+	}
+	return nil
+}
+
+// TTL reports the remaining lifetime of key. A zero duration with found=true means no
+// expiry.
+func (h *RedisHashCache) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ns, field, ok := h.route(key)
+	if !ok {
+		return h.redis.TTL(ctx, key)
+	}
+	done, err := h.redis.begin("ttl", key)
+	if err != nil {
+		return 0, false, err
+	}
+	defer done()
+
+	pipe := h.redis.client.Pipeline()
+	exists := pipe.HExists(ctx, ns, field)
+	expiry := pipe.ZScore(ctx, ns+hashExpirySuffix, field)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, false, &CacheError{Op: "ttl", Level: LevelL2, Key: key, Cause: err}
+	}
+	if !exists.Val() {
+		return 0, false, nil
+	}
+	at, err := expiry.Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, true, nil
+	}
+	remaining := time.Duration(int64(at)-h.nowMillis()) * time.Millisecond
+	if remaining <= 0 {
+		return 0, false, nil
+	}
+	return remaining, true, nil
+}
+
+// Keys returns the live keys matching the glob pattern: the top-level keys from the
+// wrapped RedisCache and the hash fields as full keys (matched with path.Match).
+func (h *RedisHashCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	keys, err := h.redis.Keys(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	keys = slices.DeleteFunc(keys, h.internalKey)
+
+	done, err := h.redis.begin("keys", "")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	now := strconv.FormatInt(h.nowMillis(), 10)
+	for _, ns := range h.namespaces {
+		pipe := h.redis.client.Pipeline()
+		fields := pipe.HKeys(ctx, ns)
+		expired := pipe.ZRangeByScore(ctx, ns+hashExpirySuffix, &redis.ZRangeBy{Min: "-inf", Max: now})
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, &CacheError{Op: "keys", Level: LevelL2, Cause: err}
+		}
+		for _, field := range fields.Val() {
+			if slices.Contains(expired.Val(), field) {
+				continue
+			}
+			key := ns + field
+			if ok, _ := path.Match(pattern, key); ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// PurgeExpired removes every expired field from the namespace hashes and returns how
+// many were removed. Top-level keys are expired by Redis itself.
+func (h *RedisHashCache) PurgeExpired(ctx context.Context) (int64, error) {
+	done, err := h.redis.begin("purge", "")
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+
+	var purged int64
+	for _, ns := range h.namespaces {
+		for {
+			n, err := purgeFieldsScript.Run(ctx, h.redis.client, []string{ns, ns + hashExpirySuffix}, h.nowMillis(), hashPurgeBatch).Int64()
+			if err != nil {
+				return purged, &CacheError{Op: "purge", Level: LevelL2, Key: ns, Cause: err}
+			}
+			purged += n
+			if n < hashPurgeBatch {
+				break
+			}
+		}
+	}
+	return purged, nil
+}
+
+// DropNamespace deletes the hash and expiry set of the namespace prefix names, with
+// one DEL, and returns the keys it held. Other prefixes report ok=false.
+func (h *RedisHashCache) DropNamespace(ctx context.Context, prefix string) ([]string, bool, error) {
+	if !slices.Contains(h.namespaces, prefix) {
+		return nil, false, nil
+	}
+	done, err := h.redis.begin("flush", prefix)
+	if err != nil {
+		return nil, false, err
+	}
+	defer done()
+
+	pipe := h.redis.client.TxPipeline()
+	fields := pipe.HKeys(ctx, prefix)
+	pipe.Del(ctx, prefix, prefix+hashExpirySuffix)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, &CacheError{Op: "flush", Level: LevelL2, Key: prefix, Cause: err}
+	}
+	keys := make([]string, len(fields.Val()))
+	for i, field := range fields.Val() {
+		keys[i] = prefix + field
+	}
+	return keys, true, nil
+}
+
+// StartJanitor runs PurgeExpired every JanitorInterval in a background goroutine until
+// ctx is done or StopJanitor is called. Calling it while a janitor is running does
+// nothing.
+func (h *RedisHashCache) StartJanitor(ctx context.Context) {
+	h.janitor.mu.Lock()
+	defer h.janitor.mu.Unlock()
+	if h.janitor.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.janitor.cancel, h.janitor.done = cancel, done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(h.janitorInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if purged, err := h.PurgeExpired(ctx); err != nil {
+				slog.Warn("l2 hash janitor purge failed", "error", err)
+			} else if purged > 0 {
+				slog.Debug("l2 hash janitor purge", "purged", purged)
+			}
+		}
+	}()
+}
+
+// StopJanitor stops the janitor started by StartJanitor and waits for it to exit.
+func (h *RedisHashCache) StopJanitor() {
+	h.janitor.mu.Lock()
+	cancel, done := h.janitor.cancel, h.janitor.done
+	h.janitor.cancel, h.janitor.done = nil, nil
+	h.janitor.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Close stops the janitor and closes the wrapped RedisCache.
+func (h *RedisHashCache) Close(ctx context.Context) error {
+	h.StopJanitor()
+	return h.redis.Close(ctx)
+}
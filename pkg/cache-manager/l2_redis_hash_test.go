@@ -0,0 +1,142 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func newHashTestCache(t *testing.T, clock Clock) (*RedisHashCache, *miniredis.Miniredis) {
+	t.Helper()
+	l2, mr := newRenameTestRedis(t)
+	h, err := NewRedisHashCache(l2, RedisHashConfig{Namespaces: []string{"user:", "user:session:"}, Clock: clock})
+	require.NoError(t, err)
+	return h, mr
+}
+
+func TestRedisHashCacheStorageShape(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h, mr := newHashTestCache(t, nil)
+	require.NoError(t, h.Set(ctx, "user:1", []byte("ada"), time.Minute))
+	require.NoError(t, h.Set(ctx, "user:session:9", []byte("s"), 0))
+	require.NoError(t, h.Set(ctx, "order:1", []byte("o"), time.Minute))
+
+	require.False(t, mr.Exists("user:1"), "namespaced keys are not top-level keys")
+	require.Equal(t, "hash", mr.Type("user:"))
+	require.Equal(t, "ada", mr.HGet("user:", "1"))
+	require.Equal(t, "s", mr.HGet("user:session:", "9"), "the longest namespace wins")
+	require.Equal(t, []string{"1"}, mustZMembers(t, mr, "user:"+hashExpirySuffix))
+	require.False(t, mr.Exists("user:session:"+hashExpirySuffix), "no expiry, no expiry entry")
+	require.True(t, mr.Exists("order:1"), "other keys stay top-level")
+
+	data, ok, err := h.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "ada", string(data))
+
+	keys, err := h.Keys(ctx, "user:*")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"user:1", "user:session:9"}, keys)
+
+	require.NoError(t, h.Delete(ctx, "user:1"))
+	_, ok, err = h.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.False(t, mr.Exists("user:"+hashExpirySuffix))
+}
+
+func TestRedisHashCacheTTLEmulation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	h, mr := newHashTestCache(t, clock)
+	require.NoError(t, h.Set(ctx, "user:1", []byte("a"), time.Minute))
+	require.NoError(t, h.Set(ctx, "user:2", []byte("b"), time.Minute))
+	require.NoError(t, h.Set(ctx, "user:3", []byte("c"), time.Hour))
+	require.NoError(t, h.Set(ctx, "user:4", []byte("d"), 0))
+
+	ttl, ok, err := h.TTL(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, ttl)
+	ttl, ok, err = h.TTL(ctx, "user:4")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Zero(t, ttl)
+
+	clock.Advance(2 * time.Minute)
+
+	// Lazy sweep on access.
+	_, ok, err = h.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Empty(t, mr.HGet("user:", "1"), "the expired field was removed")
+	keys, err := h.Keys(ctx, "*")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"user:3", "user:4"}, keys, "expired fields are not listed")
+
+	// The janitor's purge removes the rest.
+	purged, err := h.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	require.Empty(t, mr.HGet("user:", "2"))
+	require.Equal(t, "c", mr.HGet("user:", "3"))
+	require.Equal(t, "d", mr.HGet("user:", "4"))
+}
+
+func TestRedisHashCacheJanitor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	l2, mr := newRenameTestRedis(t)
+	h, err := NewRedisHashCache(l2, RedisHashConfig{Namespaces: []string{"user:"}, JanitorInterval: 5 * time.Millisecond, Clock: clock})
+	require.NoError(t, err)
+	require.NoError(t, h.Set(ctx, "user:1", []byte("a"), time.Minute))
+
+	h.StartJanitor(ctx)
+	defer h.StopJanitor()
+	clock.Advance(2 * time.Minute)
+	require.Eventually(t, func() bool { return !mr.Exists("user:") }, time.Second, 5*time.Millisecond)
+}
+
+func TestRedisHashCacheNamespaceDrop(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h, mr := newHashTestCache(t, nil)
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, h, JSONSerializer{}, MultiLevelConfig{WarmupTTL: time.Minute})
+	require.NoError(t, err)
+	for _, key := range []string{"user:1", "user:2", "user:3", "order:1"} {
+		require.NoError(t, ml.Set(ctx, key, "v", CacheOptions{}))
+	}
+	require.NoError(t, l1.Delete(ctx, "user:3"))
+
+	deleted, err := ml.DeleteByPrefix(ctx, "user:")
+	require.NoError(t, err)
+	require.Equal(t, 3, deleted)
+	require.False(t, mr.Exists("user:"), "the namespace hash was dropped")
+	require.False(t, mr.Exists("user:"+hashExpirySuffix))
+	require.False(t, l1.has("user:1"), "L1 copies are deleted too")
+	require.True(t, mr.Exists("order:1"))
+
+	// A prefix that is not a namespace falls back to per-key deletes.
+	deleted, err = ml.DeleteByPrefix(ctx, "order:")
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	require.False(t, mr.Exists("order:1"))
+}
+
+func mustZMembers(t *testing.T, mr *miniredis.Miniredis, key string) []string {
+	t.Helper()
+	members, err := mr.ZMembers(key)
+	require.NoError(t, err)
+	return members
+}
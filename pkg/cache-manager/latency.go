@@ -0,0 +1,145 @@
+package cache_manager
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultLatencySamples is how many recent samples LatencyTracker keeps per operation.
+const defaultLatencySamples = 1024
+
+// LatencyStats summarises recent latencies of one operation. Percentiles and Mean cover
+// the samples still in the window; Count is the total recorded since start.
+type LatencyStats struct {
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	Mean  time.Duration `json:"mean"`
+	Count uint64        `json:"count"`
+}
+
+// LatencyTracker keeps the last N latency samples per operation in circular buffers.
+// It is safe for concurrent use.
+type LatencyTracker struct {
+	size int
+	mu   sync.RWMutex
+	ops  map[string]*latencyRing
+}
+
+type latencyRing struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	total   uint64
+}
+
+// NewLatencyTracker returns a tracker keeping size samples per operation
+// (defaultLatencySamples when size <= 0).
+func NewLatencyTracker(size int) *LatencyTracker {
+	if size <= 0 {
+		size = defaultLatencySamples
+	}
+	return &LatencyTracker{size: size, ops: make(map[string]*latencyRing)}
+}
+
+// Record adds a sample for op, overwriting the oldest once the buffer is full.
+func (t *LatencyTracker) Record(op string, d time.Duration) {
+	ring := t.ring(op)
+	ring.mu.Lock()
+	if len(ring.samples) < t.size {
+		ring.samples = append(ring.samples, d)
+	} else {
+		ring.samples[ring.next] = d
+	}
+	ring.next = (ring.next + 1) % t.size
+	ring.total++
+	ring.mu.Unlock()
+}
+
+// Percentile returns the pct-th percentile (0-100) of the buffered samples for op,
+// or 0 when none were recorded.
+func (t *LatencyTracker) Percentile(op string, pct float64) time.Duration {
+	sorted, _ := t.snapshot(op)
+	return percentileOf(sorted, pct)
+}
+
+// Report returns LatencyStats for every recorded operation.
+func (t *LatencyTracker) Report() map[string]LatencyStats {
+	t.mu.RLock()
+	ops := make([]string, 0, len(t.ops))
+	for op := range t.ops {
+		ops = append(ops, op)
+	}
+	t.mu.RUnlock()
+
+	report := make(map[string]LatencyStats, len(ops))
+	for _, op := range ops {
+		sorted, total := t.snapshot(op)
+		var sum time.Duration
+		for _, d := range sorted {
+			sum += d
+		}
+		stats := LatencyStats{
+			P50:   percentileOf(sorted, 50),
+			P95:   percentileOf(sorted, 95),
+			P99:   percentileOf(sorted, 99),
+			Count: total,
+		}
+		if len(sorted) > 0 {
+			stats.Mean = sum / time.Duration(len(sorted))
+		}
+		report[op] = stats
+	}
+	return report
+}
+
+func (t *LatencyTracker) ring(op string) *latencyRing {
+	t.mu.RLock()
+	ring, ok := t.ops[op]
+	t.mu.RUnlock()
+	if ok {
+		return ring
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ring, ok = t.ops[op]; !ok {
+		ring = &latencyRing{samples: make([]time.Duration, 0, t.size)}
+		t.ops[op] = ring
+	}
+	return ring
+}
+
+// snapshot returns a sorted copy of op's samples and its total count.
+func (t *LatencyTracker) snapshot(op string) ([]time.Duration, uint64) {
+	t.mu.RLock()
+	ring, ok := t.ops[op]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, 0
+	}
+
+	ring.mu.Lock()
+	sorted := slices.Clone(ring.samples)
+	total := ring.total
+	ring.mu.Unlock()
+
+	slices.Sort(sorted)
+	return sorted, total
+}
+
+// percentileOf uses the nearest-rank method on already sorted samples.
+func percentileOf(sorted []time.Duration, pct float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(pct/100*float64(len(sorted))+0.5) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// LatencyReport returns P50/P95/P99, mean and count for Get, Set and Delete calls.
+func (m *MultiLevelCache) LatencyReport() map[string]LatencyStats {
+	return m.latency.Report()
+}
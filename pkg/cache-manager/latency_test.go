@@ -0,0 +1,71 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyTrackerPercentiles(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewLatencyTracker(100)
+	for i := 1; i <= 100; i++ {
+		tracker.Record("get", time.Duration(i)*time.Millisecond)
+	}
+
+	require.Equal(t, 50*time.Millisecond, tracker.Percentile("get", 50))
+	require.Equal(t, 95*time.Millisecond, tracker.Percentile("get", 95))
+	require.Equal(t, 99*time.Millisecond, tracker.Percentile("get", 99))
+	require.Zero(t, tracker.Percentile("set", 50))
+
+	stats := tracker.Report()["get"]
+	require.Equal(t, uint64(100), stats.Count)
+	require.Equal(t, 50500*time.Microsecond, stats.Mean)
+}
+
+func TestLatencyTrackerKeepsLastSamples(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewLatencyTracker(10)
+	for i := 0; i < 10; i++ {
+		tracker.Record("get", time.Second)
+	}
+	for i := 0; i < 10; i++ {
+		tracker.Record("get", time.Millisecond)
+	}
+
+	stats := tracker.Report()["get"]
+	require.Equal(t, time.Millisecond, stats.P99)
+	require.Equal(t, time.Millisecond, stats.Mean)
+	require.Equal(t, uint64(20), stats.Count)
+}
+
+func TestAdminStatsIncludesLatency(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+	var got string
+	_, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	NewAdminHandler(ml).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		L1      int                     `json:"l1"`
+		Latency map[string]LatencyStats `json:"latency"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, 1, body.L1)
+	require.Equal(t, uint64(1), body.Latency["get"].Count)
+	require.Equal(t, uint64(1), body.Latency["set"].Count)
+}
@@ -0,0 +1,71 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevelEnabledTogglesL2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+
+	require.NoError(t, ml.SetLevelEnabled(LevelL2, false))
+	require.False(t, ml.LevelEnabled(LevelL2))
+	require.NoError(t, ml.Set(ctx, "off", "v", CacheOptions{}))
+	require.True(t, l1.has("off"))
+	require.False(t, l2.has("off"), "a disabled L2 is not written")
+
+	require.NoError(t, l1.Delete(ctx, "off"))
+	require.NoError(t, l2.Set(ctx, "l2-only", []byte(`"v"`), 0))
+	var got string
+	res, err := ml.Get(ctx, "l2-only", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found, "a disabled L2 is not read")
+
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{LevelL2}, stats.DisabledLevels)
+
+	require.NoError(t, ml.SetLevelEnabled(LevelL2, true))
+	require.NoError(t, ml.Set(ctx, "on", "v", CacheOptions{}))
+	require.True(t, l1.has("on"))
+	require.True(t, l2.has("on"))
+	res, err = ml.Get(ctx, "l2-only", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+}
+
+func TestSetLevelEnabledAllLevelsOff(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+	require.NoError(t, ml.SetLevelEnabled(LevelL1, false))
+	require.NoError(t, ml.SetLevelEnabled(LevelL2, false))
+
+	var got string
+	res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err, "targeting only disabled levels is a miss, not an error")
+	require.False(t, res.Found)
+	require.NoError(t, ml.Set(ctx, "new", "v", CacheOptions{}))
+	require.False(t, l1.has("new"))
+	require.NoError(t, ml.Delete(ctx, "k"))
+	require.True(t, l1.has("k"), "disabled levels keep their entries")
+	require.True(t, l2.has("k"))
+}
+
+func TestSetLevelEnabledRejectsUnknownLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+	require.ErrorIs(t, ml.SetLevelEnabled(LevelL2, false), ErrLevelNotConfigured)
+	require.ErrorContains(t, ml.SetLevelEnabled("L3", false), "unknown cache level")
+	require.True(t, ml.LevelEnabled(LevelL1))
+	require.False(t, ml.LevelEnabled(LevelL2))
+}
@@ -0,0 +1,69 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Loader fetches the value for key on a full cache miss when set as MultiLevelConfig.Loader.
+// A ttl of 0 uses the per-call or default TTLs.
+type Loader interface {
+	Load(ctx context.Context, key string) (value any, ttl time.Duration, err error)
+}
+
+// LoaderFunc adapts an ordinary function to the Loader interface.
+type LoaderFunc func(ctx context.Context, key string) (any, time.Duration, error)
+
+// Load calls f(ctx, key).
+func (f LoaderFunc) Load(ctx context.Context, key string) (any, time.Duration, error) {
+	return f(ctx, key)
+}
+
+// ErrSkipCache may be returned by a Loader together with a value to deliver that value
+// to the caller without caching it.
+var ErrSkipCache = errors.New("loaded value must not be cached")
+
+// load resolves a full miss through the configured loader. Loader errors are returned
+// as-is inside a CacheError, so callers can match e.g. their own not-found error.
+// Caching the loaded value is best-effort and does not fail the Get.
+func (m *MultiLevelCache) load(ctx context.Context, key string, dest any, opts CacheOptions) (bool, error) {
+	m.log.Debug(ctx, "cache get loading key", "key", key)
+	tr := traceFrom(ctx)
+	began := tr.start()
+	value, ttl, err := m.loader.Load(ctx, m.logicalKey(key))
+	skipCache := errors.Is(err, ErrSkipCache)
+	loadErr := err
+	if skipCache {
+		loadErr = nil
+	}
+	m.trace(tr, TraceEvent{Op: "get", Step: TraceLoad, Key: key, Result: eventResult(loadErr)}, began, loadErr)
+	if err != nil && !skipCache {
+		m.log.Debug(ctx, "cache get loader error", "key", key, "error", err)
+		m.emit(ctx, "load", key, "", EventError)
+		return false, &CacheError{Op: "load", Key: key, Cause: err}
+	}
+
+	data, err := m.l1Serializer.Marshal(value)
+	if err != nil {
+		m.emit(ctx, "load", key, "", EventError)
+		return false, &CacheError{Op: "load", Key: key, Cause: err}
+	}
+	if err := m.decode(m.l1Serializer, data, dest); err != nil {
+		m.emit(ctx, "load", key, "", EventError)
+		return false, &CacheError{Op: "load", Key: key, Cause: err}
+	}
+	m.emit(ctx, "load", key, "", EventOK)
+
+	if skipCache {
+		m.log.Debug(ctx, "cache get loaded value not cached", "key", key)
+		return true, nil
+	}
+	if ttl > 0 {
+		opts.L1TTL, opts.L2TTL = ttl, ttl
+	}
+	if _, err := m.set(ctx, key, value, opts, false); err != nil {
+		m.log.Debug(ctx, "cache get caching loaded value failed, continuing", "key", key, "error", err)
+	}
+	return true, nil
+}
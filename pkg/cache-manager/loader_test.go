@@ -0,0 +1,112 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type loadedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var errUserMissing = errors.New("user missing")
+
+func TestGetLoadsAndCachesOnMiss(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(_ context.Context, key string) (any, time.Duration, error) {
+		calls++
+		return loadedUser{ID: 1, Name: "Ada"}, 30 * time.Second, nil
+	})})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		var got loadedUser
+		res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+		require.NoError(t, err)
+		require.True(t, res.Found)
+		require.Equal(t, loadedUser{ID: 1, Name: "Ada"}, got)
+	}
+	require.Equal(t, 1, calls)
+	require.Equal(t, 30*time.Second, l1.ttl["user:1"])
+	require.Equal(t, 30*time.Second, l2.ttl["user:1"])
+}
+
+func TestGetLoaderErrorIsReturned(t *testing.T) {
+	t.Parallel()
+
+	loadErr := errors.New("db unavailable")
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+		return nil, 0, loadErr
+	})})
+
+	var got loadedUser
+	res, err := ml.Get(context.Background(), "user:1", &got, CacheOptions{})
+	require.ErrorIs(t, err, loadErr)
+	require.False(t, res.Found)
+
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "load", ce.Op)
+	require.False(t, l1.has("user:1"))
+	require.False(t, l2.has("user:1"))
+}
+
+func TestGetLoaderNotFound(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+		calls++
+		return nil, 0, errUserMissing
+	})})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		var got loadedUser
+		res, err := ml.Get(ctx, "user:404", &got, CacheOptions{})
+		require.ErrorIs(t, err, errUserMissing)
+		require.False(t, res.Found)
+	}
+	// Not-found results are not cached, so every Get asks the loader again.
+	require.Equal(t, 2, calls)
+	require.False(t, l1.has("user:404"))
+	require.False(t, l2.has("user:404"))
+}
+
+func TestGetLoaderSkipCache(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+		return loadedUser{ID: 2, Name: "Grace"}, 0, ErrSkipCache
+	})})
+
+	var got loadedUser
+	res, err := ml.Get(context.Background(), "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelNone}, res)
+	require.Equal(t, loadedUser{ID: 2, Name: "Grace"}, got)
+	require.False(t, l1.has("user:2"))
+	require.False(t, l2.has("user:2"))
+}
+
+func TestGetLoaderRespectsTargetLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+		return loadedUser{ID: 3}, 0, nil
+	})})
+
+	var got loadedUser
+	res, err := ml.Get(context.Background(), "user:3", &got, CacheOptions{TargetL2: BoolPtr(false)})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.True(t, l1.has("user:3"))
+	require.False(t, l2.has("user:3"))
+}
@@ -0,0 +1,67 @@
+package cache_manager
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"sync"
+)
+
+const (
+	// defaultLogSampleRate is the share of per-operation debug logs kept when
+	// MultiLevelConfig.LogSampleRate is nil.
+	defaultLogSampleRate = 0.01
+	// logSampleSeed seeds every cache's sampler so sampling decisions are reproducible.
+	logSampleSeed = 1
+)
+
+// Float64Ptr returns a pointer to a float64 value, e.g. for MultiLevelConfig.LogSampleRate.
+func Float64Ptr(f float64) *float64 {
+	return &f
+}
+
+// sampledLogger forwards a random share of debug records to an slog.Logger so a busy
+// cache does not log every operation.
+type sampledLogger struct {
+	logger    *slog.Logger
+	rate      float64
+	requestID func(context.Context) string
+
+	mu  sync.Mutex // guards rng, which is not safe for concurrent use
+	rng *rand.Rand
+}
+
+func newSampledLogger(logger *slog.Logger, rate float64) *sampledLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &sampledLogger{logger: logger, rate: rate, requestID: RequestIDFromContext, rng: rand.New(rand.NewSource(logSampleSeed))}
+}
+
+// Debug logs msg at debug level if this event is sampled, with the request ID carried
+// by ctx, if any.
+func (l *sampledLogger) Debug(ctx context.Context, msg string, args ...any) {
+	if l.sample() {
+		l.Log(ctx, slog.LevelDebug, msg, args...)
+	}
+}
+
+// Log logs msg at level without sampling, adding the request ID carried by ctx, if any.
+func (l *sampledLogger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if id := l.requestID(ctx); id != "" {
+		args = append(args, "request_id", id)
+	}
+	l.logger.Log(ctx, level, msg, args...)
+}
+
+func (l *sampledLogger) sample() bool {
+	switch {
+	case l.rate <= 0:
+		return false
+	case l.rate >= 1:
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Float64() < l.rate
+}
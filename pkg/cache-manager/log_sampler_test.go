@@ -0,0 +1,85 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newLogSamplerTestCache(t *testing.T, rate float64) (*MultiLevelCache, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		Mode:          ModeBothLevels,
+		WarmupTTL:     time.Minute,
+		Logger:        logger,
+		LogSampleRate: Float64Ptr(rate),
+	})
+	require.NoError(t, err)
+	// The one-off "cache created" record is not sampled; only count per-operation logs.
+	buf.Reset()
+	return ml, &buf
+}
+
+// runLoggedOperations performs n cache operations cycling through Set, Get and Delete.
+func runLoggedOperations(t *testing.T, ml *MultiLevelCache, n int) {
+	t.Helper()
+
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("user:%d", i/3)
+		switch i % 3 {
+		case 0:
+			require.NoError(t, ml.Set(ctx, key, i, CacheOptions{}))
+		case 1:
+			var got int
+			_, err := ml.Get(ctx, key, &got, CacheOptions{})
+			require.NoError(t, err)
+		case 2:
+			require.NoError(t, ml.Delete(ctx, key))
+		}
+	}
+}
+
+func TestLogSampleRateZeroEmitsNothing(t *testing.T) {
+	t.Parallel()
+
+	ml, buf := newLogSamplerTestCache(t, 0)
+	runLoggedOperations(t, ml, 1000)
+	require.Zero(t, buf.Len(), "unexpected log output:\n%s", buf.String())
+}
+
+func TestLogSampleRateOneEmitsEveryRecord(t *testing.T) {
+	t.Parallel()
+
+	ml, buf := newLogSamplerTestCache(t, 1)
+	runLoggedOperations(t, ml, 3)
+	require.Equal(t, 6, strings.Count(buf.String(), "\n"), buf.String())
+	require.Contains(t, buf.String(), `msg="cache get l1 hit" key=user:0`)
+}
+
+func TestSampledLoggerIsReproducible(t *testing.T) {
+	t.Parallel()
+
+	count := func() int {
+		l := newSampledLogger(slog.Default(), 0.1)
+		n := 0
+		for i := 0; i < 10000; i++ {
+			if l.sample() {
+				n++
+			}
+		}
+		return n
+	}
+	first := count()
+	require.Equal(t, first, count())
+	require.InDelta(t, 1000, first, 150)
+}
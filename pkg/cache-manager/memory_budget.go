@@ -0,0 +1,148 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// SkipReasonMemoryBudget is reported to OnSkip when an L1 write would take the
+// estimated L1 usage over L1SoftMemoryBudget.
+const SkipReasonMemoryBudget = "l1_skipped_memory_budget"
+
+// EvictionNotifier is implemented by raw caches that report removed entries, such as
+// BigCache. MultiLevelCache uses it to subtract expired and evicted entries from its L1
+// memory estimate.
+type EvictionNotifier interface {
+	// OnEviction registers fn to be called for every removed entry with its payload
+	// size. fn runs on the cache's write path and must be cheap.
+	OnEviction(fn func(key string, reason EvictionReason, size int))
+}
+
+var _ EvictionNotifier = (*BigCache)(nil)
+
+// L1MemoryUsage is the L1 payload accounting of MultiLevelConfig.L1SoftMemoryBudget.
+type L1MemoryUsage struct {
+	// Used is the estimated size in bytes of the payloads the cache wrote to L1 and
+	// that were not deleted, expired or evicted since.
+	Used int64 `json:"used"`
+	// Budget is L1SoftMemoryBudget.
+	Budget int64 `json:"budget"`
+	// Skipped counts the L1 writes skipped because they would exceed Budget.
+	Skipped uint64 `json:"skipped"`
+}
+
+// l1MemoryBudget tracks the payload size of every key written to L1, so overwrites,
+// deletes and evictions can be subtracted exactly.
+type l1MemoryBudget struct {
+	budget  int64
+	skipped atomic.Uint64
+
+	mu    sync.Mutex
+	sizes map[string]int
+	used  int64
+}
+
+func newL1MemoryBudget(budget int64) *l1MemoryBudget {
+	if budget <= 0 {
+		return nil
+	}
+	return &l1MemoryBudget{budget: budget, sizes: make(map[string]int)}
+}
+
+// fits reports whether writing size bytes under key stays within the budget, counting
+// the entry it would replace as freed.
+func (b *l1MemoryBudget) fits(key string, size int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used-int64(b.sizes[key])+int64(size) <= b.budget
+}
+
+// holds reports whether key is counted as resident in L1.
+func (b *l1MemoryBudget) holds(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.sizes[key]
+	return ok
+}
+
+// record counts a successful L1 write of size bytes under key.
+func (b *l1MemoryBudget) record(key string, size int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used += int64(size - b.sizes[key])
+	b.sizes[key] = size
+}
+
+// forget stops counting key after a Delete.
+func (b *l1MemoryBudget) forget(key string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= int64(b.sizes[key])
+	delete(b.sizes, key)
+}
+
+// evicted is the EvictionNotifier callback. An entry of another size is a newer write
+// of the same key, so it is kept.
+func (b *l1MemoryBudget) evicted(key string, _ EvictionReason, size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if current, ok := b.sizes[key]; ok && current == size {
+		b.used -= int64(size)
+		delete(b.sizes, key)
+	}
+}
+
+func (b *l1MemoryBudget) usage() L1MemoryUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return L1MemoryUsage{Used: b.used, Budget: b.budget, Skipped: b.skipped.Load()}
+}
+
+// skipL1Budget reports whether an L1 write of size bytes under key would exceed
+// L1SoftMemoryBudget, counting the skip and calling OnSkip. A skipped overwrite deletes
+// the older L1 entry so L1 cannot serve it after the new value went to L2.
+func (m *MultiLevelCache) skipL1Budget(ctx context.Context, key string, size int) bool {
+	if m.l1Budget == nil || m.l1Budget.fits(key, size) {
+		return false
+	}
+	m.l1Budget.skipped.Add(1)
+	if m.onSkip != nil {
+		m.onSkip(key, LevelL1, SkipReasonMemoryBudget)
+	}
+	if m.l1Budget.holds(key) {
+		if err := m.l1.Delete(ctx, key); err != nil {
+			m.log.Debug(ctx, "cache l1 delete of replaced entry failed", "key", key, "error", err)
+		}
+		m.l1Budget.forget(key)
+	}
+	return true
+}
+
+// MemoryUsage returns the estimated L1 payload bytes against L1SoftMemoryBudget. It is
+// zero unless the budget is set.
+func (m *MultiLevelCache) MemoryUsage() L1MemoryUsage {
+	if m == nil || m.l1Budget == nil {
+		return L1MemoryUsage{}
+	}
+	return m.l1Budget.usage()
+}
+
+// OnEviction registers fn to run, after BigCacheConfig.OnEvict, whenever bigcache
+// removes an entry.
+func (b *BigCache) OnEviction(fn func(key string, reason EvictionReason, size int)) {
+	b.listenerMu.Lock()
+	defer b.listenerMu.Unlock()
+	var next []func(string, EvictionReason, int)
+	if current := b.listeners.Load(); current != nil {
+		next = append(next, *current...)
+	}
+	next = append(next, fn)
+	b.listeners.Store(&next)
+}
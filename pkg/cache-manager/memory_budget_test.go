@@ -0,0 +1,104 @@
+package cache_manager
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestL1SoftMemoryBudgetSkipsAndRecovers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l1 := newFakeClockBigCache(t, newFakeClock(), BigCacheConfig{})
+	l2 := newMemoryRawCache()
+	var mu sync.Mutex
+	var skips []string
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:               ModeBothLevels,
+		L1DefaultTTL:       time.Minute,
+		L2DefaultTTL:       time.Minute,
+		WarmupTTL:          time.Minute,
+		L1SoftMemoryBudget: 10_000,
+		OnSkip: func(key, level, reason string) {
+			mu.Lock()
+			defer mu.Unlock()
+			skips = append(skips, key+"/"+level+"/"+reason)
+		},
+	})
+	require.NoError(t, err)
+
+	big := strings.Repeat("x", 2998) // 3000 bytes as JSON
+	for _, k := range []string{"a", "b", "c"} {
+		require.NoError(t, ml.Set(ctx, k, big, CacheOptions{}))
+	}
+	require.Equal(t, L1MemoryUsage{Used: 9000, Budget: 10_000}, ml.MemoryUsage())
+
+	require.NoError(t, ml.Set(ctx, "d", big, CacheOptions{}))
+	_, ok, err := l1.Get(ctx, "d")
+	require.NoError(t, err)
+	require.False(t, ok, "the write over budget skips L1")
+	require.True(t, l2.has("d"), "L2 is still written")
+	require.Equal(t, []string{"d/L1/" + SkipReasonMemoryBudget}, skips)
+
+	var got string
+	res, err := ml.Get(ctx, "d", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+	_, ok, err = l1.Get(ctx, "d")
+	require.NoError(t, err)
+	require.False(t, ok, "warmup is skipped over budget too")
+
+	require.NoError(t, ml.Delete(ctx, "a"))
+	require.Equal(t, int64(6000), ml.MemoryUsage().Used)
+	require.NoError(t, ml.Set(ctx, "d", big, CacheOptions{}))
+	_, ok, err = l1.Get(ctx, "d")
+	require.NoError(t, err)
+	require.True(t, ok, "writes resume once usage drops")
+
+	// Removals made by L1 itself are subtracted through its eviction callback.
+	require.NoError(t, l1.Delete(ctx, "b"))
+	require.Equal(t, int64(6000), ml.MemoryUsage().Used)
+
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &L1MemoryUsage{Used: 6000, Budget: 10_000, Skipped: 2}, stats.L1Memory)
+}
+
+func TestL1SoftMemoryBudgetDropsReplacedEntry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:               ModeBothLevels,
+		L1DefaultTTL:       time.Minute,
+		L2DefaultTTL:       time.Minute,
+		L1SoftMemoryBudget: 100,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Set(ctx, "k", "small", CacheOptions{}))
+	require.True(t, l1.has("k"))
+	require.NoError(t, ml.Set(ctx, "k", strings.Repeat("x", 200), CacheOptions{}))
+	require.False(t, l1.has("k"), "the old value must not outlive an overwrite skipped for the budget")
+	require.Zero(t, ml.MemoryUsage().Used)
+
+	var got string
+	_, err = ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Len(t, got, 200)
+}
+
+func TestL1SoftMemoryBudgetValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{L1SoftMemoryBudget: -1})
+	require.ErrorContains(t, err, "must not be negative")
+	_, err = NewMultiLevelCache(nil, newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only, L1SoftMemoryBudget: 1})
+	require.ErrorIs(t, err, ErrLevelNotConfigured)
+}
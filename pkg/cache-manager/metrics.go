@@ -0,0 +1,24 @@
+package cache_manager
+
+import "time"
+
+// MetricsCollector receives per-operation cache outcomes. Op is "get", "set" or
+// "delete"; level is LevelL1, LevelL2, or empty when the outcome spans levels
+// (an overall miss, or the latency of a whole call).
+// Implementations must be safe for concurrent use and should not block.
+type MetricsCollector interface {
+	RecordHit(op, level string)
+	RecordMiss(op, level string)
+	RecordError(op, level string)
+	RecordLatency(op, level string, d time.Duration)
+}
+
+// observeLatency records the duration of a whole Get/Set/Delete call in the latency
+// tracker and metrics collector. Use with defer.
+func (m *MultiLevelCache) observeLatency(op string, start time.Time) {
+	d := time.Since(start)
+	m.latency.Record(op, d)
+	if m.metrics != nil {
+		m.metrics.RecordLatency(op, "", d)
+	}
+}
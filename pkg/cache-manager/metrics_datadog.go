@@ -0,0 +1,67 @@
+package cache_manager
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+// Datadog metric names emitted by DatadogCollector.
+const (
+	datadogMetricHit     = "cache.hit"
+	datadogMetricMiss    = "cache.miss"
+	datadogMetricError   = "cache.error"
+	datadogMetricLatency = "cache.latency"
+)
+
+// DatadogCollector implements MetricsCollector on top of a DogStatsD client.
+// Counters are tagged op:<op>, level:<level> (when known) and namespace:<namespace>;
+// latency is sent as a distribution in milliseconds.
+type DatadogCollector struct {
+	client    statsd.ClientInterface
+	namespace string
+}
+
+// NewDatadogCollector returns a collector that tags every metric with namespace,
+// normally MultiLevelConfig.Namespace.
+func NewDatadogCollector(client statsd.ClientInterface, namespace string) *DatadogCollector {
+	return &DatadogCollector{client: client, namespace: namespace}
+}
+
+func (d *DatadogCollector) RecordHit(op, level string) {
+	d.incr(datadogMetricHit, op, level)
+}
+
+func (d *DatadogCollector) RecordMiss(op, level string) {
+	d.incr(datadogMetricMiss, op, level)
+}
+
+func (d *DatadogCollector) RecordError(op, level string) {
+	d.incr(datadogMetricError, op, level)
+}
+
+func (d *DatadogCollector) RecordLatency(op, level string, dur time.Duration) {
+	ms := float64(dur) / float64(time.Millisecond)
+	if err := d.client.Distribution(datadogMetricLatency, ms, d.tags(op, level), 1); err != nil {
+		slog.Debug("datadog distribution failed", "metric", datadogMetricLatency, "error", err)
+	}
+}
+
+func (d *DatadogCollector) incr(name, op, level string) {
+	if err := d.client.Incr(name, d.tags(op, level), 1); err != nil {
+		slog.Debug("datadog incr failed", "metric", name, "error", err)
+	}
+}
+
+func (d *DatadogCollector) tags(op, level string) []string {
+	tags := make([]string, 0, 3)
+	tags = append(tags, "op:"+op)
+	if level != "" {
+		tags = append(tags, "level:"+level)
+	}
+	if d.namespace != "" {
+		tags = append(tags, "namespace:"+d.namespace)
+	}
+	return tags
+}
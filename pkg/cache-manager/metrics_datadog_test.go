@@ -0,0 +1,69 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mock_statsd "github.com/DataDog/datadog-go/v5/statsd/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newDatadogTestCache(t *testing.T, l1, l2 RawCache) (*MultiLevelCache, *mock_statsd.MockClientInterface) {
+	t.Helper()
+
+	client := mock_statsd.NewMockClientInterface(gomock.NewController(t))
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:      ModeBothLevels,
+		Namespace: "users",
+		Metrics:   NewDatadogCollector(client, "users"),
+	})
+	require.NoError(t, err)
+	return ml, client
+}
+
+func TestDatadogCollectorHit(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	ml, client := newDatadogTestCache(t, l1, newMemoryRawCache())
+	ctx := context.Background()
+	require.NoError(t, l1.Set(ctx, "user:1", []byte(`"ada"`), time.Minute))
+
+	client.EXPECT().Incr("cache.hit", []string{"op:get", "level:L1", "namespace:users"}, 1.0).Return(nil)
+	client.EXPECT().Distribution("cache.latency", gomock.Any(), []string{"op:get", "namespace:users"}, 1.0).Return(nil)
+
+	var got string
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+}
+
+func TestDatadogCollectorMiss(t *testing.T) {
+	t.Parallel()
+
+	ml, client := newDatadogTestCache(t, newMemoryRawCache(), newMemoryRawCache())
+
+	client.EXPECT().Incr("cache.miss", []string{"op:get", "namespace:users"}, 1.0).Return(nil)
+	client.EXPECT().Distribution("cache.latency", gomock.Any(), []string{"op:get", "namespace:users"}, 1.0).Return(nil)
+
+	var got string
+	res, err := ml.Get(context.Background(), "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+}
+
+func TestDatadogCollectorError(t *testing.T) {
+	t.Parallel()
+
+	ml, client := newDatadogTestCache(t, newMemoryRawCache(), failingRawCache{err: errors.New("redis down")})
+
+	client.EXPECT().Incr("cache.error", []string{"op:get", "level:L2", "namespace:users"}, 1.0).Return(nil)
+	client.EXPECT().Distribution("cache.latency", gomock.Any(), []string{"op:get", "namespace:users"}, 1.0).Return(nil)
+
+	var got string
+	_, err := ml.Get(context.Background(), "user:1", &got, CacheOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// MigrationReport summarizes a MigrateSerializer run. In a dry run Migrated counts the
+// keys that would have been rewritten.
+type MigrationReport struct {
+	Migrated int64    `json:"migrated"`
+	Failed   int64    `json:"failed"`
+	Skipped  int64    `json:"skipped"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// MigrationOptions tunes MigrateSerializer.
+type MigrationOptions struct {
+	// BatchSize is how many keys are read per MGET and written per pipeline. Default 100.
+	BatchSize int
+	// Limiter is waited on once per batch, e.g. rate.NewLimiter(10, 1) for at most
+	// 10 batches per second. nil runs batches back to back.
+	Limiter *rate.Limiter
+	// MaxErrors caps MigrationReport.Errors; Failed still counts every failure. Default 100.
+	MaxErrors int
+}
+
+// MigrateSerializer rewrites the Redis entries matching the glob match from the from
+// format to the to format, e.g. JSON to msgpack, keeping each key's TTL. Values are
+// decoded into generic maps, slices and scalars (JSON numbers become int64 when they
+// are integers), so types the from format does not record, such as time.Time in JSON,
+// arrive in their encoded form; run with dryRun first.
+//
+// Entries written with L2 compression, content hashes or chunking are skipped, as are
+// keys that disappear during the run. A key rewritten by the application between the
+// read and the write is overwritten with the migrated old value, so migrate while the
+// application already reads and writes the to format, or while it is stopped.
+func MigrateSerializer(ctx context.Context, from, to Serializer, cache *RedisCache, match string, dryRun bool, opts MigrationOptions) (*MigrationReport, error) {
+	if from == nil || to == nil {
+		return nil, &CacheError{Op: "migrate", Level: LevelL2, Cause: ErrSerializerMissing}
+	}
+	if cache == nil || cache.client == nil {
+		return nil, &CacheError{Op: "migrate", Level: LevelL2, Cause: ErrNotInitialized}
+	}
+	if match == "" {
+		match = "*"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.MaxErrors <= 0 {
+		opts.MaxErrors = 100
+	}
+
+	m := &serializerMigration{from: from, to: to, cache: cache, dryRun: dryRun, opts: opts, report: &MigrationReport{}}
+	batch := make([]string, 0, opts.BatchSize)
+	iter := cache.client.Scan(ctx, 0, match, int64(opts.BatchSize)).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) < opts.BatchSize {
+			continue
+		}
+		if err := m.migrateBatch(ctx, batch); err != nil {
+			return m.report, err
+		}
+		batch = batch[:0]
+	}
+	if err := iter.Err(); err != nil {
+		return m.report, &CacheError{Op: "migrate", Level: LevelL2, Cause: err}
+	}
+	if len(batch) > 0 {
+		if err := m.migrateBatch(ctx, batch); err != nil {
+			return m.report, err
+		}
+	}
+	return m.report, nil
+}
+
+// serializerMigration holds the state of one MigrateSerializer run.
+type serializerMigration struct {
+	from, to Serializer
+	cache    *RedisCache
+	dryRun   bool
+	opts     MigrationOptions
+	report   *MigrationReport
+}
+
+// migrateBatch converts one batch of keys. Only Redis and context errors abort the run;
+// per-key problems are recorded in the report.
+func (m *serializerMigration) migrateBatch(ctx context.Context, keys []string) error {
+	if m.opts.Limiter != nil {
+		if err := m.opts.Limiter.Wait(ctx); err != nil {
+			return &CacheError{Op: "migrate", Level: LevelL2, Cause: err}
+		}
+	}
+
+	values, err := m.cache.MGet(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	var writeKeys []string
+	var writeValues [][]byte
+	for i, key := range keys {
+		data := values[i]
+		if data == nil || isFramedL2Value(key, data) {
+			m.report.Skipped++
+			continue
+		}
+
+		value, err := decodeGeneric(m.from, data)
+		if err != nil {
+			m.fail(key, fmt.Errorf("decode: %w", err))
+			continue
+		}
+		converted, err := m.to.Marshal(value)
+		if err != nil {
+			m.fail(key, fmt.Errorf("encode: %w", err))
+			continue
+		}
+		writeKeys = append(writeKeys, key)
+		writeValues = append(writeValues, converted)
+	}
+
+	if m.dryRun {
+		m.report.Migrated += int64(len(writeKeys))
+		return nil
+	}
+	return m.write(ctx, writeKeys, writeValues)
+}
+
+// write stores the converted values in one pipeline. SET XX KEEPTTL leaves keys that
+// expired since the read absent and keeps the expiry of the others.
+func (m *serializerMigration) write(ctx context.Context, keys []string, values [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cmds := make([]*redis.StatusCmd, len(keys))
+	// Per-command results are checked below; Pipelined only repeats the first of them.
+	_, _ = m.cache.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = p.SetArgs(ctx, key, values[i], redis.SetArgs{Mode: "XX", KeepTTL: true})
+		}
+		return nil
+	})
+	if err := ctx.Err(); err != nil {
+		return &CacheError{Op: "migrate", Level: LevelL2, Cause: err}
+	}
+
+	for i, cmd := range cmds {
+		switch err := cmd.Err(); {
+		case err == nil:
+			m.report.Migrated++
+		case errors.Is(err, redis.Nil):
+			m.report.Skipped++
+		default:
+			m.fail(keys[i], fmt.Errorf("write: %w", err))
+		}
+	}
+	return nil
+}
+
+func (m *serializerMigration) fail(key string, err error) {
+	m.report.Failed++
+	if len(m.report.Errors) < m.opts.MaxErrors {
+		m.report.Errors = append(m.report.Errors, fmt.Sprintf("%s: %v", key, err))
+	}
+}
+
+// decodeGeneric decodes data into an any. JSON is decoded with UseNumber so integers
+// keep their precision and are re-encoded as integers rather than floats.
+func decodeGeneric(s Serializer, data []byte) (any, error) {
+	var value any
+	if _, isJSON := s.(JSONSerializer); !isJSON {
+		err := s.Unmarshal(data, &value)
+		return value, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return convertJSONNumbers(value), nil
+}
+
+// convertJSONNumbers replaces json.Number values with int64, or float64 when the number
+// is not an integer.
+func convertJSONNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = convertJSONNumbers(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = convertJSONNumbers(elem)
+		}
+	}
+	return v
+}
+
+// isFramedL2Value reports whether data was written by a MultiLevelCache feature that
+// wraps the serialized payload, or is a chunk of a chunked value.
+func isFramedL2Value(key string, data []byte) bool {
+	return bytes.HasPrefix(data, compressedMagic) ||
+		bytes.HasPrefix(data, contentHashMagic) ||
+		bytes.HasPrefix(data, chunkManifestMagic) ||
+		strings.Contains(key, ":__chunk:")
+}
@@ -0,0 +1,108 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+type migrationUser struct {
+	ID   int      `json:"id" msgpack:"id"`
+	Name string   `json:"name" msgpack:"name"`
+	Tags []string `json:"tags" msgpack:"tags"`
+}
+
+func newMigrationTestRedis(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	cache, err := NewRedisCache(client)
+	require.NoError(t, err)
+	return cache, mr
+}
+
+func seedJSONUsers(t *testing.T, cache *RedisCache, n int) {
+	t.Helper()
+
+	for i := 1; i <= n; i++ {
+		data, err := json.Marshal(migrationUser{ID: i, Name: fmt.Sprintf("user-%d", i), Tags: []string{"a"}})
+		require.NoError(t, err)
+		require.NoError(t, cache.Set(context.Background(), fmt.Sprintf("user:%d", i), data, time.Hour))
+	}
+}
+
+func TestMigrateSerializerJSONToMsgpack(t *testing.T) {
+	t.Parallel()
+
+	cache, mr := newMigrationTestRedis(t)
+	ctx := context.Background()
+	seedJSONUsers(t, cache, 10)
+	require.NoError(t, mr.Set("other:1", `"untouched"`))
+
+	report, err := MigrateSerializer(ctx, JSONSerializer{}, MsgpackSerializer{}, cache, "user:*", false,
+		MigrationOptions{BatchSize: 3, Limiter: rate.NewLimiter(rate.Inf, 1)})
+	require.NoError(t, err)
+	require.Equal(t, &MigrationReport{Migrated: 10}, report)
+
+	for i := 1; i <= 10; i++ {
+		key := fmt.Sprintf("user:%d", i)
+		data, found, err := cache.Get(ctx, key)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		var got migrationUser
+		require.NoError(t, MsgpackSerializer{}.Unmarshal(data, &got))
+		require.Equal(t, migrationUser{ID: i, Name: fmt.Sprintf("user-%d", i), Tags: []string{"a"}}, got)
+		require.Equal(t, time.Hour, mr.TTL(key), "migration must keep the TTL")
+	}
+	other, err := mr.Get("other:1")
+	require.NoError(t, err)
+	require.Equal(t, `"untouched"`, other)
+}
+
+func TestMigrateSerializerDryRunWritesNothing(t *testing.T) {
+	t.Parallel()
+
+	cache, mr := newMigrationTestRedis(t)
+	seedJSONUsers(t, cache, 4)
+	before, err := mr.Get("user:1")
+	require.NoError(t, err)
+
+	report, err := MigrateSerializer(context.Background(), JSONSerializer{}, MsgpackSerializer{}, cache, "user:*", true, MigrationOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), report.Migrated)
+
+	after, err := mr.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func TestMigrateSerializerReportsFailuresAndSkips(t *testing.T) {
+	t.Parallel()
+
+	cache, mr := newMigrationTestRedis(t)
+	seedJSONUsers(t, cache, 1)
+	require.NoError(t, mr.Set("user:bad", "not json"))
+	require.NoError(t, mr.Set("user:zipped", string(compressedMagic)+"\x01..."))
+
+	report, err := MigrateSerializer(context.Background(), JSONSerializer{}, MsgpackSerializer{}, cache, "user:*", false, MigrationOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), report.Migrated)
+	require.Equal(t, int64(1), report.Failed)
+	require.Equal(t, int64(1), report.Skipped)
+	require.Len(t, report.Errors, 1)
+	require.Contains(t, report.Errors[0], "user:bad: decode:")
+}
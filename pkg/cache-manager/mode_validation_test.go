@@ -0,0 +1,61 @@
+package cache_manager
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictModeValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		validation ModeValidation
+		mode       CacheMode
+		extra      string
+		wantLog    bool
+		wantErr    bool
+	}{
+		{name: "warn l1 only", validation: ModeValidationWarn, mode: ModeL1Only, extra: LevelL2, wantLog: true},
+		{name: "warn l2 only", validation: ModeValidationWarn, mode: ModeL2Only, extra: LevelL1, wantLog: true},
+		{name: "silent l1 only", validation: ModeValidationSilent, mode: ModeL1Only, extra: LevelL2},
+		{name: "silent l2 only", validation: ModeValidationSilent, mode: ModeL2Only, extra: LevelL1},
+		{name: "error l1 only", validation: ModeValidationError, mode: ModeL1Only, extra: LevelL2, wantErr: true},
+		{name: "error l2 only", validation: ModeValidationError, mode: ModeL2Only, extra: LevelL1, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+			ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+				Mode:                 tc.mode,
+				StrictModeValidation: tc.validation,
+				Logger:               slog.New(slog.NewTextHandler(&logs, nil)),
+			})
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrModeMismatch)
+				var ce *CacheError
+				require.ErrorAs(t, err, &ce)
+				require.Equal(t, tc.extra, ce.Level)
+				require.Nil(t, ml)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantLog, bytes.Contains(logs.Bytes(), []byte("cache mode mismatch")), logs.String())
+		})
+	}
+}
+
+func TestStrictModeValidationAllowsMatchingLevels(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, StrictModeValidation: ModeValidationError})
+	require.NoError(t, err)
+	_, err = NewMultiLevelCache(nil, newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only, StrictModeValidation: ModeValidationError})
+	require.NoError(t, err)
+	_, err = NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{StrictModeValidation: ModeValidationError})
+	require.NoError(t, err)
+}
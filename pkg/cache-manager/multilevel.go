@@ -1,16 +1,20 @@
 package cache_manager
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-)
+	"unicode/utf8"
 
-var (
-	// ErrSerializerMissing indicates serializer dependency absent.
-	ErrSerializerMissing = errors.New("serializer is required")
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
 // RawCache represents a low-level cache storing raw bytes.
@@ -20,10 +24,25 @@ type RawCache interface {
 	Delete(ctx context.Context, key string) error
 }
 
+// KeepTTLSetter is implemented by raw caches that can overwrite a value without resetting
+// its expiry. When the key is absent the value is stored with ttl.
+type KeepTTLSetter interface {
+	SetKeepTTL(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// PrioritySetter is implemented by raw caches that can store an eviction priority with an entry.
+type PrioritySetter interface {
+	SetWithPriority(ctx context.Context, key string, value []byte, ttl time.Duration, priority int8) error
+}
+
 // MultiLevelConfig exposes optional tuning knobs.
 type MultiLevelConfig struct {
 	// Mode defines the default caching strategy. Defaults to ModeBothLevels.
 	Mode CacheMode
+	// StrictModeValidation sets what happens when both levels are passed but Mode uses
+	// only one: ModeValidationWarn (default) logs and ignores the other level,
+	// ModeValidationSilent only ignores it, ModeValidationError fails construction.
+	StrictModeValidation ModeValidation
 	// WarmupTTL is the TTL applied when populating L1 from an L2 hit.
 	// Defaults to 5 minutes when zero.
 	WarmupTTL time.Duration
@@ -31,24 +50,286 @@ type MultiLevelConfig struct {
 	L1DefaultTTL time.Duration
 	// L2DefaultTTL is used when CacheOptions do not specify an L2 TTL.
 	L2DefaultTTL time.Duration
+	// MaxFlushPerMinute caps Flush and DeleteByPrefix calls using a token bucket.
+	// Zero disables the limit.
+	MaxFlushPerMinute int
+	// CoalesceL2Reads shares one L2 round trip between concurrent Gets for the same key.
+	// Errors are only shared with callers that joined the in-flight read.
+	CoalesceL2Reads bool
+	// L1Serializer and L2Serializer override the constructor's serializer for one level,
+	// e.g. gob in L1 for speed and JSON in L2 for non-Go readers. Warmup re-encodes
+	// L2 hits into the L1 format.
+	L1Serializer Serializer
+	L2Serializer Serializer
+	// L1MaxValueBytes skips the L1 write (Set and warmup) for serialized payloads larger
+	// than this, so a few huge values cannot evict many small ones. 0 = no limit.
+	L1MaxValueBytes int
+	// L1SoftMemoryBudget caps the estimated bytes of payloads kept in L1. Once a write
+	// would go over it, L1 writes (Set and warmup) are skipped, L2 is still written, and
+	// OnSkip gets SkipReasonMemoryBudget until deletes, expiry or evictions bring the
+	// estimate back down. The estimate counts what the cache wrote minus what it deleted;
+	// expiry and evictions are subtracted only when L1 is an EvictionNotifier, like
+	// BigCache. See MemoryUsage. 0 = no budget.
+	L1SoftMemoryBudget int64
+	// OnSkip is called when a level write is skipped on purpose, with a reason such as
+	// SkipReasonOversize. It runs synchronously and must be cheap.
+	OnSkip func(key, level, reason string)
+	// Namespace names this cache instance in metrics (e.g. the namespace:<ns> tag).
+	Namespace string
+	// Metrics receives hit/miss/error counts and call latency. nil disables metrics.
+	Metrics MetricsCollector
+	// L2Compression compresses payloads written to L2; reads decompress before
+	// decoding or warming L1, so L1 always holds plain bytes.
+	L2Compression L2CompressionConfig
+	// ChangeTrackingMaxKeys bounds the payload hashes kept for SetIfChanged
+	// (least recently set keys are dropped first). Default 10000.
+	ChangeTrackingMaxKeys int
+	// LatencySamples is how many recent samples per operation LatencyReport uses. Default 1024.
+	LatencySamples int
+	// Audit receives every Get/Set/Delete outcome, e.g. a RedisAuditLogger. nil disables it.
+	Audit AuditLogger
+	// EventBus shares deletions with the other caches of the process on the same bus:
+	// Delete publishes a CacheDeleteEvent on "cache.delete.<mode>", and every other cache
+	// of the same mode evicts the key from its L1. nil disables it.
+	EventBus *EventBus
+	// L2ChunkThreshold splits L2 payloads larger than this many bytes (after compression)
+	// into chunk keys plus a manifest under the original key. 0 disables chunking.
+	// PreserveTTL is ignored for chunked values.
+	L2ChunkThreshold int
+	// TTLProvider computes per-key default TTLs (e.g. PatternTTLProvider); nil uses
+	// L1DefaultTTL/L2DefaultTTL for every key.
+	TTLProvider TTLProvider
+	// Loader makes Get read-through: on a full miss the loaded value is cached in the
+	// targeted levels and returned in dest. nil keeps plain cache-aside behavior.
+	Loader Loader
+	// Logger receives the per-operation debug logs. nil uses slog.Default().
+	Logger *slog.Logger
+	// RequestID extracts the ID of the request an operation belongs to from its context,
+	// for the request_id attribute of the cache's logs and CacheEvent.RequestID. nil uses
+	// RequestIDFromContext, which reads IDs stored by WithRequestID.
+	RequestID func(ctx context.Context) string
+	// LogSampleRate is the fraction of per-operation debug logs emitted: 0 disables them,
+	// 1 logs everything. nil uses 0.01.
+	LogSampleRate *float64
+	// Version is folded into every stored key ("v7" stores "user:42" as "v7:user:42"), so
+	// bumping it at deploy time makes entries written by older builds unreachable; they
+	// then age out through their TTLs. Keys returned by Keys and ListL1Keys and keys in
+	// events are unversioned; events carry Version separately. Empty disables versioning.
+	Version string
+	// InstanceName is folded into stored keys ahead of Version ("users-l1" stores
+	// "user:42" as "users-l1:user:42"), so several caches can share one L1 or L2 without
+	// reading each other's entries; Keys, Flush and DeleteByPrefix only see this
+	// instance's keys. It must not contain ":". Empty shares the unprefixed key space.
+	InstanceName string
+	// Clock timestamps events and expires SetIfChanged hashes. nil uses the system clock.
+	// Pass the same fake clock to BigCacheConfig.Clock to control L1 expiry in tests.
+	Clock Clock
+	// ContentHashes stores a hash of the serialized payload with every Set (in both
+	// levels, carried over by warmup), exposed as EntryMetadata.ETag by GetWithMetadata.
+	ContentHashes bool
+	// Degradation stops calling L2 while it is down and probes it in the background.
+	// The zero value disables it.
+	Degradation DegradationConfig
+	// ValidateOnWrite decodes every payload Set produces into a fresh value of the same
+	// type before writing it, so a serializer that returns truncated or garbage bytes
+	// without an error fails the Set with ErrSerialization instead of poisoning the
+	// cache. It roughly doubles the cost of Set.
+	ValidateOnWrite bool
+	// AutoWarmOnStart copies the entries this instance finds in L2 into L1 in the
+	// background when the cache is constructed, so a new process does not start with a
+	// cold L1. It needs both levels and a single serializer; WaitForWarmup blocks until
+	// it is done and WarmupStatus reports progress.
+	AutoWarmOnStart bool
+	// WarmupMaxKeys caps how many L2 keys AutoWarmOnStart copies. Default 10000.
+	WarmupMaxKeys int
+	// WarmupConcurrency is how many keys AutoWarmOnStart copies at a time. Default 10.
+	WarmupConcurrency int
+	// WarmupTimeout bounds the whole AutoWarmOnStart run. Default 1 minute.
+	WarmupTimeout time.Duration
+	// WarmupBatch queues the L1 warmups of L2 hits and writes them in batches instead
+	// of before Get returns. The remaining L2 TTLs of a batch are looked up in one round
+	// trip when L2 is a MultiTTLInspector, as RedisCache is, so a warmed entry does not
+	// outlive its L2 copy. Close writes the warmups still queued. Disabled by default.
+	WarmupBatch WarmupBatchConfig
+	// HitRates tracks the hit rate of each key prefix (the part before the first ":")
+	// over a sliding window, exposed by HitRateByPrefix, and logs the prefixes whose
+	// rate suggests a TTL that is too short. Disabled by default.
+	HitRates HitRateConfig
+	// L2RateLimiter, e.g. a DistributedRateLimiter, is asked for one token before every
+	// L2 write of Set, so many processes populating the cache at once cannot saturate
+	// Redis. A denied write is skipped and logged as a warning; L1 is still written.
+	// If the limiter fails, the write goes ahead. nil disables the limit.
+	L2RateLimiter RateLimiter
+	// L2RateLimitID names the bucket L2RateLimiter draws from; caches sharing an ID
+	// share a budget. Defaults to Namespace, then InstanceName, then "l2-writes".
+	L2RateLimitID string
+	// L2MaxConcurrency caps the number of L2 operations in flight at once, protecting
+	// Redis from a stampede of slow calls. An operation that cannot start within
+	// L2AcquireTimeout is throttled: a read is treated as a miss and a write or delete
+	// fails with ErrL2Throttled. Stats reports both the in-flight and throttled counts.
+	// Zero means unlimited.
+	L2MaxConcurrency int
+	// L2AcquireTimeout is how long an L2 operation waits for a slot under
+	// L2MaxConcurrency. Default 50ms.
+	L2AcquireTimeout time.Duration
+	// CloseLevels makes Close also close L1 and L2 once the background work has stopped:
+	// levels with Close(ctx) error, such as RedisCache, or Close() error, such as
+	// BigCache. Leave it off when the levels are shared with other caches.
+	CloseLevels bool
+
+	// Interception hooks for metrics, logging or tracing without wrapping the cache.
+	// Each is optional and runs synchronously on the calling goroutine, with the key as
+	// passed by the caller.
+	//
+	// BeforeGet runs before every Get and GetWithMetadata; the context it returns is
+	// used for the rest of the call. AfterGet reports the outcome: level is the level
+	// that served a hit, empty on a miss or when the Loader filled it, and the failing
+	// level for an error.
+	BeforeGet func(ctx context.Context, key string) context.Context
+	AfterGet  func(ctx context.Context, key string, found bool, level string, err error)
+	// BeforeSet runs before every Set, SetIfChanged and Loader write. AfterSet runs once
+	// per level written with that level's error, or once with the error and its level,
+	// if any, when the Set failed before writing.
+	BeforeSet func(ctx context.Context, key string, opts CacheOptions)
+	AfterSet  func(ctx context.Context, key, level string, err error)
+	// BeforeDelete runs before every Delete; AfterDelete once per level with that
+	// level's error.
+	BeforeDelete func(ctx context.Context, key string)
+	AfterDelete  func(ctx context.Context, key, level string, err error)
+	// ServeStaleOnError serves an expired L1 copy when a Get fails because the L2 read or
+	// the Loader returned an error, e.g. with Redis and the database both down. L1 must
+	// be a StaleReader keeping expired entries, like a BigCache with StaleGrace. Stale
+	// serves set EntryMetadata.Stale and CacheGetResult.Stale, are visible to AfterGet
+	// through ServedStale and are counted in Stats.
+	ServeStaleOnError bool
+	// PanicOnInternalError re-panics instead of returning an ErrPanic when a backend,
+	// serializer or hook panics inside Get, GetWithMetadata, Set or Delete, e.g. in tests
+	// that should crash on bugs.
+	PanicOnInternalError bool
+	// QuarantineUndecodable deletes an entry whose payload cannot be decoded, e.g. one
+	// written by an older struct version, from both levels and turns the Get into a miss
+	// (calling the Loader when set) instead of an error, so the next write heals it.
+	// Deletions are counted in Stats as PoisonedEntries.
+	QuarantineUndecodable bool
+	// QuarantineCooldown is how long a key is left alone after being quarantined, so a
+	// Loader that keeps writing undecodable payloads does not cause a delete loop; within
+	// it the decode error is returned as without QuarantineUndecodable. Default 1m.
+	QuarantineCooldown time.Duration
+	// ReadOnly makes Set and Delete skip both levels, e.g. for a read replica. Get still
+	// reads and warms L1 from L2 hits, since L1 is process-local. It cannot be combined
+	// with ModeL1Only, which would leave nothing to read.
+	ReadOnly bool
+	// ReadOnlyL2 makes Set and Delete skip L2 only, so a replica keeps writing its own L1
+	// but never touches the shared Redis or its TTLs. It requires L2.
+	ReadOnlyL2 bool
+	// ReadOnlySilent makes Set and Delete skip read-only levels without an error. By
+	// default a Set returns ErrReadOnly when every level it targets is read-only, and a
+	// Delete whenever it skipped one, like a Delete that skips a degraded L2.
+	ReadOnlySilent bool
 }
 
+// SkipReasonOversize is reported to OnSkip when a payload exceeds L1MaxValueBytes.
+const SkipReasonOversize = "l1_skipped_oversize"
+
 // MultiLevelCache composes an L1 and L2 cache with cache-aside semantics.
 type MultiLevelCache struct {
 	l1             RawCache
 	l2             RawCache
-	serializer     Serializer
+	l1Serializer   Serializer
+	l2Serializer   Serializer
+	splitFormats   bool // true when L1 and L2 use different serializers
+	validateWrites bool
 	mode           CacheMode
 	allowOverrides bool // true only when both L1 and L2 are configured
 	warmupTTL      time.Duration
 	l1DefaultTTL   time.Duration
 	l2DefaultTTL   time.Duration
+	flushLimiter   *rate.Limiter       // nil = unlimited
+	l2Reads        *singleflight.Group // nil unless CoalesceL2Reads
+	events         *eventStream
+
+	l1MaxValueBytes   int
+	onSkip            func(key, level, reason string)
+	l1SkippedOversize atomic.Uint64
+	l1Budget          *l1MemoryBudget // nil unless L1SoftMemoryBudget is set
+
+	namespace string
+	metrics   MetricsCollector
+
+	l2Compression L2CompressionConfig
+	changes       *changeTracker
+	latency       *LatencyTracker
+	keyLocks      KeyedMutex
+	audit         AuditLogger
+
+	l2ChunkThreshold int
+	ttlProvider      TTLProvider
+	loader           Loader
+	log              *sampledLogger
+	version          string
+	keyPrefix        string // "<instance>:<version>:", each part omitted when empty
+	clock            Clock
+	degradation      *l2Degradation // nil unless Degradation.After is set
+	l2Monitored      bool           // L2 is a ConnectionNotifier
+	l2Disconnected   atomic.Bool    // set while the L2 notifier reports a lost connection
+	warmup           warmupTracker
+	autoWarm         *autoWarm       // nil unless AutoWarmOnStart
+	warmups          *warmupBatcher  // nil unless WarmupBatch.Window is set
+	hitRates         *HitRateTracker // nil unless HitRates.Enabled
+	contentHashes    bool
+	closeLevels      bool
+	localTags        localTagIndex // SetWithTags members not kept in L2
+	l2RateLimiter    RateLimiter   // nil = L2 writes are not throttled
+	l2RateLimitID    string
+	l2Gate           *l2Gate // nil = L2 concurrency is not capped
+	hooks            cacheHooks
+	switches         levelSwitches
+	readOnly         readOnlyLevels
+	serveStaleOnErr  bool
+	staleServes      atomic.Int64
+	panicOnInternal  bool
+	quarantine       *entryQuarantine // nil unless QuarantineUndecodable is set
+	bus              *EventBus
+	busCancel        func() // ends the subscription of subscribeDeletes; nil without one
+
+	// background is the parent context of goroutines the cache starts itself; Close
+	// cancels it and waits for backgroundWork.
+	background     context.Context
+	stopBackground context.CancelFunc
+	backgroundWork sync.WaitGroup
+	closeOnce      sync.Once
+	closeLevelOnce sync.Once // guards CloseLevels, as BigCache.Close is not idempotent
+}
+
+// checkUnusedLevel applies validation to a level that is configured but unused by mode,
+// warning on logger (nil uses slog.Default()).
+func checkUnusedLevel(logger *slog.Logger, validation ModeValidation, mode, level string) error {
+	switch validation {
+	case ModeValidationSilent:
+		return nil
+	case ModeValidationError:
+		return &CacheError{Op: "new", Level: level, Cause: fmt.Errorf("%w: %s does not use the configured %s", ErrModeMismatch, mode, level)}
+	default:
+		cmp.Or(logger, slog.Default()).Warn("cache mode mismatch",
+			"mode", mode,
+			"l1_configured", true,
+			"l2_configured", true,
+			"message", level+" will be ignored by default")
+		return nil
+	}
 }
 
 // NewMultiLevelCache builds a MultiLevelCache with sensible defaults.
 func NewMultiLevelCache(l1 RawCache, l2 RawCache, serializer Serializer, cfg MultiLevelConfig) (*MultiLevelCache, error) {
-	if serializer == nil {
-		return nil, ErrSerializerMissing
+	l1Serializer, l2Serializer := serializer, serializer
+	if cfg.L1Serializer != nil {
+		l1Serializer = cfg.L1Serializer
+	}
+	if cfg.L2Serializer != nil {
+		l2Serializer = cfg.L2Serializer
+	}
+	if l1Serializer == nil || l2Serializer == nil {
+		return nil, &CacheError{Op: "new", Cause: ErrSerializerMissing}
 	}
 
 	// Validate mode against provided caches
@@ -56,46 +337,62 @@ func NewMultiLevelCache(l1 RawCache, l2 RawCache, serializer Serializer, cfg Mul
 	switch mode {
 	case ModeL1Only:
 		if l1 == nil {
-			return nil, errors.New("ModeL1Only requires L1 cache to be configured")
+			return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: ModeL1Only requires L1 cache to be configured", ErrModeMismatch)}
 		}
 		// Ensure mode matches configuration
 		if l2 != nil {
-			slog.Warn("cache mode mismatch",
-				"mode", "ModeL1Only",
-				"l1_configured", true,
-				"l2_configured", true,
-				"message", "L2 will be ignored by default")
+			if err := checkUnusedLevel(cfg.Logger, cfg.StrictModeValidation, "ModeL1Only", LevelL2); err != nil {
+				return nil, err
+			}
 		}
 	case ModeL2Only:
 		if l2 == nil {
-			return nil, errors.New("ModeL2Only requires L2 cache to be configured")
+			return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: ModeL2Only requires L2 cache to be configured", ErrModeMismatch)}
 		}
 		// Ensure mode matches configuration
 		if l1 != nil {
-			slog.Warn("cache mode mismatch",
-				"mode", "ModeL2Only",
-				"l1_configured", true,
-				"l2_configured", true,
-				"message", "L1 will be ignored by default")
+			if err := checkUnusedLevel(cfg.Logger, cfg.StrictModeValidation, "ModeL2Only", LevelL1); err != nil {
+				return nil, err
+			}
 		}
 	case ModeBothLevels:
 		if l1 == nil || l2 == nil {
-			return nil, errors.New("ModeBothLevels requires both L1 and L2 caches to be configured")
+			return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: ModeBothLevels requires both L1 and L2 caches to be configured", ErrModeMismatch)}
 		}
 	default:
 		// Default to ModeBothLevels if not specified
 		mode = ModeBothLevels
 		if l1 == nil || l2 == nil {
-			return nil, errors.New("ModeBothLevels (default) requires both L1 and L2 caches to be configured")
+			return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: ModeBothLevels (default) requires both L1 and L2 caches to be configured", ErrModeMismatch)}
 		}
 	}
 
 	// Strict validation: if only one level configured, verify mode matches exactly
 	if l1 != nil && l2 == nil && mode != ModeL1Only {
-		return nil, errors.New("only L1 configured but mode is not ModeL1Only; set mode to ModeL1Only or configure L2")
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: only L1 configured but mode is not ModeL1Only; set mode to ModeL1Only or configure L2", ErrModeMismatch)}
 	}
 	if l1 == nil && l2 != nil && mode != ModeL2Only {
-		return nil, errors.New("only L2 configured but mode is not ModeL2Only; set mode to ModeL2Only or configure L1")
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: only L2 configured but mode is not ModeL2Only; set mode to ModeL2Only or configure L1", ErrModeMismatch)}
+	}
+
+	if cfg.L1SoftMemoryBudget < 0 {
+		return nil, &CacheError{Op: "new", Level: LevelL1, Cause: errors.New("L1SoftMemoryBudget must not be negative")}
+	}
+	if cfg.L1SoftMemoryBudget > 0 && l1 == nil {
+		return nil, &CacheError{Op: "new", Level: LevelL1, Cause: fmt.Errorf("%w: L1SoftMemoryBudget requires L1", ErrLevelNotConfigured)}
+	}
+
+	if cfg.ServeStaleOnError {
+		if err := checkServeStale(l1); err != nil {
+			return nil, &CacheError{Op: "new", Level: LevelL1, Cause: err}
+		}
+	}
+
+	if cfg.ReadOnly && mode == ModeL1Only {
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: ReadOnly with ModeL1Only makes every operation a no-op", ErrModeMismatch)}
+	}
+	if cfg.ReadOnlyL2 && l2 == nil {
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: ReadOnlyL2 requires L2 cache to be configured", ErrModeMismatch)}
 	}
 
 	// Per-call overrides are only allowed when both levels are configured
@@ -116,28 +413,207 @@ func NewMultiLevelCache(l1 RawCache, l2 RawCache, serializer Serializer, cfg Mul
 		l2TTL = 5 * time.Minute
 	}
 
-	return &MultiLevelCache{
-		l1:             l1,
-		l2:             l2,
-		serializer:     serializer,
-		mode:           mode,
-		allowOverrides: allowOverrides,
-		warmupTTL:      warmTTL,
-		l1DefaultTTL:   l1TTL,
-		l2DefaultTTL:   l2TTL,
-	}, nil
+	if err := cfg.L2Compression.validate(); err != nil {
+		return nil, &CacheError{Op: "new", Level: LevelL2, Cause: err}
+	}
+
+	changeTrackingSize := cfg.ChangeTrackingMaxKeys
+	if changeTrackingSize <= 0 {
+		changeTrackingSize = 10000
+	}
+
+	clock := clockOrSystem(cfg.Clock)
+
+	if strings.Contains(cfg.InstanceName, keySeparator) {
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("instance name %q must not contain %q", cfg.InstanceName, keySeparator)}
+	}
+
+	logSampleRate := defaultLogSampleRate
+	if cfg.LogSampleRate != nil {
+		logSampleRate = *cfg.LogSampleRate
+	}
+
+	var flushLimiter *rate.Limiter
+	if cfg.MaxFlushPerMinute > 0 {
+		flushLimiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(cfg.MaxFlushPerMinute)), cfg.MaxFlushPerMinute)
+	}
+
+	var l2Reads *singleflight.Group
+	if cfg.CoalesceL2Reads {
+		l2Reads = &singleflight.Group{}
+	}
+
+	m := &MultiLevelCache{
+		l1:               l1,
+		l2:               l2,
+		l1Serializer:     l1Serializer,
+		l2Serializer:     l2Serializer,
+		splitFormats:     cfg.L1Serializer != nil || cfg.L2Serializer != nil,
+		validateWrites:   cfg.ValidateOnWrite,
+		mode:             mode,
+		allowOverrides:   allowOverrides,
+		warmupTTL:        warmTTL,
+		l1DefaultTTL:     l1TTL,
+		l2DefaultTTL:     l2TTL,
+		flushLimiter:     flushLimiter,
+		l2Reads:          l2Reads,
+		events:           newEventStream(eventBufferSize),
+		l1MaxValueBytes:  cfg.L1MaxValueBytes,
+		l1Budget:         newL1MemoryBudget(cfg.L1SoftMemoryBudget),
+		onSkip:           cfg.OnSkip,
+		namespace:        cfg.Namespace,
+		metrics:          cfg.Metrics,
+		l2Compression:    cfg.L2Compression,
+		changes:          newChangeTracker(changeTrackingSize, clock),
+		latency:          NewLatencyTracker(cfg.LatencySamples),
+		audit:            cfg.Audit,
+		l2ChunkThreshold: cfg.L2ChunkThreshold,
+		ttlProvider:      cfg.TTLProvider,
+		loader:           cfg.Loader,
+		log:              newSampledLogger(cfg.Logger, logSampleRate),
+		version:          cfg.Version,
+		keyPrefix:        storeKeyPrefix(cfg.InstanceName, cfg.Version),
+		clock:            clock,
+		degradation:      newL2Degradation(cfg.Degradation, l2, clock),
+		contentHashes:    cfg.ContentHashes,
+		closeLevels:      cfg.CloseLevels,
+		l2RateLimiter:    cfg.L2RateLimiter,
+		l2RateLimitID:    cmp.Or(cfg.L2RateLimitID, cfg.Namespace, cfg.InstanceName, defaultL2RateLimitID),
+		l2Gate:           newL2Gate(cfg.L2MaxConcurrency, cfg.L2AcquireTimeout),
+		hooks:            newCacheHooks(cfg),
+		serveStaleOnErr:  cfg.ServeStaleOnError,
+		panicOnInternal:  cfg.PanicOnInternalError,
+		bus:              cfg.EventBus,
+		quarantine:       newEntryQuarantine(cfg.QuarantineUndecodable, cfg.QuarantineCooldown, clock),
+		readOnly:         readOnlyLevels{l1: cfg.ReadOnly, l2: cfg.ReadOnly || cfg.ReadOnlyL2, silent: cfg.ReadOnlySilent},
+	}
+	if cfg.RequestID != nil {
+		m.log.requestID = cfg.RequestID
+	}
+
+	if notifier, ok := l1.(EvictionNotifier); ok && m.l1Budget != nil {
+		notifier.OnEviction(m.l1Budget.evicted)
+	}
+
+	m.background, m.stopBackground = context.WithCancel(context.Background())
+	if notifier, ok := l2.(ConnectionNotifier); ok {
+		m.l2Monitored = true
+		notifier.OnDisconnect(func(err error) {
+			m.l2Disconnected.Store(true)
+			m.log.logger.Warn("cache l2 disconnected, serving from l1 only", "error", err)
+		})
+		notifier.OnReconnect(func() {
+			m.l2Disconnected.Store(false)
+			m.log.logger.Info("cache l2 reconnected")
+		})
+	}
+	// Construction is logged once and unsampled; LogSampleRate only applies to operations.
+	m.log.logger.Debug("cache created", "cache", m.String())
+	if cfg.AutoWarmOnStart {
+		m.startAutoWarm(cfg)
+	}
+	if cfg.WarmupBatch.Window > 0 && l1 != nil && l2 != nil {
+		m.startWarmupBatcher(cfg.WarmupBatch)
+	}
+	if cfg.HitRates.Enabled {
+		m.hitRates = NewHitRateTracker(cfg.HitRates.Window, clock)
+		m.startHitRateReporter(cfg.HitRates)
+	}
+	m.subscribeDeletes()
+	return m, nil
+}
+
+// Mode returns the default caching strategy, after defaulting.
+func (m *MultiLevelCache) Mode() CacheMode { return m.mode }
+
+// WarmupTTL returns the TTL used when warming L1 from an L2 hit.
+func (m *MultiLevelCache) WarmupTTL() time.Duration { return m.warmupTTL }
+
+// L1DefaultTTL returns the L1 TTL used when CacheOptions do not set one.
+func (m *MultiLevelCache) L1DefaultTTL() time.Duration { return m.l1DefaultTTL }
+
+// L2DefaultTTL returns the L2 TTL used when CacheOptions do not set one.
+func (m *MultiLevelCache) L2DefaultTTL() time.Duration { return m.l2DefaultTTL }
+
+// IsAllowOverrides reports whether CacheOptions.TargetL1/TargetL2 are accepted, which
+// requires both levels.
+func (m *MultiLevelCache) IsAllowOverrides() bool { return m.allowOverrides }
+
+// HasL1 reports whether an L1 cache is configured.
+func (m *MultiLevelCache) HasL1() bool { return m.l1 != nil }
+
+// HasL2 reports whether an L2 cache is configured.
+func (m *MultiLevelCache) HasL2() bool { return m.l2 != nil }
+
+// String summarises the configuration, e.g.
+// "MultiLevelCache{mode=both-levels, l1_ttl=40s, l2_ttl=2m0s}".
+func (m *MultiLevelCache) String() string {
+	return fmt.Sprintf("MultiLevelCache{mode=%s, l1_ttl=%s, l2_ttl=%s}", m.mode, m.l1DefaultTTL, m.l2DefaultTTL)
 }
 
 // Get implements Cache.Get with cache-aside semantics and mode-aware warmup.
 // It checks endpoint-level options first (via opts), then falls back to service-level mode.
-func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any, opts CacheOptions) (bool, error) {
+func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any, opts CacheOptions) (res CacheGetResult, err error) {
+	res.Level = CacheLevelNone
 	if m == nil {
-		return false, errors.New("cache not initialized")
+		return res, &CacheError{Op: "get", Key: key, Cause: ErrNotInitialized}
+	}
+	defer m.recoverPanic("get", key, &err)
+	meta, found, err := m.get(ctx, key, dest, opts)
+	res = CacheGetResult{Found: found, Level: CacheLevelNone, Stale: meta.Stale}
+	if found && meta.Level != "" {
+		res.Level = CacheLevelHit(meta.Level)
+	}
+	return res, err
+}
+
+// get implements Get and GetWithMetadata; a nil dest skips decoding and loading.
+func (m *MultiLevelCache) get(ctx context.Context, key string, dest any, opts CacheOptions) (EntryMetadata, bool, error) {
+	key, opts = applyHints(ctx, key, opts)
+	if m.hooks.beforeGet != nil {
+		ctx = m.hooks.beforeGet(ctx, key)
+	}
+	meta, found, err := m.lookup(ctx, key, dest, opts)
+	if err != nil && m.serveStaleOnErr && backendFailure(err) {
+		if stale, ok := m.serveStale(ctx, m.storeKey(key), dest, err); ok {
+			meta, found, err = stale, true, nil
+			ctx = context.WithValue(ctx, servedStaleKey{}, true)
+		}
+	}
+	if m.hitRates != nil && err == nil {
+		m.hitRates.Record(keyPrefix(key), found)
+	}
+	if m.hooks.afterGet != nil {
+		level := meta.Level
+		if err != nil {
+			level = errorLevel(err)
+		}
+		m.hooks.afterGet(ctx, key, found, level, err)
+	}
+	return meta, found, err
+}
+
+// lookup reads key from the levels selected by mode and opts, warming L1 from an L2 hit
+// and filling a miss with the Loader.
+func (m *MultiLevelCache) lookup(ctx context.Context, key string, dest any, opts CacheOptions) (EntryMetadata, bool, error) {
+	defer m.observeLatency("get", time.Now())
+	key = m.storeKey(key)
+	tr := traceFrom(ctx)
+
+	// A context bypass wins over options and mode
+	bypass := bypassFrom(ctx)
+	if bypass&BypassRead != 0 {
+		m.log.Debug(ctx, "cache get read bypassed", "key", key)
+		if m.loader != nil && dest != nil {
+			found, err := m.load(ctx, key, dest, opts)
+			return EntryMetadata{}, found, err
+		}
+		return EntryMetadata{}, false, nil
 	}
 
 	// Check if user is trying to override levels when not allowed
 	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
-		return false, errors.New("level overrides not allowed: both L1 and L2 must be configured to use TargetL1/TargetL2 options")
+		return EntryMetadata{}, false, &CacheError{Op: "get", Key: key, Cause: ErrLevelOverrideNotAllowed}
 	}
 
 	// Determine which levels to check based on mode (service-level default)
@@ -149,58 +625,112 @@ func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any, opts Ca
 
 	// Validate that at least one level is targeted
 	if !checkL1 && !checkL2 {
-		return false, errors.New("Get operation requires at least one cache level to be checked")
+		return EntryMetadata{}, false, &CacheError{Op: "get", Key: key, Cause: ErrNoLevelTargeted}
 	}
 
 	// Validate that targeted levels are configured
 	if checkL1 && m.l1 == nil {
-		return false, errors.New("L1 target requested but L1 cache not configured")
+		return EntryMetadata{}, false, &CacheError{Op: "get", Level: LevelL1, Key: key, Cause: ErrLevelNotConfigured}
 	}
 	if checkL2 && m.l2 == nil {
-		return false, errors.New("L2 target requested but L2 cache not configured")
+		return EntryMetadata{}, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: ErrLevelNotConfigured}
+	}
+
+	// Levels switched off with SetLevelEnabled are skipped; with none left it is a miss
+	checkL1 = checkL1 && !m.switches.l1Off.Load()
+	checkL2 = checkL2 && !m.switches.l2Off.Load()
+
+	// While L2 is degraded or disconnected, serve from L1 alone instead of waiting on L2
+	if checkL2 && !m.l2Available() {
+		if !checkL1 {
+			return EntryMetadata{}, false, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: ErrL2Degraded}
+		}
+		m.log.Debug(ctx, "cache get skipping degraded l2", "key", key)
+		checkL2 = false
 	}
 
 	// Check L1 if mode/options allow it
 	if checkL1 && m.l1 != nil {
-		fmt.Printf("🔍 [GET] Checking L1 cache for key: %s\n", key)
+		m.log.Debug(ctx, "cache get checking l1", "key", key)
+		began := tr.start()
 		if data, ok, err := m.l1.Get(ctx, key); err != nil {
-			fmt.Printf("❌ [GET] L1 error for key %s: %v\n", key, err)
-			return false, err
+			m.trace(tr, TraceEvent{Op: "get", Step: TraceLookup, Key: key, Level: LevelL1, Result: EventError}, began, err)
+			m.log.Debug(ctx, "cache get l1 error", "key", key, "error", err)
+			m.emit(ctx, "get", key, LevelL1, EventError)
+			return EntryMetadata{}, false, wrapError("get", LevelL1, key, err)
 		} else if ok {
-			fmt.Printf("✅ [GET] L1 HIT! Key: %s | Data size: %d bytes | Preview: %s\n", key, len(data), previewData(data))
-			if err := m.serializer.Unmarshal(data, dest); err != nil {
-				fmt.Printf("❌ [GET] L1 unmarshal error for key %s: %v\n", key, err)
-				return false, err
+			m.trace(tr, TraceEvent{Op: "get", Step: TraceLookup, Key: key, Level: LevelL1, Result: EventHit}, began, nil)
+			m.log.Debug(ctx, "cache get l1 hit", "key", key, "size", len(data), "preview", previewData(data))
+			hash, payload := splitContentHash(data)
+			began = tr.start()
+			err := m.decode(m.l1Serializer, payload, dest)
+			m.trace(tr, TraceEvent{Op: "get", Step: TraceDecode, Key: key, Level: LevelL1}, began, err)
+			if err != nil {
+				m.log.Debug(ctx, "cache get l1 unmarshal error", "key", key, "error", err)
+				m.emit(ctx, "get", key, LevelL1, EventError)
+				if m.quarantineEntry(ctx, key, LevelL1, err) {
+					return m.quarantinedMiss(ctx, key, dest, opts)
+				}
+				return EntryMetadata{}, false, wrapError("get", LevelL1, key, err)
 			}
-			fmt.Printf("✨ [GET] Successfully returned value from L1\n")
-			return true, nil
+			m.emit(ctx, "get", key, LevelL1, EventHit)
+			return EntryMetadata{ETag: etagOf(hash), Level: LevelL1}, true, nil
 		} else {
-			fmt.Printf("❌ [GET] L1 MISS for key: %s\n", key)
+			m.trace(tr, TraceEvent{Op: "get", Step: TraceLookup, Key: key, Level: LevelL1, Result: EventMiss}, began, nil)
+			m.log.Debug(ctx, "cache get l1 miss", "key", key)
 		}
 	}
 
 	// Check L2 if mode/options allow it
 	if !checkL2 || m.l2 == nil {
-		fmt.Printf("❌ [GET] OVERALL MISS for key: %s (L2 not checked)\n", key)
-		return false, nil
+		m.log.Debug(ctx, "cache get miss, l2 not checked", "key", key)
+		m.emit(ctx, "get", key, "", EventMiss)
+		if m.loader != nil && dest != nil {
+			found, err := m.load(ctx, key, dest, opts)
+			return EntryMetadata{}, found, err
+		}
+		return EntryMetadata{}, false, nil
 	}
 
-	fmt.Printf("🔍 [GET] Checking L2 cache for key: %s\n", key)
-	data, ok, err := m.l2.Get(ctx, key)
+	m.log.Debug(ctx, "cache get checking l2", "key", key)
+	began := tr.start()
+	data, ok, err := m.getL2(ctx, key)
+	m.degradation.record(err)
+	if errors.Is(err, ErrL2Throttled) {
+		// Fail open: a read that could not get an L2 slot is a miss.
+		m.log.Debug(ctx, "cache get l2 throttled, treating as miss", "key", key)
+		err = nil
+	}
 	if err != nil {
-		fmt.Printf("❌ [GET] L2 error for key %s: %v\n", key, err)
-		return false, err
+		m.trace(tr, TraceEvent{Op: "get", Step: TraceLookup, Key: key, Level: LevelL2, Result: EventError}, began, err)
+		m.log.Debug(ctx, "cache get l2 error", "key", key, "error", err)
+		m.emit(ctx, "get", key, LevelL2, EventError)
+		return EntryMetadata{}, false, wrapError("get", LevelL2, key, err)
 	}
 	if !ok {
-		fmt.Printf("❌ [GET] L2 MISS for key: %s\n", key)
-		fmt.Printf("❌ [GET] OVERALL MISS - key not found in any cache level\n")
-		return false, nil
+		m.trace(tr, TraceEvent{Op: "get", Step: TraceLookup, Key: key, Level: LevelL2, Result: EventMiss}, began, nil)
+		m.log.Debug(ctx, "cache get miss in all levels", "key", key)
+		m.emit(ctx, "get", key, "", EventMiss)
+		if m.loader != nil && dest != nil {
+			found, err := m.load(ctx, key, dest, opts)
+			return EntryMetadata{}, found, err
+		}
+		return EntryMetadata{}, false, nil
 	}
 
-	fmt.Printf("✅ [GET] L2 HIT! Key: %s | Data size: %d bytes | Preview: %s\n", key, len(data), previewData(data))
-	if err := m.serializer.Unmarshal(data, dest); err != nil {
-		fmt.Printf("❌ [GET] L2 unmarshal error for key %s: %v\n", key, err)
-		return false, err
+	m.trace(tr, TraceEvent{Op: "get", Step: TraceLookup, Key: key, Level: LevelL2, Result: EventHit}, began, nil)
+	m.log.Debug(ctx, "cache get l2 hit", "key", key, "size", len(data), "preview", previewData(data))
+	hash, payload := splitContentHash(data)
+	began = tr.start()
+	err = m.decode(m.l2Serializer, payload, dest)
+	m.trace(tr, TraceEvent{Op: "get", Step: TraceDecode, Key: key, Level: LevelL2}, began, err)
+	if err != nil {
+		m.log.Debug(ctx, "cache get l2 unmarshal error", "key", key, "error", err)
+		m.emit(ctx, "get", key, LevelL2, EventError)
+		if m.quarantineEntry(ctx, key, LevelL2, err) {
+			return m.quarantinedMiss(ctx, key, dest, opts)
+		}
+		return EntryMetadata{}, false, wrapError("get", LevelL2, key, err)
 	}
 
 	// Only warm L1 if:
@@ -208,18 +738,110 @@ func (m *MultiLevelCache) Get(ctx context.Context, key string, dest any, opts Ca
 	// 2. L1 is configured
 	// 3. Mode is ModeBothLevels and no explicit L1 override was provided
 	//    (we don't warm L1 if user explicitly chose to skip it)
-	if checkL1 && m.l1 != nil && m.mode == ModeBothLevels && opts.TargetL1 == nil {
-		fmt.Printf("🔥 [GET] Warming L1 from L2 hit | Key: %s | TTL: %v | Data size: %d bytes\n", key, m.warmupTTL, len(data))
+	// 4. The context does not bypass writes
+	if checkL1 && m.l1 != nil && m.mode == ModeBothLevels && opts.TargetL1 == nil && bypass&BypassWrite == 0 {
+		began = tr.start()
+		warmup := TraceEvent{Op: "get", Step: TraceWarmup, Key: key, Level: LevelL1, TTL: m.warmupTTL, Result: EventError}
+		warmData, err := m.l1WarmupData(data, dest)
 		// best-effort warmup; ignore errors to avoid failing the request.
-		if err := m.l1.Set(ctx, key, data, m.warmupTTL); err != nil {
-			fmt.Printf("⚠️  [GET] L1 warmup failed (continuing): %v\n", err)
+		if err != nil {
+			m.log.Debug(ctx, "cache get l1 warmup re-encode failed, continuing", "key", key, "error", err)
+		} else if m.skipL1Oversize(key, len(warmData), opts) {
+			warmup.Result = TraceSkipped
+			m.log.Debug(ctx, "cache get l1 warmup skipped, payload too large", "key", key, "size", len(warmData))
+		} else if m.skipL1Budget(ctx, key, len(warmData)) {
+			warmup.Result = TraceSkipped
+			m.log.Debug(ctx, "cache get l1 warmup skipped, memory budget exceeded", "key", key, "size", len(warmData))
+		} else if m.warmups != nil {
+			if m.queueWarmup(key, warmData) {
+				warmup.Result = TraceQueued
+				m.log.Debug(ctx, "cache get l1 warmup queued", "key", key)
+			} else {
+				warmup.Result = TraceSkipped
+				m.log.Debug(ctx, "cache get l1 warmup skipped, batch queue full", "key", key)
+			}
+		} else if err = m.l1.Set(ctx, key, warmData, m.warmupTTL); err != nil {
+			m.log.Debug(ctx, "cache get l1 warmup failed, continuing", "key", key, "error", err)
 		} else {
-			fmt.Printf("✨ [GET] L1 warmup successful!\n")
+			m.l1Budget.record(key, len(warmData))
+			warmup.Result = EventOK
+			m.log.Debug(ctx, "cache get warmed l1 from l2 hit", "key", key, "ttl", m.warmupTTL, "size", len(warmData))
 		}
+		m.trace(tr, warmup, began, err)
 	}
 
-	fmt.Printf("✨ [GET] Successfully returned value from L2\n")
-	return true, nil
+	m.emit(ctx, "get", key, LevelL2, EventHit)
+	return EntryMetadata{ETag: etagOf(hash), Level: LevelL2}, true, nil
+}
+
+// l2Result carries a coalesced L2 read between singleflight callers.
+type l2Result struct {
+	data []byte
+	ok   bool
+}
+
+// getL2 reads key from L2, sharing the round trip with concurrent callers when
+// CoalesceL2Reads is enabled. The returned buffer may be shared and must not be mutated.
+// A coalesced read runs with the context of the caller that started it.
+func (m *MultiLevelCache) getL2(ctx context.Context, key string) ([]byte, bool, error) {
+	if m.l2Reads == nil {
+		return m.readL2(ctx, key)
+	}
+
+	v, err, shared := m.l2Reads.Do(key, func() (any, error) {
+		data, ok, err := m.readL2(ctx, key)
+		return l2Result{data: data, ok: ok}, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if shared {
+		m.log.Debug(ctx, "cache get l2 read coalesced", "key", key)
+	}
+	res := v.(l2Result)
+	return res.data, res.ok, nil
+}
+
+// readL2 reads key from L2, reassembles chunked values and strips any compression framing.
+func (m *MultiLevelCache) readL2(ctx context.Context, key string) ([]byte, bool, error) {
+	if !m.l2Gate.acquire(ctx) {
+		return nil, false, ErrL2Throttled
+	}
+	defer m.l2Gate.release()
+
+	data, ok, err := m.l2.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	if data, ok, err = m.resolveChunks(ctx, key, data); err != nil || !ok {
+		return nil, false, err
+	}
+	data, err = decompressL2(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// setL2 compresses data according to L2Compression and writes it to L2, keeping the
+// current expiry when requested and L2 supports it.
+func (m *MultiLevelCache) setL2(ctx context.Context, key string, data []byte, ttl time.Duration, opts CacheOptions) error {
+	data, err := m.l2Compression.compress(data)
+	if err != nil {
+		return err
+	}
+	if !m.l2Gate.acquire(ctx) {
+		return ErrL2Throttled
+	}
+	defer m.l2Gate.release()
+
+	if m.l2ChunkThreshold > 0 && len(data) > m.l2ChunkThreshold {
+		return m.writeChunked(ctx, key, data, ttl)
+	}
+	if kts, ok := m.l2.(KeepTTLSetter); ok && opts.PreserveTTL {
+		return kts.SetKeepTTL(ctx, key, data, ttl)
+	}
+	return m.l2.Set(ctx, key, data, ttl)
 }
 
 func (m *MultiLevelCache) applyEndpointLevelOverrides(opts CacheOptions, checkL1 bool, checkL2 bool) (bool, bool) {
@@ -250,25 +872,37 @@ func (m *MultiLevelCache) determineCacheLevel() (bool, bool) {
 
 // Set serializes value and persists to cache levels based on mode and options.
 // It checks endpoint-level options first (via opts), then falls back to service-level mode.
-func (m *MultiLevelCache) Set(ctx context.Context, key string, value any, opts CacheOptions) error {
+func (m *MultiLevelCache) Set(ctx context.Context, key string, value any, opts CacheOptions) (err error) {
 	if m == nil {
-		return errors.New("cache not initialized")
+		return &CacheError{Op: "set", Key: key, Cause: ErrNotInitialized}
 	}
+	defer m.recoverPanic("set", key, &err)
+	key, opts = applyHints(ctx, key, opts)
+	_, err = m.set(ctx, m.storeKey(key), value, opts, false)
+	return err
+}
 
-	// Check if user is trying to override levels when not allowed
-	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
-		return errors.New("level overrides not allowed: both L1 and L2 must be configured to use TargetL1/TargetL2 options")
-	}
+// set implements Set and SetIfChanged. With ifChanged, the backend writes are skipped when
+// the serialized payload matches the last one written for key; the result reports whether
+// a write happened.
+func (m *MultiLevelCache) set(ctx context.Context, key string, value any, opts CacheOptions, ifChanged bool) (bool, error) {
+	defer m.observeLatency("set", time.Now())
+	tr := traceFrom(ctx)
 
-	data, err := m.serializer.Marshal(value)
-	if err != nil {
-		fmt.Printf("❌ [SET] Marshal error for key %s: %v\n", key, err)
-		return err
+	if m.hooks.beforeSet != nil {
+		m.hooks.beforeSet(ctx, m.logicalKey(key), opts)
+	}
+	if bypassFrom(ctx)&BypassWrite != 0 {
+		m.log.Debug(ctx, "cache set write bypassed", "key", key)
+		return false, nil
 	}
 
-	fmt.Printf("📦 [SET] Serialized value | Key: %s | Data size: %d bytes | Preview: %s\n", key, len(data), previewData(data))
+	// Check if user is trying to override levels when not allowed
+	if !m.allowOverrides && (opts.TargetL1 != nil || opts.TargetL2 != nil) {
+		return false, m.failSet(ctx, key, &CacheError{Op: "set", Key: key, Cause: ErrLevelOverrideNotAllowed})
+	}
 
-	l1TTL, l2TTL := opts.normalize(m.l1DefaultTTL, m.l2DefaultTTL)
+	l1TTL, l2TTL := m.ttlsFor(key, opts)
 
 	// Determine target levels based on mode
 	var targetL1, targetL2 bool
@@ -279,91 +913,318 @@ func (m *MultiLevelCache) Set(ctx context.Context, key string, value any, opts C
 
 	// Validate that at least one level is targeted
 	if !targetL1 && !targetL2 {
-		return errors.New("Set operation requires at least one cache level to be targeted")
+		return false, m.failSet(ctx, key, &CacheError{Op: "set", Key: key, Cause: ErrNoLevelTargeted})
 	}
 
 	// Validate that targeted levels are configured
 	if targetL1 && m.l1 == nil {
-		return errors.New("L1 target requested but L1 cache not configured")
+		return false, m.failSet(ctx, key, &CacheError{Op: "set", Level: LevelL1, Key: key, Cause: ErrLevelNotConfigured})
 	}
 	if targetL2 && m.l2 == nil {
-		return errors.New("L2 target requested but L2 cache not configured")
+		return false, m.failSet(ctx, key, &CacheError{Op: "set", Level: LevelL2, Key: key, Cause: ErrLevelNotConfigured})
+	}
+
+	// Levels switched off with SetLevelEnabled are skipped
+	targetL1 = targetL1 && !m.switches.l1Off.Load()
+	targetL2 = targetL2 && !m.switches.l2Off.Load()
+	if !targetL1 && !targetL2 {
+		m.log.Debug(ctx, "cache set skipped, targeted levels disabled", "key", key)
+		return false, nil
+	}
+
+	// Read-only levels are skipped; a Set left with nothing to write is a no-op
+	targetL1, targetL2, roErr := m.readOnly.filter("set", key, targetL1, targetL2)
+	if !targetL1 && !targetL2 {
+		m.log.Debug(ctx, "cache set skipped, targeted levels read-only", "key", key)
+		if roErr != nil {
+			return false, m.failSet(ctx, key, roErr)
+		}
+		return false, nil
+	}
+
+	began := tr.start()
+	l1Data, l2Data, err := m.marshalForLevels(value, targetL1, targetL2)
+	m.trace(tr, TraceEvent{Op: "set", Step: TraceEncode, Key: key}, began, err)
+	if err != nil {
+		m.log.Debug(ctx, "cache set marshal error", "key", key, "error", err)
+		return false, m.failSet(ctx, key, wrapError("set", "", key, err))
+	}
+
+	var sum payloadSum
+	if ifChanged || m.contentHashes {
+		sum = payloadHash(l1Data, l2Data)
+	}
+	if ifChanged {
+		if m.changes.unchanged(key, sum) {
+			if !opts.RefreshTTL {
+				m.log.Debug(ctx, "cache set value unchanged, skipping write", "key", key)
+				return false, nil
+			}
+			if m.refreshTTL(ctx, key, targetL1, targetL2, l1TTL, l2TTL) {
+				m.log.Debug(ctx, "cache set value unchanged, refreshed ttl", "key", key)
+				m.changes.remember(key, sum, shortestTTL(targetL1, targetL2, l1TTL, l2TTL))
+				return false, nil
+			}
+			// A level no longer holds the key, so fall through and rewrite it.
+		}
+	}
+	m.changes.forget(key)
+
+	if m.contentHashes {
+		if targetL1 {
+			l1Data = withContentHash(sum, l1Data)
+		}
+		if targetL2 {
+			l2Data = withContentHash(sum, l2Data)
+		}
 	}
 
 	// Write to targeted levels with best-effort semantics
 	// Attempt both writes regardless of individual failures to maximize cache availability
 	var l1Err, l2Err error
 
-	if targetL1 {
-		fmt.Printf("💾 [SET] Writing to L1 | Key: %s | TTL: %v | Size: %d bytes\n", key, l1TTL, len(data))
-		if err := m.l1.Set(ctx, key, data, l1TTL); err != nil {
-			l1Err = err
-			fmt.Printf("❌ [SET] L1 write FAILED | Key: %s | Error: %v\n", key, err)
+	if targetL1 && m.skipL1Oversize(key, len(l1Data), opts) {
+		m.trace(tr, TraceEvent{Op: "set", Step: TraceWrite, Key: key, Level: LevelL1, Result: TraceSkipped}, time.Time{}, nil)
+		m.log.Debug(ctx, "cache set skipping l1, payload too large", "key", key, "size", len(l1Data))
+	} else if targetL1 && m.skipL1Budget(ctx, key, len(l1Data)) {
+		m.trace(tr, TraceEvent{Op: "set", Step: TraceWrite, Key: key, Level: LevelL1, Result: TraceSkipped}, time.Time{}, nil)
+		m.log.Debug(ctx, "cache set skipping l1, memory budget exceeded", "key", key, "size", len(l1Data))
+	} else if targetL1 {
+		began := tr.start()
+		err := m.setL1(ctx, key, l1Data, l1TTL, opts)
+		m.trace(tr, TraceEvent{Op: "set", Step: TraceWrite, Key: key, Level: LevelL1, TTL: l1TTL, Result: eventResult(err)}, began, err)
+		if err != nil {
+			l1Err = wrapError("set", LevelL1, key, err)
+			m.log.Debug(ctx, "cache set l1 write failed", "key", key, "error", err)
+			m.emit(ctx, "set", key, LevelL1, EventError)
 		} else {
-			fmt.Printf("✅ [SET] L1 write SUCCESS | Key: %s\n", key)
+			m.l1Budget.record(key, len(l1Data))
+			m.log.Debug(ctx, "cache set l1 write", "key", key, "ttl", l1TTL, "size", len(l1Data))
+			m.emit(ctx, "set", key, LevelL1, EventOK)
 		}
+		m.afterSet(ctx, key, LevelL1, l1Err)
 	}
 
-	if targetL2 {
-		fmt.Printf("💾 [SET] Writing to L2 | Key: %s | TTL: %v | Size: %d bytes\n", key, l2TTL, len(data))
-		if err := m.l2.Set(ctx, key, data, l2TTL); err != nil {
-			l2Err = err
-			fmt.Printf("❌ [SET] L2 write FAILED | Key: %s | Error: %v\n", key, err)
+	if targetL2 && !m.l2Available() {
+		l2Err = &CacheError{Op: "set", Level: LevelL2, Key: key, Cause: ErrL2Degraded}
+		m.trace(tr, TraceEvent{Op: "set", Step: TraceWrite, Key: key, Level: LevelL2, Result: TraceSkipped}, time.Time{}, ErrL2Degraded)
+		m.log.Debug(ctx, "cache set skipping degraded l2", "key", key)
+	} else if targetL2 && !m.allowL2Write(ctx, key) {
+		l2Err = &CacheError{Op: "set", Level: LevelL2, Key: key, Cause: ErrL2RateLimited}
+		m.trace(tr, TraceEvent{Op: "set", Step: TraceWrite, Key: key, Level: LevelL2, Result: TraceSkipped}, time.Time{}, ErrL2RateLimited)
+		m.log.Log(ctx, slog.LevelWarn, "cache set l2 write rate limited, skipping", "key", key, "limiter", m.l2RateLimitID)
+	} else if targetL2 {
+		began := tr.start()
+		err := m.setL2(ctx, key, l2Data, l2TTL, opts)
+		m.trace(tr, TraceEvent{Op: "set", Step: TraceWrite, Key: key, Level: LevelL2, TTL: l2TTL, Result: eventResult(err)}, began, err)
+		m.degradation.record(err)
+		if err != nil {
+			l2Err = wrapError("set", LevelL2, key, err)
+			m.log.Debug(ctx, "cache set l2 write failed", "key", key, "error", err)
+			m.emit(ctx, "set", key, LevelL2, EventError)
 		} else {
-			fmt.Printf("✅ [SET] L2 write SUCCESS | Key: %s\n", key)
+			m.log.Debug(ctx, "cache set l2 write", "key", key, "ttl", l2TTL, "size", len(l2Data))
+			m.emit(ctx, "set", key, LevelL2, EventOK)
 		}
 	}
+	if targetL2 {
+		m.afterSet(ctx, key, LevelL2, l2Err)
+	}
+
+	if ifChanged && l1Err == nil && l2Err == nil {
+		m.changes.remember(key, sum, shortestTTL(targetL1, targetL2, l1TTL, l2TTL))
+	}
 
 	// Only return error if all targeted levels failed
 	if targetL1 && targetL2 {
 		if l1Err != nil && l2Err != nil {
-			return fmt.Errorf("both cache levels failed: L1=%w, L2=%v", l1Err, l2Err)
+			return false, &CacheError{Op: "set", Key: key, Cause: errors.Join(l1Err, l2Err)}
 		}
-		return nil
+		return true, nil
 	}
 
 	// For single-level operations, return the error
 	if l1Err != nil {
-		return l1Err
+		return false, l1Err
 	}
 	if l2Err != nil {
-		return l2Err
+		return false, l2Err
+	}
+
+	return true, nil
+}
+
+// marshalForLevels encodes value once per distinct serializer among the targeted levels.
+func (m *MultiLevelCache) marshalForLevels(value any, targetL1, targetL2 bool) ([]byte, []byte, error) {
+	var l1Data, l2Data []byte
+	var err error
+
+	if targetL2 || !m.splitFormats {
+		if l2Data, err = m.marshal(m.l2Serializer, value); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !m.splitFormats {
+		return l2Data, l2Data, nil
+	}
+	if targetL1 {
+		if l1Data, err = m.marshal(m.l1Serializer, value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return l1Data, l2Data, nil
+}
+
+// marshal encodes value with s and, with ValidateOnWrite, checks that the bytes decode
+// back into a value of the same type.
+func (m *MultiLevelCache) marshal(s Serializer, value any) ([]byte, error) {
+	data, err := s.Marshal(value)
+	if err != nil || !m.validateWrites {
+		return data, err
+	}
+	if _, isJSON := s.(JSONSerializer); isJSON && !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: JSON payload is not valid UTF-8", ErrSerialization)
+	}
+	if value == nil {
+		return data, nil
+	}
+	probe := reflect.New(reflect.TypeOf(value))
+	if err := s.Unmarshal(data, probe.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSerialization, err)
+	}
+	return data, nil
+}
+
+// l1WarmupData returns the bytes to store in L1 after an L2 hit. With a shared serializer the
+// L2 bytes are reused; otherwise the decoded dest is re-encoded in the L1 format.
+func (m *MultiLevelCache) l1WarmupData(l2Data []byte, dest any) ([]byte, error) {
+	if !m.splitFormats {
+		return l2Data, nil
 	}
+	if _, isRaw := dest.(*rawPayload); dest == nil || isRaw {
+		return nil, errors.New("no decoded value to re-encode")
+	}
+	data, err := m.l1Serializer.Marshal(dest)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the hash Set computed so the entry's ETag is the same in both levels
+	if hash, _ := splitContentHash(l2Data); hash != nil {
+		var sum payloadSum
+		copy(sum[:], hash)
+		data = withContentHash(sum, data)
+	}
+	return data, nil
+}
 
-	return nil
+// decode unmarshals payload into dest; a nil dest (GetWithMetadata) skips decoding and a
+// *rawPayload (GetRaw) receives the payload as is.
+func (m *MultiLevelCache) decode(serializer Serializer, payload []byte, dest any) error {
+	if dest == nil || decodeRaw(payload, dest) {
+		return nil
+	}
+	return serializer.Unmarshal(payload, dest)
+}
+
+// skipL1Oversize reports whether a payload of size bytes is over the L1 limit, counting
+// and reporting the skip when it is.
+func (m *MultiLevelCache) skipL1Oversize(key string, size int, opts CacheOptions) bool {
+	limit := m.l1MaxValueBytes
+	if opts.L1MaxValueBytes != 0 {
+		limit = opts.L1MaxValueBytes
+	}
+	if limit <= 0 || size <= limit {
+		return false
+	}
+	m.l1SkippedOversize.Add(1)
+	if m.onSkip != nil {
+		m.onSkip(key, LevelL1, SkipReasonOversize)
+	}
+	return true
+}
+
+// L1SkippedOversize reports how many L1 writes were skipped because the payload
+// exceeded the L1MaxValueBytes threshold.
+func (m *MultiLevelCache) L1SkippedOversize() uint64 {
+	return m.l1SkippedOversize.Load()
+}
+
+// setL1 writes to L1, passing the priority through and keeping the current expiry when
+// requested and L1 supports it.
+func (m *MultiLevelCache) setL1(ctx context.Context, key string, data []byte, ttl time.Duration, opts CacheOptions) error {
+	if kts, ok := m.l1.(KeepTTLSetter); ok && opts.PreserveTTL {
+		return kts.SetKeepTTL(ctx, key, data, ttl)
+	}
+	if ps, ok := m.l1.(PrioritySetter); ok && opts.Priority != 0 {
+		return ps.SetWithPriority(ctx, key, data, ttl, opts.Priority)
+	}
+	return m.l1.Set(ctx, key, data, ttl)
 }
 
 // Delete removes the key from both levels.
-func (m *MultiLevelCache) Delete(ctx context.Context, key string) error {
+func (m *MultiLevelCache) Delete(ctx context.Context, key string) (err error) {
 	if m == nil {
-		return errors.New("cache not initialized")
+		return &CacheError{Op: "delete", Key: key, Cause: ErrNotInitialized}
+	}
+	defer m.recoverPanic("delete", key, &err)
+	defer m.observeLatency("delete", time.Now())
+	key, _ = applyHints(ctx, key, CacheOptions{})
+	if m.hooks.beforeDelete != nil {
+		m.hooks.beforeDelete(ctx, key)
 	}
+	key = m.storeKey(key)
+	m.changes.forget(key)
 
-	fmt.Printf("🗑️  [DELETE] Deleting key: %s\n", key)
 	var firstErr error
+	deleteL1 := m.l1 != nil && !m.switches.l1Off.Load()
+	deleteL2 := m.l2 != nil && !m.switches.l2Off.Load()
+	deleteL1, deleteL2, roErr := m.readOnly.filter("delete", key, deleteL1, deleteL2)
+	if roErr != nil {
+		m.log.Debug(ctx, "cache delete skipping read-only levels", "key", key)
+	}
 
-	if m.l1 != nil {
-		fmt.Printf("🗑️  [DELETE] Deleting from L1 | Key: %s\n", key)
+	if deleteL1 {
 		if err := m.l1.Delete(ctx, key); err != nil {
-			firstErr = err
-			fmt.Printf("❌ [DELETE] L1 delete FAILED | Key: %s | Error: %v\n", key, err)
+			firstErr = wrapError("delete", LevelL1, key, err)
+			m.log.Debug(ctx, "cache delete l1 failed", "key", key, "error", err)
+			m.emit(ctx, "delete", key, LevelL1, EventError)
 		} else {
-			fmt.Printf("✅ [DELETE] L1 delete SUCCESS | Key: %s\n", key)
+			m.l1Budget.forget(key)
+			m.log.Debug(ctx, "cache delete l1", "key", key)
+			m.emit(ctx, "delete", key, LevelL1, EventOK)
 		}
+		m.afterDelete(ctx, key, LevelL1, firstErr)
 	}
 
-	if m.l2 != nil {
-		fmt.Printf("🗑️  [DELETE] Deleting from L2 | Key: %s\n", key)
-		if err := m.l2.Delete(ctx, key); err != nil && firstErr == nil {
-			firstErr = err
-			fmt.Printf("❌ [DELETE] L2 delete FAILED | Key: %s | Error: %v\n", key, err)
-		} else if err == nil {
-			fmt.Printf("✅ [DELETE] L2 delete SUCCESS | Key: %s\n", key)
+	if deleteL2 && !m.l2Available() {
+		l2Err := &CacheError{Op: "delete", Level: LevelL2, Key: key, Cause: ErrL2Degraded}
+		if firstErr == nil {
+			firstErr = l2Err
+		}
+		m.log.Debug(ctx, "cache delete skipping degraded l2", "key", key)
+		m.afterDelete(ctx, key, LevelL2, l2Err)
+	} else if deleteL2 {
+		var l2Err error
+		err := m.deleteL2(ctx, key)
+		m.degradation.record(err)
+		if err != nil {
+			l2Err = wrapError("delete", LevelL2, key, err)
+			if firstErr == nil {
+				firstErr = l2Err
+			}
+			m.log.Debug(ctx, "cache delete l2 failed", "key", key, "error", err)
+			m.emit(ctx, "delete", key, LevelL2, EventError)
+		} else {
+			m.log.Debug(ctx, "cache delete l2", "key", key)
+			m.emit(ctx, "delete", key, LevelL2, EventOK)
 		}
+		m.afterDelete(ctx, key, LevelL2, l2Err)
 	}
 
+	m.publishDelete(key)
+
 	if firstErr == nil {
-		fmt.Printf("✨ [DELETE] Successfully deleted from all cache levels\n")
+		firstErr = roErr
 	}
 
 	return firstErr
@@ -380,4 +1241,3 @@ func previewData(data []byte) string {
 	}
 	return preview
 }
-
@@ -0,0 +1,366 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRawCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttl  map[string]time.Duration
+}
+
+func newMemoryRawCache() *memoryRawCache {
+	return &memoryRawCache{
+		data: make(map[string][]byte),
+		ttl:  make(map[string]time.Duration),
+	}
+}
+
+func (m *memoryRawCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	val, ok := m.data[key]
+	if !ok {
+		return nil, false, nil
+	}
+	cp := make([]byte, len(val))
+	copy(cp, val)
+	return cp, true, nil
+}
+
+func (m *memoryRawCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := make([]byte, len(value))
+	copy(cp, value)
+	m.data[key] = cp
+	m.ttl[key] = ttl
+	return nil
+}
+
+func (m *memoryRawCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	delete(m.ttl, key)
+	return nil
+}
+
+func (m *memoryRawCache) Keys(_ context.Context, pattern string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for k := range m.data {
+		if ok, _ := path.Match(pattern, k); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memoryRawCache) has(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.data[key]
+	return ok
+}
+
+// newTestMultiLevelCache builds a cache with cfg over two fresh memoryRawCaches; see
+// newTestMultiLevelCacheOver for the defaults.
+func newTestMultiLevelCache(t *testing.T, cfg MultiLevelConfig) (*MultiLevelCache, *memoryRawCache, *memoryRawCache) {
+	t.Helper()
+
+	l1 := newMemoryRawCache()
+	l2 := newMemoryRawCache()
+	return newTestMultiLevelCacheOver(t, l1, l2, cfg), l1, l2
+}
+
+// newTestMultiLevelCacheOver builds a cache with cfg over l1 and l2 and JSONSerializer.
+// A zero WarmupTTL, L1DefaultTTL or L2DefaultTTL in cfg becomes one minute; the zero
+// Mode is ModeBothLevels.
+func newTestMultiLevelCacheOver(t *testing.T, l1, l2 RawCache, cfg MultiLevelConfig) *MultiLevelCache {
+	t.Helper()
+
+	if cfg.WarmupTTL == 0 {
+		cfg.WarmupTTL = time.Minute
+	}
+	if cfg.L1DefaultTTL == 0 {
+		cfg.L1DefaultTTL = time.Minute
+	}
+	if cfg.L2DefaultTTL == 0 {
+		cfg.L2DefaultTTL = time.Minute
+	}
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, cfg)
+	require.NoError(t, err)
+	return ml
+}
+
+func TestMultiLevelCacheL1MissL2HitWarmsL1(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	payload := map[string]string{"value": "from-l2"}
+
+	bytes, err := JSONSerializer{}.Marshal(payload)
+	require.NoError(t, err)
+	require.NoError(t, l2.Set(context.Background(), "key", bytes, time.Minute))
+
+	var result map[string]string
+	res, err := ml.Get(context.Background(), "key", &result, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, payload, result)
+	require.True(t, l1.has("key"), "expected L1 warm after L2 hit in ModeBothLevels")
+}
+
+func TestMultiLevelCacheGetReportsSourceLevel(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	var got string
+
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: false, Level: CacheLevelNone}, res)
+
+	// Seed only L2; the first read comes from there and warms L1.
+	require.NoError(t, l2.Set(ctx, "user:1", []byte(`"ada"`), time.Minute))
+	res, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL2}, res)
+	require.True(t, l1.has("user:1"))
+
+	res, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL1}, res)
+	require.Equal(t, "ada", got)
+}
+
+func TestMultiLevelCacheSetWritesBothAndDeleteEvictsBoth(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "key", map[string]string{"value": "cached"}, CacheOptions{}))
+	require.True(t, l1.has("key"))
+	require.True(t, l2.has("key"))
+
+	require.NoError(t, ml.Delete(ctx, "key"))
+	require.False(t, l1.has("key"))
+	require.False(t, l2.has("key"))
+}
+
+func TestMultiLevelCacheFlushRateLimited(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		Mode:              ModeBothLevels,
+		MaxFlushPerMinute: 3,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	succeeded, limited := 0, 0
+	for i := 0; i < 10; i++ {
+		_, err := ml.Flush(ctx)
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrFlushRateLimited):
+			limited++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	require.Equal(t, 3, succeeded)
+	require.Equal(t, 7, limited)
+
+	_, err = ml.DeleteByPrefix(ctx, "user:")
+	require.ErrorIs(t, err, ErrFlushRateLimited)
+}
+
+func TestMultiLevelCacheDeleteByPrefix(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	for _, key := range []string{"user:1", "user:2", "session:1"} {
+		require.NoError(t, ml.Set(ctx, key, key, CacheOptions{}))
+	}
+
+	deleted, err := ml.DeleteByPrefix(ctx, "user:")
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+	require.False(t, l1.has("user:1"))
+	require.False(t, l2.has("user:2"))
+	require.True(t, l1.has("session:1"))
+}
+
+func TestMultiLevelCacheSkipsOversizeL1Writes(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	l2 := newMemoryRawCache()
+	var skipped []string
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:            ModeBothLevels,
+		L1MaxValueBytes: 16,
+		OnSkip: func(key, level, reason string) {
+			skipped = append(skipped, key+"|"+level+"|"+reason)
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	large := map[string]string{"value": "this payload is well over sixteen bytes"}
+
+	require.NoError(t, ml.Set(ctx, "big", large, CacheOptions{}))
+	require.False(t, l1.has("big"))
+	require.True(t, l2.has("big"))
+
+	// Warmup from the L2 hit must not push it into L1 either.
+	var got map[string]string
+	res, err := ml.Get(ctx, "big", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, large, got)
+	require.False(t, l1.has("big"))
+
+	require.Equal(t, uint64(2), ml.L1SkippedOversize())
+	require.Equal(t, []string{"big|L1|" + SkipReasonOversize, "big|L1|" + SkipReasonOversize}, skipped)
+
+	// Small values and per-call overrides still reach L1.
+	require.NoError(t, ml.Set(ctx, "small", 1, CacheOptions{}))
+	require.True(t, l1.has("small"))
+	require.NoError(t, ml.Set(ctx, "big-allowed", large, CacheOptions{L1MaxValueBytes: -1}))
+	require.True(t, l1.has("big-allowed"))
+	require.Equal(t, uint64(2), ml.L1SkippedOversize())
+}
+
+// warmWithJSON marshals each value with encoding/json and loads the result through Warm.
+func warmWithJSON(t *testing.T, ml *MultiLevelCache, entries map[string]any) {
+	t.Helper()
+
+	raw := make(map[string][]byte, len(entries))
+	for key, value := range entries {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		raw[key] = data
+	}
+	require.NoError(t, ml.Warm(context.Background(), raw, 0, 0))
+}
+
+func TestMultiLevelCacheWarmWritesRawBytesToBothLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	require.NoError(t, ml.Warm(ctx, map[string][]byte{"user:1": []byte(`{"name":"ada"}`)}, 0, 2*time.Minute))
+
+	raw, found, err := l1.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.JSONEq(t, `{"name":"ada"}`, string(raw))
+	require.Equal(t, time.Minute, l1.ttl["user:1"])
+	require.True(t, l2.has("user:1"))
+	require.Equal(t, 2*time.Minute, l2.ttl["user:1"])
+
+	warmWithJSON(t, ml, map[string]any{"user:2": map[string]string{"name": "grace"}})
+	var got map[string]string
+	res, err := ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "grace", got["name"])
+}
+
+func TestMultiLevelCacheTTLProviderPerKey(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	l2 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:         ModeBothLevels,
+		L1DefaultTTL: time.Minute,
+		L2DefaultTTL: time.Minute,
+		TTLProvider: PatternTTLProvider{
+			{Pattern: "user:*", L1TTL: 10 * time.Minute, L2TTL: time.Hour},
+			{Pattern: "session:*", L1TTL: 30 * time.Second, L2TTL: 2 * time.Minute},
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+	require.NoError(t, ml.Set(ctx, "session:1", "token", CacheOptions{}))
+	require.NoError(t, ml.Set(ctx, "other", 1, CacheOptions{}))
+	require.NoError(t, ml.Set(ctx, "user:2", "grace", CacheOptions{L2TTL: 5 * time.Minute}))
+
+	require.Equal(t, 10*time.Minute, l1.ttl["user:1"])
+	require.Equal(t, time.Hour, l2.ttl["user:1"])
+	require.Equal(t, 30*time.Second, l1.ttl["session:1"])
+	require.Equal(t, 2*time.Minute, l2.ttl["session:1"])
+	require.Equal(t, time.Minute, l1.ttl["other"])
+	require.Equal(t, time.Minute, l2.ttl["other"])
+	// Per-call options still win over the provider.
+	require.Equal(t, 10*time.Minute, l1.ttl["user:2"])
+	require.Equal(t, 5*time.Minute, l2.ttl["user:2"])
+}
+
+func TestMultiLevelCacheAccessors(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		WarmupTTL:    10 * time.Second,
+		L1DefaultTTL: 40 * time.Second,
+		L2DefaultTTL: 2 * time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ModeBothLevels, ml.Mode())
+	require.Equal(t, 10*time.Second, ml.WarmupTTL())
+	require.Equal(t, 40*time.Second, ml.L1DefaultTTL())
+	require.Equal(t, 2*time.Minute, ml.L2DefaultTTL())
+	require.True(t, ml.IsAllowOverrides())
+	require.True(t, ml.HasL1())
+	require.True(t, ml.HasL2())
+	require.Equal(t, "MultiLevelCache{mode=both-levels, l1_ttl=40s, l2_ttl=2m0s}", ml.String())
+
+	l1Only, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+	require.Equal(t, ModeL1Only, l1Only.Mode())
+	require.Equal(t, 5*time.Minute, l1Only.WarmupTTL())
+	require.False(t, l1Only.IsAllowOverrides())
+	require.True(t, l1Only.HasL1())
+	require.False(t, l1Only.HasL2())
+
+	l2Only, err := NewMultiLevelCache(nil, newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only})
+	require.NoError(t, err)
+	require.False(t, l2Only.HasL1())
+	require.True(t, l2Only.HasL2())
+	require.Equal(t, "MultiLevelCache{mode=l2-only, l1_ttl=5m0s, l2_ttl=5m0s}", l2Only.String())
+}
+
+func TestNewMultiLevelCacheLogsSummary(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{
+		Logger:        slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		LogSampleRate: Float64Ptr(0),
+	})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "cache created")
+	require.Contains(t, buf.String(), ml.String())
+}
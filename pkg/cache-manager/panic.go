@@ -0,0 +1,39 @@
+package cache_manager
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrPanic is the cause of the CacheError returned when a backend, serializer or hook
+// panicked inside Get, GetWithMetadata, Set or Delete. Match it with errors.As.
+type ErrPanic struct {
+	// Cause is the value passed to panic.
+	Cause any
+}
+
+func (e *ErrPanic) Error() string {
+	return fmt.Sprintf("cache panic: %v", e.Cause)
+}
+
+// Unwrap returns Cause when the panic value is an error, such as a runtime.Error.
+func (e *ErrPanic) Unwrap() error {
+	if err, ok := e.Cause.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// recoverPanic must be deferred directly. It turns a panic into a CacheError wrapping an
+// ErrPanic stored in *err, or panics again with MultiLevelConfig.PanicOnInternalError.
+func (m *MultiLevelCache) recoverPanic(op, key string, err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if m.panicOnInternal {
+		panic(v)
+	}
+	m.log.logger.Error("cache panic recovered", "op", op, "key", key, "panic", v, "stack", string(debug.Stack()))
+	*err = &CacheError{Op: op, Key: key, Cause: &ErrPanic{Cause: v}}
+}
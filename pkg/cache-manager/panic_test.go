@@ -0,0 +1,71 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// panickingRawCache panics with value in every operation.
+type panickingRawCache struct {
+	value any
+}
+
+func (p panickingRawCache) Get(context.Context, string) ([]byte, bool, error) { panic(p.value) }
+func (p panickingRawCache) Set(context.Context, string, []byte, time.Duration) error {
+	panic(p.value)
+}
+func (p panickingRawCache) Delete(context.Context, string) error { panic(p.value) }
+
+func TestMultiLevelCacheRecoversPanics(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	boom := errors.New("boom")
+	ml, err := NewMultiLevelCache(panickingRawCache{value: boom}, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	var dest string
+	res, err := ml.Get(ctx, "user:1", &dest, CacheOptions{})
+	require.Error(t, err)
+	require.False(t, res.Found)
+	require.Equal(t, CacheLevelNone, res.Level)
+	var pe *ErrPanic
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, boom, pe.Cause)
+	require.ErrorIs(t, err, boom, "an error panic value is unwrapped")
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, "get", ce.Op)
+	require.Equal(t, "user:1", ce.Key)
+
+	_, _, err = ml.GetWithMetadata(ctx, "user:1", &dest, CacheOptions{})
+	require.ErrorAs(t, err, &pe)
+	require.ErrorAs(t, ml.Set(ctx, "user:1", "v", CacheOptions{}), &pe)
+	require.ErrorAs(t, ml.Delete(ctx, "user:1"), &pe)
+
+	ml, err = NewMultiLevelCache(panickingRawCache{value: "not an error"}, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+	err = ml.Set(ctx, "user:1", "v", CacheOptions{})
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "not an error", pe.Cause)
+	require.Nil(t, pe.Unwrap())
+	require.ErrorContains(t, err, "cache panic: not an error")
+}
+
+func TestMultiLevelCachePanicOnInternalError(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(panickingRawCache{value: "boom"}, nil, JSONSerializer{}, MultiLevelConfig{
+		Mode:                 ModeL1Only,
+		PanicOnInternalError: true,
+	})
+	require.NoError(t, err)
+
+	var dest string
+	require.PanicsWithValue(t, "boom", func() { _, _ = ml.Get(context.Background(), "user:1", &dest, CacheOptions{}) })
+	require.PanicsWithValue(t, "boom", func() { _ = ml.Delete(context.Background(), "user:1") })
+}
@@ -0,0 +1,44 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ExpiredPurger is implemented by raw caches that can remove their expired entries on
+// demand, such as BigCache.
+type ExpiredPurger interface {
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+var _ ExpiredPurger = (*BigCache)(nil)
+
+// PurgeExpired removes the expired entries of L1 now instead of waiting for them to be
+// read, swept by a BigCacheJanitor or aged out by LifeWindow, and returns how many were
+// removed. It scans the whole L1, so the entries of other caches sharing it are purged
+// too. L2 is not touched; Redis expires its keys itself.
+//
+// L1 must implement ExpiredPurger, as BigCache does.
+func (m *MultiLevelCache) PurgeExpired(ctx context.Context) (int64, error) {
+	if m == nil {
+		return 0, &CacheError{Op: "purge", Cause: ErrNotInitialized}
+	}
+	if m.l1 == nil {
+		return 0, &CacheError{Op: "purge", Level: LevelL1, Cause: ErrLevelNotConfigured}
+	}
+	purger, ok := m.l1.(ExpiredPurger)
+	if !ok {
+		return 0, &CacheError{Op: "purge", Level: LevelL1, Cause: fmt.Errorf("L1 cannot purge expired entries: %w", errors.ErrUnsupported)}
+	}
+	defer m.observeLatency("purge", time.Now())
+
+	purged, err := purger.PurgeExpired(ctx)
+	if err != nil {
+		return purged, wrapError("purge", LevelL1, "", err)
+	}
+	m.log.Log(ctx, slog.LevelInfo, "cache purged expired l1 entries", "purged", purged)
+	return purged, nil
+}
@@ -0,0 +1,87 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeExpiredRemovesExpiredL1Entries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	bc, err := NewBigCache(ctx, BigCacheConfig{Config: bigcache.DefaultConfig(time.Minute), Clock: clock})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = bc.Close() })
+	ml, err := NewMultiLevelCache(bc, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, ml.Set(ctx, fmt.Sprintf("short:%d", i), i, CacheOptions{L1TTL: 50 * time.Millisecond}))
+	}
+	require.NoError(t, ml.Set(ctx, "long", "v", CacheOptions{L1TTL: time.Minute}))
+	clock.Advance(100 * time.Millisecond)
+
+	purged, err := ml.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), purged)
+	require.Equal(t, 1, bc.cache.Len(), "expired entries must be gone without being read")
+
+	purged, err = ml.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+}
+
+func TestPurgeExpiredNeedsPurgeableL1(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+	_, err = ml.PurgeExpired(context.Background())
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	ml, err = NewMultiLevelCache(nil, newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only})
+	require.NoError(t, err)
+	_, err = ml.PurgeExpired(context.Background())
+	require.ErrorIs(t, err, ErrLevelNotConfigured)
+}
+
+func TestBigCachePurgeExpiredKeepsConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{})
+	const keys = 500
+	for round := range 20 {
+		for i := range keys {
+			require.NoError(t, bc.Set(ctx, fmt.Sprintf("k%d", i), []byte("old"), time.Millisecond))
+		}
+		clock.Advance(time.Second)
+
+		// Rewrite every expired key while the purge runs; no fresh write may be lost
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range keys {
+				_ = bc.Set(ctx, fmt.Sprintf("k%d", i), []byte("fresh"), time.Hour)
+			}
+		}()
+		_, err := bc.PurgeExpired(ctx)
+		require.NoError(t, err)
+		<-done
+
+		for i := range keys {
+			data, ok, err := bc.Get(ctx, fmt.Sprintf("k%d", i))
+			require.NoError(t, err)
+			require.True(t, ok, "round %d: fresh write of k%d was purged", round, i)
+			require.Equal(t, []byte("fresh"), data)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultQuarantineCooldown = time.Minute
+
+// entryQuarantine tracks the keys deleted by MultiLevelConfig.QuarantineUndecodable.
+type entryQuarantine struct {
+	cooldown time.Duration
+	clock    Clock
+	poisoned atomic.Int64
+
+	mu    sync.Mutex
+	until map[string]time.Time // key -> end of its cooldown
+}
+
+func newEntryQuarantine(enabled bool, cooldown time.Duration, clock Clock) *entryQuarantine {
+	if !enabled {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = defaultQuarantineCooldown
+	}
+	return &entryQuarantine{cooldown: cooldown, clock: clock, until: make(map[string]time.Time)}
+}
+
+// admit reports whether key may be quarantined now, starting its cooldown if so.
+// Expired cooldowns are dropped on the way so the map only holds recent keys.
+func (q *entryQuarantine) admit(key string) bool {
+	now := q.clock.Now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if until, ok := q.until[key]; ok && now.Before(until) {
+		return false
+	}
+	for k, until := range q.until {
+		if !now.Before(until) {
+			delete(q.until, k)
+		}
+	}
+	q.until[key] = now.Add(q.cooldown)
+	return true
+}
+
+// quarantineEntry deletes key from every writable level after its payload in level
+// failed to decode with cause. It returns false, leaving the entry in place, when
+// QuarantineUndecodable is off or key is still cooling down from an earlier quarantine.
+func (m *MultiLevelCache) quarantineEntry(ctx context.Context, key, level string, cause error) bool {
+	if m.quarantine == nil || !m.quarantine.admit(key) {
+		return false
+	}
+	m.quarantine.poisoned.Add(1)
+	m.log.logger.Warn("cache entry undecodable, quarantining", "key", m.logicalKey(key), "level", level, "error", cause)
+
+	deleteL1, deleteL2, _ := m.readOnly.filter("quarantine", key, m.l1 != nil, m.l2 != nil && m.l2Available())
+	if deleteL1 {
+		if err := m.l1.Delete(ctx, key); err != nil {
+			m.log.logger.Warn("cache quarantine l1 delete failed", "key", m.logicalKey(key), "error", err)
+		} else if m.l1Budget != nil {
+			m.l1Budget.forget(key)
+		}
+	}
+	if deleteL2 {
+		if err := m.deleteL2(ctx, key); err != nil {
+			m.log.logger.Warn("cache quarantine l2 delete failed", "key", m.logicalKey(key), "error", err)
+		}
+	}
+	m.changes.forget(key)
+	return true
+}
+
+// quarantinedMiss finishes a Get whose entry was quarantined as a miss, loading the
+// value when a Loader is configured.
+func (m *MultiLevelCache) quarantinedMiss(ctx context.Context, key string, dest any, opts CacheOptions) (EntryMetadata, bool, error) {
+	m.emit(ctx, "get", key, "", EventMiss)
+	if m.loader != nil && dest != nil {
+		found, err := m.load(ctx, key, dest, opts)
+		return EntryMetadata{}, found, err
+	}
+	return EntryMetadata{}, false, nil
+}
@@ -0,0 +1,121 @@
+package cache_manager
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type quarantineUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestQuarantineUndecodableHealsWithLoader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var loads atomic.Int32
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{
+		QuarantineUndecodable: true,
+		Loader: LoaderFunc(func(context.Context, string) (any, time.Duration, error) {
+			loads.Add(1)
+			return quarantineUser{ID: 1, Name: "ada"}, 0, nil
+		}),
+	})
+	// An entry written by an older version where the id was a string
+	old := []byte(`{"id":"one","name":"ada"}`)
+	require.NoError(t, l1.Set(ctx, "user:1", old, time.Minute))
+	require.NoError(t, l2.Set(ctx, "user:1", old, time.Minute))
+
+	var got quarantineUser
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, quarantineUser{ID: 1, Name: "ada"}, got)
+	require.EqualValues(t, 1, loads.Load())
+
+	// The loader rewrote a good entry, so the second Get is a plain hit
+	got = quarantineUser{}
+	res, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, quarantineUser{ID: 1, Name: "ada"}, got)
+	require.EqualValues(t, 1, loads.Load())
+
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stats.PoisonedEntries)
+}
+
+func TestQuarantineUndecodableWithoutLoader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, _, l2 := newTestMultiLevelCache(t, MultiLevelConfig{QuarantineUndecodable: true})
+	require.NoError(t, l2.Set(ctx, "user:1", []byte(`{"id":"one"}`), time.Minute))
+
+	var got quarantineUser
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found, "an undecodable L2 entry is a miss")
+	require.False(t, l2.has("user:1"))
+
+	require.NoError(t, ml.Set(ctx, "user:1", quarantineUser{ID: 1, Name: "ada"}, CacheOptions{}))
+	res, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, quarantineUser{ID: 1, Name: "ada"}, got)
+}
+
+func TestQuarantineUndecodableCooldown(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{
+		QuarantineUndecodable: true,
+		QuarantineCooldown:    time.Minute,
+		Clock:                 clock,
+	})
+	bad := []byte(`{"id":"one"}`)
+
+	require.NoError(t, l2.Set(ctx, "user:1", bad, time.Minute))
+	var got quarantineUser
+	_, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, l2.has("user:1"))
+
+	// A second bad write within the cooldown is left in place and reported
+	require.NoError(t, l2.Set(ctx, "user:1", bad, time.Minute))
+	_, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.Error(t, err)
+	require.True(t, l2.has("user:1"))
+	require.False(t, l1.has("user:1"))
+
+	clock.Advance(time.Minute)
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.False(t, l2.has("user:1"))
+
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, stats.PoisonedEntries)
+}
+
+func TestQuarantineUndecodableOff(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, _, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, l2.Set(ctx, "user:1", []byte(`{"id":"one"}`), time.Minute))
+
+	var got quarantineUser
+	_, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.Error(t, err)
+	require.True(t, l2.has("user:1"))
+}
@@ -0,0 +1,103 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimiter decides whether n units of work may proceed under the limit named
+// limiterID. MultiLevelConfig.L2RateLimiter uses one to throttle L2 writes.
+type RateLimiter interface {
+	Allow(ctx context.Context, limiterID string, n int) (bool, error)
+}
+
+var _ RateLimiter = (*DistributedRateLimiter)(nil)
+
+// tokenBucketScript refills the bucket in KEYS[1] (tokens) and KEYS[2] (last refill,
+// in microseconds of Redis server time) at ARGV[1] tokens per second up to ARGV[2],
+// then takes ARGV[3] tokens if that many are available. It returns 1 when they were
+// taken. Using the server clock keeps every process on the same time base. Both keys
+// expire once a full bucket would have refilled, so idle limiters clean up after
+// themselves.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tokens = tonumber(redis.call('GET', KEYS[1]))
+local last = tonumber(redis.call('GET', KEYS[2]))
+if tokens == nil or last == nil then
+	tokens = burst
+	last = now
+end
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) * rate / 1000000)
+end
+
+local allowed = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+end
+
+local ttl = math.ceil(burst / rate * 1000) + 1000
+redis.call('SET', KEYS[1], tostring(tokens), 'PX', ttl)
+redis.call('SET', KEYS[2], tostring(now), 'PX', ttl)
+return allowed
+`)
+
+// DistributedRateLimiter is a token bucket kept in Redis, so every process using the
+// same limiter ID shares one budget. Each Allow is a single script call, which makes
+// the check and the take atomic across processes.
+type DistributedRateLimiter struct {
+	client *redis.Client
+	rate   float64
+	burst  int
+}
+
+// NewDistributedRateLimiter returns a limiter that refills rate tokens per second up to
+// burst tokens. A burst below 1 is raised to 1.
+func NewDistributedRateLimiter(client *redis.Client, rate float64, burst int) (*DistributedRateLimiter, error) {
+	if client == nil {
+		return nil, ErrRedisClientMissing
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, errors.New("rate must be a positive number of tokens per second")
+	}
+	return &DistributedRateLimiter{client: client, rate: rate, burst: max(burst, 1)}, nil
+}
+
+// Allow takes n tokens from the bucket of limiterID and reports whether they were
+// available. A denied call takes nothing.
+func (l *DistributedRateLimiter) Allow(ctx context.Context, limiterID string, n int) (bool, error) {
+	keys := []string{"ratelimit:" + limiterID + ":tokens", "ratelimit:" + limiterID + ":last_refill"}
+	allowed, err := tokenBucketScript.Run(ctx, l.client, keys, l.rate, l.burst, n).Int()
+	if err != nil {
+		return false, &CacheError{Op: "ratelimit", Level: LevelL2, Key: limiterID, Cause: err}
+	}
+	return allowed == 1, nil
+}
+
+// defaultL2RateLimitID names the L2 write limiter when neither L2RateLimitID,
+// Namespace nor InstanceName is set.
+const defaultL2RateLimitID = "l2-writes"
+
+// allowL2Write asks the L2 rate limiter for one write. Without a limiter, or when the
+// limiter itself fails, the write is allowed, so a Redis hiccup in the limiter does not
+// stop caching.
+func (m *MultiLevelCache) allowL2Write(ctx context.Context, key string) bool {
+	if m.l2RateLimiter == nil {
+		return true
+	}
+	allowed, err := m.l2RateLimiter.Allow(ctx, m.l2RateLimitID, 1)
+	if err != nil {
+		m.log.Debug(ctx, "cache l2 rate limiter failed, allowing write", "key", key, "error", err)
+		return true
+	}
+	return allowed
+}
@@ -0,0 +1,98 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistributedRateLimiterConcurrentCallersShareBurst(t *testing.T) {
+	t.Parallel()
+
+	l2, mr := newRenameTestRedis(t)
+	mr.SetTime(time.Unix(1_700_000_000, 0))
+	limiter, err := NewDistributedRateLimiter(l2.client, 5, 20)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 4 {
+				ok, err := limiter.Allow(ctx, "users", 1)
+				require.NoError(t, err)
+				if ok {
+					allowed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int64(20), allowed.Load(), "only the burst passes while the clock stands still")
+	require.True(t, mr.Exists("ratelimit:users:tokens"))
+	require.True(t, mr.Exists("ratelimit:users:last_refill"))
+
+	// Two seconds at 5 tokens per second refill 10 tokens
+	mr.SetTime(time.Unix(1_700_000_002, 0))
+	ok, err := limiter.Allow(ctx, "users", 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = limiter.Allow(ctx, "users", 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = limiter.Allow(ctx, "orders", 1)
+	require.NoError(t, err)
+	require.True(t, ok, "limiter IDs have separate buckets")
+}
+
+func TestL2RateLimiterSkipsL2Writes(t *testing.T) {
+	t.Parallel()
+
+	l2, mr := newRenameTestRedis(t)
+	mr.SetTime(time.Unix(1_700_000_000, 0))
+	limiter, err := NewDistributedRateLimiter(l2.client, 1, 2)
+	require.NoError(t, err)
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, L2RateLimiter: limiter})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, ml.Set(ctx, key, key, CacheOptions{}), "L1 still takes the write")
+		require.True(t, l1.has(key))
+	}
+	require.True(t, mr.Exists("a"))
+	require.True(t, mr.Exists("b"))
+	require.False(t, mr.Exists("c"))
+
+	l2Only, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only, L2RateLimiter: limiter})
+	require.NoError(t, err)
+	require.ErrorIs(t, l2Only.Set(ctx, "d", "d", CacheOptions{}), ErrL2RateLimited)
+}
+
+// failingRateLimiter returns err from every Allow.
+type failingRateLimiter struct{ err error }
+
+func (f failingRateLimiter) Allow(context.Context, string, int) (bool, error) { return false, f.err }
+
+func TestL2RateLimiterFailureAllowsWrite(t *testing.T) {
+	t.Parallel()
+
+	l2 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:          ModeL2Only,
+		L2RateLimiter: failingRateLimiter{errors.New("redis down")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(context.Background(), "k", 1, CacheOptions{}))
+	require.True(t, l2.has("k"))
+}
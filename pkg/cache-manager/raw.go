@@ -0,0 +1,57 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+)
+
+// RawGetter is implemented by caches that can return stored payloads without decoding them.
+type RawGetter interface {
+	GetRaw(ctx context.Context, key string, opts CacheOptions) ([]byte, CacheGetResult, error)
+}
+
+var _ RawGetter = (*MultiLevelCache)(nil)
+
+// rawPayload is the dest GetRaw passes through get; decode copies the payload into it
+// instead of unmarshaling.
+type rawPayload struct {
+	data []byte
+}
+
+// GetRaw is Get without the Unmarshal: it returns the serialized payload as Set stored
+// it, after removing the L1 envelope, content hash, chunking and L2 compression, so a
+// handler can write a JSON payload straight to the response. An L2 hit still warms L1.
+//
+// With split L1/L2 serializers the payload is in the format of the level that served it,
+// and L2 hits do not warm L1 because there is no decoded value to re-encode. A value
+// filled by MultiLevelConfig.Loader is returned in the L1 format.
+func (m *MultiLevelCache) GetRaw(ctx context.Context, key string, opts CacheOptions) ([]byte, CacheGetResult, error) {
+	res := CacheGetResult{Level: CacheLevelNone}
+	if m == nil {
+		return nil, res, &CacheError{Op: "get", Key: key, Cause: ErrNotInitialized}
+	}
+
+	var raw rawPayload
+	meta, found, err := m.get(ctx, key, &raw, opts)
+	if err != nil || !found {
+		return nil, res, err
+	}
+	res.Found = true
+	if meta.Level != "" {
+		res.Level = CacheLevelHit(meta.Level)
+	}
+	return raw.data, res, nil
+}
+
+// decodeRaw stores payload in dest when dest is a *rawPayload. The payload may be shared
+// with coalesced readers, so it is copied.
+func decodeRaw(payload []byte, dest any) bool {
+	raw, ok := dest.(*rawPayload)
+	if ok {
+		raw.data = bytes.Clone(payload)
+		if raw.data == nil {
+			raw.data = []byte{}
+		}
+	}
+	return ok
+}
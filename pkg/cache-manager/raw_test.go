@@ -0,0 +1,85 @@
+package cache_manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRawReturnsStoredBytes(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	user := loadedUser{ID: 1, Name: "Ada"}
+	want, err := JSONSerializer{}.Marshal(user)
+	require.NoError(t, err)
+
+	_, res, err := ml.GetRaw(ctx, "user:1", CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Level: CacheLevelNone}, res)
+
+	require.NoError(t, ml.Set(ctx, "user:1", user, CacheOptions{}))
+	raw, res, err := ml.GetRaw(ctx, "user:1", CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL1}, res)
+	require.Equal(t, want, raw)
+
+	// An L2 hit returns the same bytes and warms L1.
+	require.NoError(t, l1.Delete(ctx, "user:1"))
+	raw, res, err = ml.GetRaw(ctx, "user:1", CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL2}, res)
+	require.Equal(t, want, raw)
+	warmed, found, err := l1.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, want, warmed)
+}
+
+func TestGetRawStripsFraming(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:          ModeBothLevels,
+		WarmupTTL:     time.Minute,
+		ContentHashes: true,
+		L2Compression: L2CompressionConfig{Algorithm: CompressionGzip},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	value := map[string]string{"bio": strings.Repeat("cache ", 100)}
+	want, err := JSONSerializer{}.Marshal(value)
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Set(ctx, "user:1", value, CacheOptions{}))
+	require.NoError(t, l1.Delete(ctx, "user:1"))
+
+	raw, res, err := ml.GetRaw(ctx, "user:1", CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+	require.Equal(t, want, raw)
+
+	raw, res, err = ml.GetRaw(ctx, "user:1", CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL1, res.Level)
+	require.Equal(t, want, raw)
+}
+
+func TestGetRawWithSplitSerializersSkipsWarmup(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{L1Serializer: GobSerializer{}, L2Serializer: JSONSerializer{}})
+	ctx := context.Background()
+	require.NoError(t, l2.Set(ctx, "user:9", []byte(`{"ID":9,"Name":"Grace"}`), time.Minute))
+
+	raw, res, err := ml.GetRaw(ctx, "user:9", CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+	require.JSONEq(t, `{"ID":9,"Name":"Grace"}`, string(raw))
+	require.False(t, l1.has("user:9"), "raw L2 bytes cannot be re-encoded for L1")
+}
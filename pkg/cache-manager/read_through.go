@@ -0,0 +1,319 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"go-cache-poc/internal/db"
+)
+
+// ReadThroughCache wraps any Cache with a loader for misses. Unlike MultiLevelConfig.Loader
+// it works with every Cache implementation and is configured per call site. Concurrent
+// misses for the same key share one Loader call.
+type ReadThroughCache struct {
+	Cache Cache
+	// KeyFn builds the cache key for GetID.
+	KeyFn func(id int) string
+	// Loader fetches the value for key from the source of truth. Its errors are returned
+	// unchanged, so callers can match their own not-found errors.
+	Loader func(ctx context.Context, key string) (any, error)
+	// Options are passed to every cache Get and Set; the zero value uses the cache defaults.
+	Options CacheOptions
+	// FallbackOnCacheError loads from Loader when the cache read fails instead of
+	// returning the cache error.
+	FallbackOnCacheError bool
+	// DeletedErr is returned by Get for a key removed with Delete while its tombstone
+	// lives, without calling Loader. nil disables tombstones: Delete only drops the key.
+	DeletedErr error
+	// TombstoneTTL is how long Delete's tombstone lives. Default 30s; it only needs to
+	// outlast reads and loads that started before the delete.
+	TombstoneTTL time.Duration
+	// Latency receives the cost of each Get that called Loader, by key prefix (the part
+	// before the first ":"): the Loader time as "miss_load:<prefix>" and the time spent in
+	// the cache as "miss_cache:<prefix>". nil records into Cache when it is a
+	// MultiLevelCache, so the samples show in its LatencyReport.
+	Latency *LatencyTracker
+
+	loads singleflight.Group
+}
+
+// ReadTiming is where the time of a ReadThroughCache.Get went, e.g. for a Server-Timing
+// header. Load is zero unless the Loader ran, including when another caller's load was
+// shared; Cache is the rest of the call.
+type ReadTiming struct {
+	Cache time.Duration
+	Load  time.Duration
+}
+
+// loadResult is what a ReadThroughCache load flight hands its callers.
+type loadResult struct {
+	value any
+	took  time.Duration // Loader time; zero when the flight found the value cached
+	ran   bool
+}
+
+// defaultTombstoneTTL is used when ReadThroughCache.TombstoneTTL is not set.
+const defaultTombstoneTTL = 30 * time.Second
+
+// ReadThroughUserCache returns a ReadThroughCache for users stored under UserCacheKey.
+func ReadThroughUserCache(cache Cache, store db.UserStore) *ReadThroughCache {
+	return &ReadThroughCache{
+		Cache: cache,
+		KeyFn: UserCacheKey,
+		Loader: func(ctx context.Context, key string) (any, error) {
+			k, err := ParseKey(key)
+			if err != nil {
+				return nil, err
+			}
+			id, err := k.IntSegment(1)
+			if err != nil || k.Segment(0) != "user" {
+				return nil, fmt.Errorf("not a user key: %q", key)
+			}
+			return store.GetUser(ctx, id)
+		},
+		DeletedErr: db.ErrUserNotFound,
+	}
+}
+
+// GetID is Get for the key KeyFn builds from id.
+func (r *ReadThroughCache) GetID(ctx context.Context, id int, dest any) (CacheGetResult, error) {
+	if r.KeyFn == nil {
+		return CacheGetResult{Level: CacheLevelNone}, errors.New("read-through cache has no KeyFn")
+	}
+	return r.Get(ctx, r.KeyFn(id), dest)
+}
+
+// Get fills dest (a pointer to the loaded type) from the cache, or on a miss from Loader,
+// caching the loaded value. The result reports the cache level dest was served from;
+// after a load it is a miss (Found false, Level CacheLevelNone) even though dest is filled.
+func (r *ReadThroughCache) Get(ctx context.Context, key string, dest any) (CacheGetResult, error) {
+	start := time.Now()
+	miss := CacheGetResult{Level: CacheLevelNone}
+	res, err := r.Cache.Get(ctx, key, dest, r.Options)
+	if err != nil && !r.FallbackOnCacheError {
+		return miss, err
+	}
+	if res.Found {
+		// An L2 hit warmed L1; if the L2 read raced with Delete, that copy is stale.
+		if res.Level == CacheLevelL2 && r.warmsL1() {
+			if err := r.checkTombstone(ctx, key); err != nil {
+				return miss, err
+			}
+		}
+		res.Timing.Cache = time.Since(start)
+		return res, nil
+	}
+	if err := r.checkTombstone(ctx, key); err != nil {
+		return miss, err
+	}
+
+	v, err, _ := r.loads.Do(key, func() (any, error) {
+		// A flight that finished just before this one may already have filled the cache.
+		if res, err := r.Cache.Get(ctx, key, dest, r.Options); err == nil && res.Found {
+			return loadResult{}, nil
+		}
+		began := time.Now()
+		value, err := r.Loader(ctx, key)
+		loaded := loadResult{value: value, took: time.Since(began), ran: true}
+		if err != nil {
+			return loaded, err
+		}
+		if err := r.store(ctx, key, value); err != nil {
+			return loaded, err
+		}
+		return loaded, nil
+	})
+	loaded, _ := v.(loadResult)
+	if loaded.ran {
+		miss.Timing = ReadTiming{Load: loaded.took, Cache: time.Since(start) - loaded.took}
+		r.recordMiss(key, miss.Timing)
+	}
+	if err != nil {
+		return miss, err
+	}
+	if !loaded.ran {
+		// Served from the cache inside the flight; other callers of that flight re-read it.
+		res, err := r.Cache.Get(ctx, key, dest, r.Options)
+		if err != nil || !res.Found {
+			return miss, fmt.Errorf("read-through %s: cached value vanished: %w", key, err)
+		}
+		res.Timing.Cache = time.Since(start)
+		return res, nil
+	}
+	return miss, assignLoaded(dest, loaded.value)
+}
+
+// recordMiss adds the timing of a Get that called Loader to Latency.
+func (r *ReadThroughCache) recordMiss(key string, t ReadTiming) {
+	tracker := r.Latency
+	if m, ok := r.Cache.(*MultiLevelCache); ok && tracker == nil && m != nil {
+		tracker = m.latency
+	}
+	if tracker == nil {
+		return
+	}
+	prefix := keyPrefix(key)
+	tracker.Record("miss_load:"+prefix, t.Load)
+	tracker.Record("miss_cache:"+prefix, t.Cache)
+}
+
+// Refresh loads key from Loader and caches it without reading the cache first, e.g. for
+// a client that sent Cache-Control: no-cache.
+func (r *ReadThroughCache) Refresh(ctx context.Context, key string, dest any) error {
+	value, err := r.Loader(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := r.store(ctx, key, value); err != nil {
+		return err
+	}
+	return assignLoaded(dest, value)
+}
+
+// Delete removes key from the cache. With DeletedErr set it first writes a tombstone,
+// so Get reports DeletedErr without calling Loader until the tombstone expires, and a
+// read or load that started before the delete cannot put the old value back. Delete
+// the row from the source of truth before calling it.
+func (r *ReadThroughCache) Delete(ctx context.Context, key string) error {
+	if r.DeletedErr != nil {
+		ttl := r.TombstoneTTL
+		if ttl <= 0 {
+			ttl = defaultTombstoneTTL
+		}
+		opts := r.Options
+		opts.L1TTL, opts.L2TTL, opts.Priority = ttl, ttl, 0
+		if err := r.Cache.Set(ctx, tombstoneKey(key), true, opts); err != nil {
+			return err
+		}
+	}
+	return r.Cache.Delete(ctx, key)
+}
+
+// store caches a loaded value. When a tombstone appeared while it was loading, the value
+// predates a Delete: it is dropped again and DeletedErr returned.
+func (r *ReadThroughCache) store(ctx context.Context, key string, value any) error {
+	if err := r.Cache.Set(ctx, key, value, r.Options); err != nil && !r.FallbackOnCacheError {
+		return err
+	}
+	return r.checkTombstone(ctx, key)
+}
+
+// checkTombstone returns DeletedErr when key has a live tombstone, after removing any
+// copy of key a racing read or load cached since the delete.
+func (r *ReadThroughCache) checkTombstone(ctx context.Context, key string) error {
+	if r.DeletedErr == nil {
+		return nil
+	}
+	var deleted bool
+	res, err := r.Cache.Get(ctx, tombstoneKey(key), &deleted, r.Options)
+	if err != nil {
+		if r.FallbackOnCacheError {
+			return nil
+		}
+		return err
+	}
+	if !res.Found {
+		return nil
+	}
+	if err := r.Cache.Delete(ctx, key); err != nil && !r.FallbackOnCacheError {
+		return err
+	}
+	return r.DeletedErr
+}
+
+// warmsL1 reports whether an L2 hit can have copied the value into L1.
+func (r *ReadThroughCache) warmsL1() bool {
+	if r.Options.TargetL1 != nil && !*r.Options.TargetL1 {
+		return false
+	}
+	if c, ok := r.Cache.(interface{ HasL1() bool }); ok {
+		return c.HasL1()
+	}
+	return true
+}
+
+// tombstoneKey is where ReadThroughCache.Delete marks key as deleted.
+func tombstoneKey(key string) string {
+	return NewKey("tombstone").Str(key).String()
+}
+
+// Load fills dest from Loader without touching the cache, e.g. for a client that sent
+// Cache-Control: no-store.
+func (r *ReadThroughCache) Load(ctx context.Context, key string, dest any) error {
+	value, err := r.Loader(ctx, key)
+	if err != nil {
+		return err
+	}
+	return assignLoaded(dest, value)
+}
+
+// CacheQuery returns the cached result of a SQL query, keyed by QueryFingerprint under
+// the "query" namespace. On a miss it calls loader and caches the result for ttl in
+// every level (0 uses the cache defaults). Cache errors are returned, as with
+// ReadThroughCache.
+func CacheQuery[T any](ctx context.Context, cache Cache, query string, args []any, loader func() (T, error), ttl time.Duration) (T, error) {
+	var result T
+	fp, err := QueryFingerprint(query, args...)
+	if err != nil {
+		return result, err
+	}
+	key := NewKey("query").Str(fp).String()
+	opts := CacheOptions{L1TTL: ttl, L2TTL: ttl}
+
+	if err := GetE(ctx, cache, key, &result, opts); !errors.Is(err, ErrCacheMiss) {
+		return result, err
+	}
+
+	if result, err = loader(); err != nil {
+		return result, err
+	}
+	return result, cache.Set(ctx, key, result, opts)
+}
+
+// Versioned is implemented by cached values that carry the version of the row they
+// were loaded from, such as db.User with its updated_at.
+type Versioned interface {
+	CacheVersion() time.Time
+}
+
+// RefreshIfStale compares the version of the cached value of key with currentVersion,
+// typically the row's updated_at from a cheap query, and only when they differ (or the
+// key is not cached) calls loader and caches the result with opts. It returns the
+// cached or loaded value and whether loader ran. Cache errors are returned, as with
+// ReadThroughCache.
+func RefreshIfStale[T Versioned](ctx context.Context, cache Cache, key string, currentVersion time.Time, loader func(ctx context.Context) (T, error), opts CacheOptions) (T, bool, error) {
+	var cached T
+	err := GetE(ctx, cache, key, &cached, opts)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		return cached, false, err
+	}
+	if err == nil && cached.CacheVersion().Equal(currentVersion) {
+		return cached, false, nil
+	}
+
+	loaded, err := loader(ctx)
+	if err != nil {
+		return loaded, false, err
+	}
+	return loaded, true, cache.Set(ctx, key, loaded, opts)
+}
+
+// assignLoaded stores a loaded value in dest, which must point to a type the value is
+// assignable to.
+func assignLoaded(dest, value any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("read-through dest must be a non-nil pointer, got %T", dest)
+	}
+	lv := reflect.ValueOf(value)
+	if !lv.IsValid() || !lv.Type().AssignableTo(rv.Elem().Type()) {
+		return fmt.Errorf("read-through loaded %T, cannot assign to %T", value, dest)
+	}
+	rv.Elem().Set(lv)
+	return nil
+}
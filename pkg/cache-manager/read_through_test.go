@@ -0,0 +1,401 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go-cache-poc/internal/db"
+)
+
+func newUserReadThrough(t *testing.T, loads *sync.Map) (*ReadThroughCache, *memoryRawCache) {
+	t.Helper()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	return &ReadThroughCache{
+		Cache: ml,
+		KeyFn: UserCacheKey,
+		Loader: func(_ context.Context, key string) (any, error) {
+			n, _ := loads.LoadOrStore(key, new(atomic.Int64))
+			n.(*atomic.Int64).Add(1)
+			k, err := ParseKey(key)
+			if err != nil {
+				return nil, err
+			}
+			id, err := k.IntSegment(1)
+			if err != nil {
+				return nil, err
+			}
+			if id < 0 {
+				return nil, errUserMissing
+			}
+			return loadedUser{ID: id, Name: fmt.Sprintf("user-%d", id)}, nil
+		},
+	}, l1
+}
+
+func TestReadThroughCacheLoadsOncePerIDUnderConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var loads sync.Map
+	rt, _ := newUserReadThrough(t, &loads)
+	ctx := context.Background()
+
+	const ids, callers = 5, 20
+	var wg sync.WaitGroup
+	for i := 0; i < ids*callers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			var got loadedUser
+			_, err := rt.GetID(ctx, id, &got)
+			require.NoError(t, err)
+			require.Equal(t, loadedUser{ID: id, Name: fmt.Sprintf("user-%d", id)}, got)
+		}(i % ids)
+	}
+	wg.Wait()
+
+	for id := 0; id < ids; id++ {
+		n, ok := loads.Load(UserCacheKey(id))
+		require.True(t, ok)
+		require.Equal(t, int64(1), n.(*atomic.Int64).Load(), "id %d", id)
+	}
+}
+
+func TestReadThroughCacheReportsCacheHits(t *testing.T) {
+	t.Parallel()
+
+	var loads sync.Map
+	rt, l1 := newUserReadThrough(t, &loads)
+	ctx := context.Background()
+
+	var got loadedUser
+	res, err := rt.GetID(ctx, 7, &got)
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.True(t, l1.has(UserCacheKey(7)))
+
+	res, err = rt.GetID(ctx, 7, &got)
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "user-7", got.Name)
+}
+
+func TestReadThroughCacheReturnsLoaderErrors(t *testing.T) {
+	t.Parallel()
+
+	var loads sync.Map
+	rt, l1 := newUserReadThrough(t, &loads)
+
+	var got loadedUser
+	_, err := rt.GetID(context.Background(), -1, &got)
+	require.ErrorIs(t, err, errUserMissing)
+	require.False(t, l1.has(UserCacheKey(-1)))
+}
+
+func TestReadThroughCacheFallbackOnCacheError(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(failingRawCache{err: errors.New("down")}, failingRawCache{err: errors.New("down")}, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+	rt := &ReadThroughCache{
+		Cache: ml,
+		Loader: func(context.Context, string) (any, error) {
+			return loadedUser{ID: 1, Name: "Ada"}, nil
+		},
+	}
+
+	var got loadedUser
+	_, err = rt.Get(context.Background(), "user:1", &got)
+	require.Error(t, err)
+
+	rt.FallbackOnCacheError = true
+	res, err := rt.Get(context.Background(), "user:1", &got)
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.Equal(t, "Ada", got.Name)
+}
+
+func TestReadThroughCacheRefreshAndLoadSkipCacheRead(t *testing.T) {
+	t.Parallel()
+
+	var loads sync.Map
+	rt, l1 := newUserReadThrough(t, &loads)
+	ctx := context.Background()
+	require.NoError(t, rt.Cache.Set(ctx, UserCacheKey(3), loadedUser{ID: 3, Name: "stale"}, CacheOptions{}))
+
+	var got loadedUser
+	require.NoError(t, rt.Load(ctx, UserCacheKey(3), &got))
+	require.Equal(t, "user-3", got.Name)
+	var cached loadedUser
+	_, err := rt.Cache.Get(ctx, UserCacheKey(3), &cached, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "stale", cached.Name, "Load must not write the cache")
+
+	require.NoError(t, rt.Refresh(ctx, UserCacheKey(3), &got))
+	require.Equal(t, "user-3", got.Name)
+	_, err = rt.Cache.Get(ctx, UserCacheKey(3), &cached, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "user-3", cached.Name)
+	require.True(t, l1.has(UserCacheKey(3)))
+}
+
+func TestCacheQueryLoadsOnceAndCaches(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	var loads atomic.Int64
+	loader := func() ([]loadedUser, error) {
+		loads.Add(1)
+		return []loadedUser{{ID: 1, Name: "Ada"}}, nil
+	}
+
+	const query = "SELECT id, name FROM users WHERE team = $1"
+	for range 3 {
+		users, err := CacheQuery(ctx, ml, query, []any{"core"}, loader, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, []loadedUser{{ID: 1, Name: "Ada"}}, users)
+	}
+	require.Equal(t, int64(1), loads.Load())
+
+	fp, err := QueryFingerprint(query, "core")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, l1.ttl["query:"+fp])
+
+	_, err = CacheQuery(ctx, ml, query, []any{"infra"}, loader, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), loads.Load(), "different args must not share a cache entry")
+}
+
+func TestCacheQueryReturnsLoaderError(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	_, err := CacheQuery(context.Background(), ml, "SELECT 1", nil, func() (int, error) {
+		return 0, errUserMissing
+	}, 0)
+	require.ErrorIs(t, err, errUserMissing)
+}
+
+type versionedUser struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (u versionedUser) CacheVersion() time.Time { return u.UpdatedAt }
+
+func TestRefreshIfStaleSkipsLoaderForCurrentVersion(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	v1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	v2 := v1.Add(time.Second)
+	var loads atomic.Int64
+	loader := func(name string, version time.Time) func(context.Context) (versionedUser, error) {
+		return func(context.Context) (versionedUser, error) {
+			loads.Add(1)
+			return versionedUser{ID: 1, Name: name, UpdatedAt: version}, nil
+		}
+	}
+
+	// A miss loads and caches.
+	got, refreshed, err := RefreshIfStale(ctx, ml, "user:1", v1, loader("Ada", v1), CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, refreshed)
+	require.Equal(t, "Ada", got.Name)
+
+	// The cached copy is current, so the loader is not called.
+	got, refreshed, err = RefreshIfStale(ctx, ml, "user:1", v1, loader("unused", v1), CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, refreshed)
+	require.Equal(t, "Ada", got.Name)
+	require.Equal(t, int64(1), loads.Load())
+
+	// A newer version reloads and replaces the cached copy.
+	got, refreshed, err = RefreshIfStale(ctx, ml, "user:1", v2, loader("Ada Lovelace", v2), CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, refreshed)
+	require.Equal(t, "Ada Lovelace", got.Name)
+
+	var cached versionedUser
+	_, err = ml.Get(ctx, "user:1", &cached, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "Ada Lovelace", cached.Name)
+	require.True(t, cached.UpdatedAt.Equal(v2))
+}
+
+func TestRefreshIfStaleReturnsLoaderError(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	_, refreshed, err := RefreshIfStale(context.Background(), ml, "user:1", time.Now(), func(context.Context) (versionedUser, error) {
+		return versionedUser{}, errUserMissing
+	}, CacheOptions{})
+	require.ErrorIs(t, err, errUserMissing)
+	require.False(t, refreshed)
+	require.False(t, l1.has("user:1"))
+}
+
+func TestReadThroughDeleteServesTombstone(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	store := db.NewMemoryStore(db.User{ID: 1, Name: "Ada"})
+	rt := ReadThroughUserCache(ml, store)
+	ctx := context.Background()
+
+	var user db.User
+	_, err := rt.GetID(ctx, 1, &user)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.GetUserCalls())
+
+	require.NoError(t, store.DeleteUser(ctx, 1))
+	require.NoError(t, rt.Delete(ctx, UserCacheKey(1)))
+	require.False(t, l1.has(UserCacheKey(1)))
+	require.False(t, l2.has(UserCacheKey(1)))
+	require.Equal(t, defaultTombstoneTTL, l2.ttl[tombstoneKey(UserCacheKey(1))])
+
+	for range 2 {
+		_, err = rt.GetID(ctx, 1, &user)
+		require.ErrorIs(t, err, db.ErrUserNotFound)
+	}
+	require.Equal(t, 1, store.GetUserCalls(), "the tombstone must answer without the store")
+}
+
+func TestReadThroughDeleteDuringLoad(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	loading, release := make(chan struct{}), make(chan struct{})
+	rt := &ReadThroughCache{
+		Cache: ml,
+		Loader: func(context.Context, string) (any, error) {
+			close(loading)
+			<-release
+			return loadedUser{ID: 1, Name: "Ada"}, nil // read before the delete
+		},
+		DeletedErr: errUserMissing,
+	}
+	ctx := context.Background()
+
+	errc := make(chan error, 1)
+	go func() {
+		var got loadedUser
+		_, err := rt.Get(ctx, "user:1", &got)
+		errc <- err
+	}()
+	<-loading
+	require.NoError(t, rt.Delete(ctx, "user:1"))
+	close(release)
+
+	require.ErrorIs(t, <-errc, errUserMissing)
+	require.False(t, l1.has("user:1"), "the load must not resurrect the deleted value")
+	require.False(t, l2.has("user:1"))
+}
+
+// pausingRawCache blocks the first Get of key after reading it, until release is closed.
+type pausingRawCache struct {
+	*memoryRawCache
+	key     string
+	read    chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (c *pausingRawCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, ok, err := c.memoryRawCache.Get(ctx, key)
+	if key == c.key {
+		c.once.Do(func() {
+			close(c.read)
+			<-c.release
+		})
+	}
+	return data, ok, err
+}
+
+func TestReadThroughDeleteDuringStaleL2Read(t *testing.T) {
+	t.Parallel()
+
+	l1 := newMemoryRawCache()
+	l2 := &pausingRawCache{memoryRawCache: newMemoryRawCache(), key: "user:1", read: make(chan struct{}), release: make(chan struct{})}
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, WarmupTTL: time.Minute})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1, Name: "Ada"}, CacheOptions{TargetL1: BoolPtr(false)}))
+
+	var loads atomic.Int64
+	rt := &ReadThroughCache{
+		Cache: ml,
+		Loader: func(context.Context, string) (any, error) {
+			loads.Add(1)
+			return nil, errUserMissing
+		},
+		DeletedErr: errUserMissing,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		var got loadedUser
+		_, err := rt.Get(ctx, "user:1", &got)
+		errc <- err
+	}()
+	<-l2.read
+	require.NoError(t, rt.Delete(ctx, "user:1"))
+	close(l2.release)
+
+	require.ErrorIs(t, <-errc, errUserMissing)
+	require.False(t, l1.has("user:1"), "the stale L2 read must not stay in L1")
+	require.Zero(t, loads.Load())
+}
+
+func TestReadThroughCacheTimesMissesByPrefix(t *testing.T) {
+	t.Parallel()
+
+	const loaderDelay = 50 * time.Millisecond
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	rt := &ReadThroughCache{
+		Cache: ml,
+		Loader: func(context.Context, string) (any, error) {
+			time.Sleep(loaderDelay)
+			return "v", nil
+		},
+	}
+	ctx := context.Background()
+
+	var got string
+	res, err := rt.Get(ctx, "user:1", &got)
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.GreaterOrEqual(t, res.Timing.Load, loaderDelay)
+	require.Less(t, res.Timing.Load, loaderDelay+40*time.Millisecond)
+	require.Less(t, res.Timing.Cache, loaderDelay, "cache I/O excludes the loader")
+
+	res, err = rt.Get(ctx, "user:1", &got)
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Zero(t, res.Timing.Load)
+	require.Positive(t, res.Timing.Cache)
+
+	report := ml.LatencyReport()
+	load, cache := report["miss_load:user"], report["miss_cache:user"]
+	require.Equal(t, uint64(1), load.Count, "hits are not counted as misses")
+	require.GreaterOrEqual(t, load.P50, loaderDelay)
+	require.Equal(t, uint64(1), cache.Count)
+	require.Less(t, cache.P50, loaderDelay)
+
+	tracker := NewLatencyTracker(0)
+	rt.Latency = tracker
+	_, err = rt.Get(ctx, "order:1", &got)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), tracker.Report()["miss_load:order"].Count)
+	require.NotContains(t, ml.LatencyReport(), "miss_load:order")
+}
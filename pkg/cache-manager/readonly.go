@@ -0,0 +1,26 @@
+package cache_manager
+
+// readOnlyLevels records the levels MultiLevelConfig.ReadOnly and ReadOnlyL2 protect
+// from Set and Delete.
+type readOnlyLevels struct {
+	l1, l2 bool
+	silent bool // MultiLevelConfig.ReadOnlySilent
+}
+
+// filter drops the read-only levels from the targeted ones. The error is an ErrReadOnly
+// CacheError for the last level dropped, or nil when none was dropped or silent is set.
+func (r readOnlyLevels) filter(op, key string, targetL1, targetL2 bool) (bool, bool, error) {
+	var err error
+	if targetL1 && r.l1 {
+		targetL1 = false
+		err = &CacheError{Op: op, Level: LevelL1, Key: key, Cause: ErrReadOnly}
+	}
+	if targetL2 && r.l2 {
+		targetL2 = false
+		err = &CacheError{Op: op, Level: LevelL2, Key: key, Cause: ErrReadOnly}
+	}
+	if r.silent {
+		err = nil
+	}
+	return targetL1, targetL2, err
+}
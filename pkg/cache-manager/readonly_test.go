@@ -0,0 +1,90 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyL2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{ReadOnlyL2: true})
+	require.NoError(t, l2.Set(ctx, "shared", []byte(`"v"`), time.Hour))
+
+	// Set writes L1 only; an L2-only Set is a no-op.
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}))
+	require.True(t, l1.has("k"))
+	require.False(t, l2.has("k"))
+	require.ErrorIs(t, ml.Set(ctx, "k2", "v", CacheOptions{TargetL1: BoolPtr(false)}), ErrReadOnly)
+	require.False(t, l2.has("k2"))
+
+	// Get reads L2 and warms L1 without touching the L2 TTL.
+	var got string
+	res, err := ml.Get(ctx, "shared", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+	require.True(t, l1.has("shared"))
+	require.Equal(t, time.Hour, l2.ttl["shared"])
+
+	// Delete clears L1 and reports the skipped L2.
+	require.ErrorIs(t, ml.Delete(ctx, "shared"), ErrReadOnly)
+	require.False(t, l1.has("shared"))
+	require.True(t, l2.has("shared"))
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{ReadOnly: true})
+	require.NoError(t, l2.Set(ctx, "shared", []byte(`"v"`), time.Hour))
+
+	require.ErrorIs(t, ml.Set(ctx, "k", "v", CacheOptions{}), ErrReadOnly)
+	require.False(t, l1.has("k"))
+	require.False(t, l2.has("k"))
+
+	var got string
+	res, err := ml.Get(ctx, "shared", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.True(t, l1.has("shared"), "warmup into L1 still works")
+
+	require.ErrorIs(t, ml.Delete(ctx, "shared"), ErrReadOnly)
+	require.True(t, l1.has("shared"))
+	require.True(t, l2.has("shared"))
+}
+
+func TestReadOnlySilent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, cfg := range []MultiLevelConfig{
+		{ReadOnly: true, ReadOnlySilent: true},
+		{ReadOnlyL2: true, ReadOnlySilent: true},
+	} {
+		ml, _, l2 := newTestMultiLevelCache(t, cfg)
+		require.NoError(t, l2.Set(ctx, "shared", []byte(`"v"`), time.Hour))
+
+		require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{TargetL1: BoolPtr(false)}))
+		require.False(t, l2.has("k"))
+		require.NoError(t, ml.Delete(ctx, "shared"))
+		require.True(t, l2.has("shared"))
+	}
+}
+
+func TestReadOnlyValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, ReadOnly: true})
+	require.ErrorIs(t, err, ErrModeMismatch)
+	_, err = NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, ReadOnlyL2: true})
+	require.ErrorIs(t, err, ErrModeMismatch)
+
+	ml, err := NewMultiLevelCache(nil, newMemoryRawCache(), JSONSerializer{}, MultiLevelConfig{Mode: ModeL2Only, ReadOnly: true})
+	require.NoError(t, err, "an L2-only replica still reads")
+	require.ErrorIs(t, ml.Set(context.Background(), "k", "v", CacheOptions{}), ErrReadOnly)
+}
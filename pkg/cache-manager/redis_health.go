@@ -0,0 +1,126 @@
+package cache_manager
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultPingInterval is used when RedisCache.PingInterval is zero.
+const defaultPingInterval = 5 * time.Second
+
+// ConnectionNotifier is implemented by raw caches that report lost and restored
+// connections, e.g. a RedisCache with a running monitor. NewMultiLevelCache subscribes to
+// its L2 and skips L2 while it is disconnected.
+type ConnectionNotifier interface {
+	OnDisconnect(fn func(err error))
+	OnReconnect(fn func())
+}
+
+// redisMonitor holds the health monitor state of a RedisCache.
+type redisMonitor struct {
+	mu           sync.Mutex
+	onDisconnect []func(err error)
+	onReconnect  []func()
+	cancel       context.CancelFunc // nil while stopped
+	done         chan struct{}
+}
+
+// OnDisconnect registers fn to run when a monitor ping fails after Redis was reachable.
+// Callbacks run on the monitor goroutine and must not block.
+func (r *RedisCache) OnDisconnect(fn func(err error)) {
+	r.monitor.mu.Lock()
+	defer r.monitor.mu.Unlock()
+	r.monitor.onDisconnect = append(r.monitor.onDisconnect, fn)
+}
+
+// OnReconnect registers fn to run when a monitor ping succeeds after a disconnect.
+// Callbacks run on the monitor goroutine and must not block.
+func (r *RedisCache) OnReconnect(fn func()) {
+	r.monitor.mu.Lock()
+	defer r.monitor.mu.Unlock()
+	r.monitor.onReconnect = append(r.monitor.onReconnect, fn)
+}
+
+// StartMonitor pings Redis every PingInterval in a background goroutine until ctx is
+// done or StopMonitor is called. Redis is assumed reachable at start, so the first failed
+// ping reports a disconnect. Calling it while a monitor is running does nothing.
+func (r *RedisCache) StartMonitor(ctx context.Context) {
+	r.monitor.mu.Lock()
+	defer r.monitor.mu.Unlock()
+	if r.monitor.cancel != nil {
+		return
+	}
+
+	interval := r.PingInterval
+	if interval <= 0 {
+		interval = defaultPingInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	r.monitor.cancel, r.monitor.done = cancel, done
+	go r.monitorLoop(ctx, interval, done)
+}
+
+// StopMonitor stops the monitor started by StartMonitor and waits for it to exit.
+func (r *RedisCache) StopMonitor() {
+	r.monitor.mu.Lock()
+	cancel, done := r.monitor.cancel, r.monitor.done
+	r.monitor.cancel, r.monitor.done = nil, nil
+	r.monitor.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (r *RedisCache) monitorLoop(ctx context.Context, interval time.Duration, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	connected := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := r.client.Ping(pingCtx).Err()
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch {
+		case err != nil && connected:
+			connected = false
+			r.notifyDisconnect(err)
+		case err == nil && !connected:
+			connected = true
+			r.notifyReconnect()
+		}
+	}
+}
+
+func (r *RedisCache) notifyDisconnect(err error) {
+	r.monitor.mu.Lock()
+	callbacks := slices.Clone(r.monitor.onDisconnect)
+	r.monitor.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(err)
+	}
+}
+
+func (r *RedisCache) notifyReconnect() {
+	r.monitor.mu.Lock()
+	callbacks := slices.Clone(r.monitor.onReconnect)
+	r.monitor.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+}
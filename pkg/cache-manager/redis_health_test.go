@@ -0,0 +1,92 @@
+package cache_manager
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+func newMonitoredRedisCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+		MaxRetries:               -1,
+		DialTimeout:              50 * time.Millisecond,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	rc, err := NewRedisCache(client)
+	require.NoError(t, err)
+	rc.PingInterval = 10 * time.Millisecond
+	return rc, mr
+}
+
+func TestRedisHealthMonitorReportsRestart(t *testing.T) {
+	t.Parallel()
+
+	rc, mr := newMonitoredRedisCache(t)
+	var disconnects, reconnects atomic.Int64
+	rc.OnDisconnect(func(error) { disconnects.Add(1) })
+	rc.OnReconnect(func() { reconnects.Add(1) })
+
+	rc.StartMonitor(context.Background())
+	rc.StartMonitor(context.Background()) // already running: no second goroutine
+	t.Cleanup(rc.StopMonitor)
+
+	mr.Close()
+	require.Eventually(t, func() bool { return disconnects.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+	require.Zero(t, reconnects.Load())
+
+	require.NoError(t, mr.Restart())
+	require.Eventually(t, func() bool { return reconnects.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+	require.Equal(t, int64(1), disconnects.Load())
+}
+
+func TestMultiLevelCacheSkipsL2WhileDisconnected(t *testing.T) {
+	t.Parallel()
+
+	rc, mr := newMonitoredRedisCache(t)
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, rc, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+
+	rc.StartMonitor(ctx)
+	t.Cleanup(rc.StopMonitor)
+
+	mr.Close()
+	require.Eventually(t, func() bool { return ml.L2State() == L2Degraded }, 2*time.Second, 5*time.Millisecond)
+
+	// L1 still serves; a miss is a plain miss instead of a Redis connection error.
+	var got string
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	res, err = ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+
+	require.NoError(t, mr.Restart())
+	require.Eventually(t, func() bool { return ml.L2State() == L2Normal }, 2*time.Second, 5*time.Millisecond)
+	require.NoError(t, l1.Delete(ctx, "user:1"))
+	res, err = ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found, "L2 reads resume after reconnect")
+}
+
+func TestRedisStopMonitorWithoutStart(t *testing.T) {
+	t.Parallel()
+
+	rc, _ := newMonitoredRedisCache(t)
+	rc.StopMonitor()
+}
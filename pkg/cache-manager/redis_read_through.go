@@ -0,0 +1,117 @@
+package cache_manager
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// BytesLoader fetches the payload for a key missing from Redis.
+type BytesLoader func(ctx context.Context, key string) ([]byte, error)
+
+// ReadThroughRedisCache is a Redis-only read-through cache for services without an L1:
+// a miss calls the loader and stores its result in Redis. Concurrent misses for a key
+// share one loader call, within a process through singleflight and across processes
+// through a SETNX lock that the other callers wait on.
+type ReadThroughRedisCache struct {
+	redis      *RedisCache
+	loader     BytesLoader
+	defaultTTL time.Duration
+
+	// LockTTL bounds how long a loader holds a key's lock, so a crashed process does not
+	// block the key forever. Default 10s.
+	LockTTL time.Duration
+	// PollInterval is how often a caller waiting on another process's load re-reads the
+	// key. Default 10ms.
+	PollInterval time.Duration
+
+	loads singleflight.Group
+}
+
+const (
+	defaultReadThroughLockTTL      = 10 * time.Second
+	defaultReadThroughPollInterval = 10 * time.Millisecond
+)
+
+// NewReadThroughRedisCache wraps r with loader; loaded payloads are stored for defaultTTL
+// (0 = no expiry).
+func NewReadThroughRedisCache(r *RedisCache, loader BytesLoader, defaultTTL time.Duration) *ReadThroughRedisCache {
+	return &ReadThroughRedisCache{redis: r, loader: loader, defaultTTL: defaultTTL}
+}
+
+// Get returns the cached payload of key, or on a miss loads, stores and returns it.
+// Loader errors are returned unchanged.
+func (c *ReadThroughRedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if c == nil || c.loader == nil {
+		return nil, &CacheError{Op: "get", Level: LevelL2, Key: key, Cause: ErrNotInitialized}
+	}
+	data, ok, err := c.redis.Get(ctx, key)
+	if err != nil || ok {
+		return data, err
+	}
+	v, err, _ := c.loads.Do(key, func() (any, error) {
+		return c.load(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// load fills key from the loader once the lock is taken, or waits for the process that
+// holds it. If that process gives up without storing a value, the lock is retried.
+func (c *ReadThroughRedisCache) load(ctx context.Context, key string) ([]byte, error) {
+	lock := NewKey("lock").Str(key).String()
+	lockTTL := c.LockTTL
+	if lockTTL <= 0 {
+		lockTTL = defaultReadThroughLockTTL
+	}
+	poll := c.PollInterval
+	if poll <= 0 {
+		poll = defaultReadThroughPollInterval
+	}
+
+	for {
+		won, err := c.redis.SetNX(ctx, lock, []byte("1"), lockTTL)
+		if err != nil {
+			return nil, err
+		}
+		if won {
+			return c.loadLocked(ctx, key, lock)
+		}
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		data, ok, err := c.redis.Get(ctx, key)
+		if err != nil || ok {
+			return data, err
+		}
+	}
+}
+
+// loadLocked runs the loader while holding lock and releases it afterwards.
+func (c *ReadThroughRedisCache) loadLocked(ctx context.Context, key, lock string) ([]byte, error) {
+	defer func() {
+		// Release with a fresh context so a canceled caller does not leave the lock behind
+		_ = c.redis.Delete(context.WithoutCancel(ctx), lock)
+	}()
+
+	// The previous holder may have stored the value between our miss and the lock.
+	if data, ok, err := c.redis.Get(ctx, key); err != nil || ok {
+		return data, err
+	}
+	data, err := c.loader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.redis.Set(ctx, key, data, c.defaultTTL); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
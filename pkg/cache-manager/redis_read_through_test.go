@@ -0,0 +1,119 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadThroughRedisCacheLoadsOncePerKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l2, mr := newRenameTestRedis(t)
+	var mu sync.Mutex
+	calls := map[string]int{}
+	loader := func(_ context.Context, key string) ([]byte, error) {
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		return []byte("value of " + key), nil
+	}
+	// Two instances stand in for two processes: they share Redis but not singleflight.
+	caches := []*ReadThroughRedisCache{
+		NewReadThroughRedisCache(l2, loader, time.Minute),
+		NewReadThroughRedisCache(l2, loader, time.Minute),
+	}
+
+	keys := []string{"a", "b", "c"}
+	var wg sync.WaitGroup
+	for i := range 10 {
+		for _, key := range keys {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				data, err := caches[i%2].Get(ctx, key)
+				require.NoError(t, err)
+				require.Equal(t, "value of "+key, string(data))
+			}()
+		}
+	}
+	wg.Wait()
+
+	require.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, calls)
+	require.Equal(t, time.Minute, mr.TTL("a"))
+	require.False(t, mr.Exists(NewKey("lock").Str("a").String()), "the lock is released")
+}
+
+func TestReadThroughRedisCacheHitSkipsLoader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l2, _ := newRenameTestRedis(t)
+	require.NoError(t, l2.Set(ctx, "k", []byte("cached"), 0))
+	c := NewReadThroughRedisCache(l2, func(context.Context, string) ([]byte, error) {
+		t.Fatal("loader called on a hit")
+		return nil, nil
+	}, time.Minute)
+
+	data, err := c.Get(ctx, "k")
+	require.NoError(t, err)
+	require.Equal(t, "cached", string(data))
+}
+
+func TestReadThroughRedisCacheLoaderError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l2, mr := newRenameTestRedis(t)
+	errNotFound := errors.New("not found")
+	var calls atomic.Int32
+	c := NewReadThroughRedisCache(l2, func(_ context.Context, key string) ([]byte, error) {
+		if calls.Add(1) == 1 {
+			return nil, errNotFound
+		}
+		return []byte(fmt.Sprint("retry ", key)), nil
+	}, time.Minute)
+
+	_, err := c.Get(ctx, "k")
+	require.ErrorIs(t, err, errNotFound)
+	require.False(t, mr.Exists("k"))
+
+	data, err := c.Get(ctx, "k")
+	require.NoError(t, err, "a failed load releases the lock")
+	require.Equal(t, "retry k", string(data))
+}
+
+func TestReadThroughRedisCacheWaitsForOtherProcess(t *testing.T) {
+	t.Parallel()
+
+	l2, mr := newRenameTestRedis(t)
+	lock := NewKey("lock").Str("k").String()
+	require.NoError(t, mr.Set(lock, "1"))
+	c := NewReadThroughRedisCache(l2, func(context.Context, string) ([]byte, error) {
+		return []byte("loaded here"), nil
+	}, time.Minute)
+	c.PollInterval = time.Millisecond
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = mr.Set("k", "loaded elsewhere")
+		mr.Del(lock)
+	}()
+	data, err := c.Get(context.Background(), "k")
+	require.NoError(t, err)
+	require.Equal(t, "loaded elsewhere", string(data))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.NoError(t, mr.Set(NewKey("lock").Str("held").String(), "1"))
+	_, err = c.Get(ctx, "held")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,152 @@
+package cache_manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+// Renamer is implemented by raw caches that can move an entry to a new key, keeping its
+// value and expiry and replacing any entry under newKey. It reports false when oldKey
+// does not exist.
+type Renamer interface {
+	Rename(ctx context.Context, oldKey, newKey string) (bool, error)
+}
+
+var (
+	_ Renamer = (*RedisCache)(nil)
+	_ Renamer = (*BigCache)(nil)
+)
+
+// Rename moves the entry under oldKey to newKey with RENAME, which is atomic and keeps
+// the TTL.
+func (r *RedisCache) Rename(ctx context.Context, oldKey, newKey string) (bool, error) {
+	done, err := r.begin("rename", oldKey)
+	if err != nil {
+		return false, err
+	}
+	defer done()
+	if err := r.client.Rename(ctx, oldKey, newKey).Err(); err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			return false, nil
+		}
+		return false, &CacheError{Op: "rename", Level: LevelL2, Key: oldKey, Cause: err}
+	}
+	return true, nil
+}
+
+// Rename moves the entry under oldKey to newKey, keeping its expiry and priority.
+// bigcache has no rename, so it is a read, write and delete under the write locks of
+// both keys; a writer that does not take those locks (any writer unless CopyOnRead is
+// set) can still interleave.
+func (b *BigCache) Rename(ctx context.Context, oldKey, newKey string) (bool, error) {
+	release := b.rlock()
+	if release == nil {
+		return false, &CacheError{Op: "rename", Level: LevelL1, Key: oldKey, Cause: ErrNotInitialized}
+	}
+	defer release()
+	if oldKey == newKey {
+		_, _, found, err := b.GetWithPriority(ctx, oldKey)
+		return found, err
+	}
+
+	// Lock in a fixed order so concurrent renames of the same pair cannot deadlock
+	first, second := oldKey, newKey
+	if second < first {
+		first, second = second, first
+	}
+	unlockFirst := b.writeLocks.Lock(first)
+	defer unlockFirst()
+	unlockSecond := b.writeLocks.Lock(second)
+	defer unlockSecond()
+
+	raw, err := b.cache.Get(oldKey)
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, &CacheError{Op: "rename", Level: LevelL1, Key: oldKey, Cause: err}
+	}
+	now := b.now()
+	if _, _, ok := decodeEntry(raw, now); !ok {
+		if b.removable(raw, now) {
+			_ = b.delete(oldKey)
+		}
+		return false, nil
+	}
+
+	if err := b.set(newKey, bytes.Clone(raw)); err != nil {
+		return false, &CacheError{Op: "rename", Level: LevelL1, Key: newKey, Cause: err}
+	}
+	if err := b.delete(oldKey); err != nil && !errors.Is(err, bigcache.ErrEntryNotFound) {
+		return false, &CacheError{Op: "rename", Level: LevelL1, Key: oldKey, Cause: err}
+	}
+	return true, nil
+}
+
+// Rename moves oldKey to newKey in every configured level, replacing any entry under
+// newKey. L2 is renamed first; a level that does not hold oldKey drops its newKey entry
+// so it cannot shadow the renamed one. When L2 was renamed but L1 failed, the returned
+// error says so and the L1 entries are left as they were. It returns ErrKeyNotFound
+// when no level holds oldKey.
+//
+// Both levels must implement Renamer, as RedisCache and BigCache do. Values chunked by
+// L2ChunkThreshold cannot be renamed, because their chunk keys derive from the key.
+func (m *MultiLevelCache) Rename(ctx context.Context, oldKey, newKey string) error {
+	if m == nil {
+		return &CacheError{Op: "rename", Key: oldKey, Cause: ErrNotInitialized}
+	}
+	defer m.observeLatency("rename", time.Now())
+	storedOld, storedNew := m.storeKey(oldKey), m.storeKey(newKey)
+
+	levels := make([]namedLevel, 0, 2)
+	if m.l2 != nil {
+		levels = append(levels, namedLevel{name: LevelL2, cache: m.l2})
+	}
+	if m.l1 != nil {
+		levels = append(levels, namedLevel{name: LevelL1, cache: m.l1})
+	}
+	for _, lvl := range levels {
+		if _, ok := lvl.cache.(Renamer); !ok {
+			return &CacheError{Op: "rename", Level: lvl.name, Key: oldKey, Cause: fmt.Errorf("%s cannot rename keys: %w", lvl.name, errors.ErrUnsupported)}
+		}
+	}
+	if m.l2 != nil && m.l2ChunkThreshold > 0 {
+		if data, ok, err := m.l2.Get(ctx, storedOld); err != nil {
+			return wrapError("rename", LevelL2, oldKey, err)
+		} else if ok && bytes.HasPrefix(data, chunkManifestMagic) {
+			return &CacheError{Op: "rename", Level: LevelL2, Key: oldKey, Cause: errors.New("chunked values cannot be renamed")}
+		}
+	}
+
+	m.changes.forget(storedOld)
+	m.changes.forget(storedNew)
+
+	var renamed []string
+	for _, lvl := range levels {
+		found, err := lvl.cache.(Renamer).Rename(ctx, storedOld, storedNew)
+		if err == nil && !found {
+			err = lvl.cache.Delete(ctx, storedNew)
+		}
+		if err != nil {
+			m.emit(ctx, "rename", storedOld, lvl.name, EventError)
+			if len(renamed) > 0 {
+				err = fmt.Errorf("renamed in %s but not in %s: %w", strings.Join(renamed, ", "), lvl.name, err)
+			}
+			return wrapError("rename", lvl.name, oldKey, err)
+		}
+		m.emit(ctx, "rename", storedOld, lvl.name, EventOK)
+		if found {
+			renamed = append(renamed, lvl.name)
+		}
+	}
+	if len(renamed) == 0 {
+		return &CacheError{Op: "rename", Key: oldKey, Cause: ErrKeyNotFound}
+	}
+	return nil
+}
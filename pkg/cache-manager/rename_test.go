@@ -0,0 +1,149 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/allegro/bigcache/v3"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+func newRenameTestRedis(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+	return l2, mr
+}
+
+func newRenameTestCache(t *testing.T, l1 RawCache) (*MultiLevelCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	if l1 == nil {
+		bcConfig := bigcache.DefaultConfig(time.Hour)
+		bcConfig.Verbose = false
+		bc, err := NewBigCache(context.Background(), BigCacheConfig{Config: bcConfig})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = bc.Close() })
+		l1 = bc
+	}
+	l2, mr := newRenameTestRedis(t)
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, InstanceName: "users"})
+	require.NoError(t, err)
+	return ml, mr
+}
+
+func TestRenameMovesValueInBothLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, mr := newRenameTestCache(t, nil)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1, Name: "Ada"}, CacheOptions{L1TTL: time.Minute, L2TTL: time.Hour}))
+
+	require.NoError(t, ml.Rename(ctx, "user:1", "user:1001"))
+
+	var got loadedUser
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found, "old key must be gone")
+
+	res, err = ml.Get(ctx, "user:1001", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL1}, res)
+	require.Equal(t, loadedUser{ID: 1, Name: "Ada"}, got)
+
+	require.False(t, mr.Exists("users:user:1"))
+	require.Equal(t, time.Hour, mr.TTL("users:user:1001"), "RENAME keeps the L2 TTL")
+	ttl, found, err := ml.l1.(*BigCache).TTL(ctx, "users:user:1001")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.InDelta(t, time.Minute, ttl, float64(time.Second), "the L1 entry keeps its expiry")
+}
+
+func TestRenameDropsStaleTargetInColdLevel(t *testing.T) {
+	t.Parallel()
+
+	ml, _ := newRenameTestCache(t, nil)
+	ctx := context.Background()
+	l2Only := CacheOptions{TargetL1: BoolPtr(false), TargetL2: BoolPtr(true)}
+	l1Only := CacheOptions{TargetL1: BoolPtr(true), TargetL2: BoolPtr(false)}
+	require.NoError(t, ml.Set(ctx, "old", "fresh", l2Only))
+	require.NoError(t, ml.Set(ctx, "new", "stale", l1Only))
+
+	require.NoError(t, ml.Rename(ctx, "old", "new"))
+
+	var got string
+	res, err := ml.Get(ctx, "new", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level, "the stale L1 entry must not shadow the renamed value")
+	require.Equal(t, "fresh", got)
+}
+
+func TestRenameMissingKey(t *testing.T) {
+	t.Parallel()
+
+	ml, _ := newRenameTestCache(t, nil)
+	err := ml.Rename(context.Background(), "nope", "other")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestBigCacheRenameKeepsEntriesInStaleGrace(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{StaleGrace: time.Minute})
+	ctx := context.Background()
+	require.NoError(t, bc.Set(ctx, "old", []byte("v"), time.Second))
+
+	clock.Advance(30 * time.Second)
+	renamed, err := bc.Rename(ctx, "old", "new")
+	require.NoError(t, err)
+	require.False(t, renamed, "expired entries are not renamed")
+
+	data, found, err := bc.GetStale(ctx, "old")
+	require.NoError(t, err)
+	require.True(t, found, "Rename must not delete an entry inside the grace window")
+	require.Equal(t, []byte("v"), data)
+}
+
+// brokenRenamer is an L1 whose renames always fail.
+type brokenRenamer struct{ *memoryRawCache }
+
+func (brokenRenamer) Rename(context.Context, string, string) (bool, error) {
+	return false, errors.New("l1 rename failed")
+}
+
+func TestRenameReportsPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	l1 := brokenRenamer{newMemoryRawCache()}
+	ml, mr := newRenameTestCache(t, l1)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "old", "v", CacheOptions{}))
+
+	err := ml.Rename(ctx, "old", "new")
+	var ce *CacheError
+	require.ErrorAs(t, err, &ce)
+	require.Equal(t, LevelL1, ce.Level)
+	require.ErrorContains(t, err, "renamed in L2 but not in L1")
+	require.True(t, mr.Exists("users:new"))
+	require.True(t, l1.has("users:old"))
+}
+
+func TestRenameNeedsRenamerLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.ErrorIs(t, ml.Rename(context.Background(), "a", "b"), errors.ErrUnsupported)
+}
@@ -0,0 +1,22 @@
+package cache_manager
+
+import "context"
+
+// requestIDKey is the context key WithRequestID stores the request ID under.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id, which the cache adds to its logs and
+// events for operations run with that context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or "". It is the
+// default MultiLevelConfig.RequestID.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
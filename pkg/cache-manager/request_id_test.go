@@ -0,0 +1,50 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDInLogsAndEvents(t *testing.T) {
+	t.Parallel()
+
+	ml, buf := newLogSamplerTestCache(t, 1)
+	ctx := WithRequestID(context.Background(), "req-42")
+	require.NoError(t, ml.Set(ctx, "user:1", 1, CacheOptions{}))
+
+	require.Contains(t, buf.String(), `msg="cache set l1 write" key=user:1`)
+	require.Contains(t, buf.String(), "request_id=req-42")
+
+	select {
+	case ev := <-ml.Events():
+		require.Equal(t, "req-42", ev.RequestID)
+	case <-time.After(time.Second):
+		t.Fatal("no event emitted")
+	}
+
+	buf.Reset()
+	require.NoError(t, ml.Delete(context.Background(), "user:1"))
+	require.NotContains(t, buf.String(), "request_id")
+}
+
+func TestRequestIDExtractorFromConfig(t *testing.T) {
+	t.Parallel()
+
+	type traceKey struct{}
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{
+		Mode: ModeL1Only,
+		RequestID: func(ctx context.Context) string {
+			id, _ := ctx.Value(traceKey{}).(string)
+			return id
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-7")
+	require.NoError(t, ml.Set(ctx, "k", 1, CacheOptions{}))
+	ev := <-ml.Events()
+	require.Equal(t, "trace-7", ev.RequestID)
+}
@@ -1,6 +1,12 @@
 package cache_manager
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
 
 // Serializer defines marshaling boundaries for cache payloads.
 type Serializer interface {
@@ -19,3 +25,30 @@ func (JSONSerializer) Unmarshal(data []byte, dest any) error {
 	return json.Unmarshal(data, dest)
 }
 
+// MsgpackSerializer implements Serializer using MessagePack, which is typically
+// smaller and faster to decode than JSON for struct payloads.
+type MsgpackSerializer struct{}
+
+func (MsgpackSerializer) Marshal(value any) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (MsgpackSerializer) Unmarshal(data []byte, dest any) error {
+	return msgpack.Unmarshal(data, dest)
+}
+
+// GobSerializer implements Serializer using encoding/gob. Payloads are Go-only, so it
+// suits L1 while L2 keeps a portable format.
+type GobSerializer struct{}
+
+func (GobSerializer) Marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobSerializer) Unmarshal(data []byte, dest any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dest)
+}
@@ -0,0 +1,85 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type serializerTestUser struct {
+	ID   int
+	Name string
+}
+
+func TestMultiLevelCacheSplitSerializersEncodePerLevel(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{L1Serializer: GobSerializer{}, L2Serializer: JSONSerializer{}})
+	ctx := context.Background()
+	user := serializerTestUser{ID: 7, Name: "Ada"}
+
+	require.NoError(t, ml.Set(ctx, "user:7", user, CacheOptions{}))
+
+	raw, found, err := l2.Get(ctx, "user:7")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, json.Valid(raw), "L2 should hold JSON")
+
+	raw, found, err = l1.Get(ctx, "user:7")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.False(t, json.Valid(raw), "L1 should hold gob")
+
+	var got serializerTestUser
+	res, err := ml.Get(ctx, "user:7", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, user, got)
+}
+
+func TestMultiLevelCacheSplitSerializersWarmupReencodes(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{L1Serializer: GobSerializer{}, L2Serializer: JSONSerializer{}})
+	ctx := context.Background()
+	user := serializerTestUser{ID: 9, Name: "Grace"}
+
+	data, err := json.Marshal(user)
+	require.NoError(t, err)
+	require.NoError(t, l2.Set(ctx, "user:9", data, time.Minute))
+
+	var got serializerTestUser
+	res, err := ml.Get(ctx, "user:9", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, user, got)
+
+	raw, found, err := l1.Get(ctx, "user:9")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	var warmed serializerTestUser
+	require.NoError(t, GobSerializer{}.Unmarshal(raw, &warmed))
+	require.Equal(t, user, warmed)
+
+	// Served from L1 now, decoded with gob.
+	require.NoError(t, l2.Delete(ctx, "user:9"))
+	got = serializerTestUser{}
+	res, err = ml.Get(ctx, "user:9", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, user, got)
+}
+
+func TestNewMultiLevelCacheRequiresSerializerPerLevel(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMultiLevelCache(newMemoryRawCache(), newMemoryRawCache(), nil, MultiLevelConfig{
+		Mode:         ModeBothLevels,
+		L1Serializer: GobSerializer{},
+	})
+	require.ErrorIs(t, err, ErrSerializerMissing)
+}
@@ -0,0 +1,54 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// KVPair is one entry for SetMany.
+type KVPair struct {
+	Key   string
+	Value any
+}
+
+// KV returns the KVPair key, value.
+func KV(key string, value any) KVPair {
+	return KVPair{Key: key, Value: value}
+}
+
+// SetMulti stores every entry with Set and the same options, in sorted key order. A
+// failed entry does not stop the others; the failures are returned joined, each a
+// *CacheError naming its key.
+func (m *MultiLevelCache) SetMulti(ctx context.Context, entries map[string]any, opts CacheOptions) error {
+	if m == nil {
+		return &CacheError{Op: "set", Cause: ErrNotInitialized}
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := m.Set(ctx, key, entries[key], opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetMany is SetMulti for a handful of entries written inline:
+//
+//	cache.SetMany(ctx, opts, cache_manager.KV("user:1", u1), cache_manager.KV("user:2", u2))
+//
+// When a key repeats, its last pair wins.
+func (m *MultiLevelCache) SetMany(ctx context.Context, opts CacheOptions, pairs ...KVPair) error {
+	entries := make(map[string]any, len(pairs))
+	for _, p := range pairs {
+		entries[p.Key] = p.Value
+	}
+	return m.SetMulti(ctx, entries, opts)
+}
@@ -0,0 +1,60 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetManyWritesEveryPairToTargetedLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, ml.SetMany(ctx, CacheOptions{},
+		KV("user:1", loadedUser{ID: 1, Name: "Ada"}),
+		KV("user:2", loadedUser{ID: 2, Name: "Grace"}),
+	))
+	for _, key := range []string{"user:1", "user:2"} {
+		require.True(t, l1.has(key), key)
+		require.True(t, l2.has(key), key)
+	}
+	var got loadedUser
+	res, err := ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, loadedUser{ID: 2, Name: "Grace"}, got)
+
+	require.NoError(t, ml.SetMany(ctx, CacheOptions{TargetL2: BoolPtr(false)}, KV("user:3", 3), KV("user:3", 4)))
+	require.True(t, l1.has("user:3"))
+	require.False(t, l2.has("user:3"))
+	var n int
+	_, err = ml.Get(ctx, "user:3", &n, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 4, n, "the last pair for a key wins")
+}
+
+func TestSetMultiContinuesPastFailures(t *testing.T) {
+	t.Parallel()
+
+	errFull := errors.New("full")
+	l1 := &observingRawCache{memoryRawCache: newMemoryRawCache(), onSet: func(key string) error {
+		if key == "b" {
+			return errFull
+		}
+		return nil
+	}}
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	err = ml.SetMulti(context.Background(), map[string]any{"a": 1, "b": 2, "c": 3}, CacheOptions{})
+	require.ErrorIs(t, err, errFull)
+	var cerr *CacheError
+	require.ErrorAs(t, err, &cerr)
+	require.Equal(t, "b", cerr.Key)
+	require.True(t, l1.has("a"))
+	require.True(t, l1.has("c"))
+}
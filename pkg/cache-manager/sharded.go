@@ -0,0 +1,199 @@
+package cache_manager
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// shardVirtualNodes is the number of points each shard gets on the hash ring; more points
+// spread the keys more evenly.
+const shardVirtualNodes = 160
+
+// ShardedMultiLevelCache spreads keys over several MultiLevelCaches by consistent hashing
+// on the key, e.g. one BigCache per partition to keep each heap small, or to try out
+// distribution strategies. Each key lives in exactly one shard; adding a shard to a new
+// ring moves only about 1/N of the keys.
+type ShardedMultiLevelCache struct {
+	shards []*MultiLevelCache
+	ring   []ringPoint // sorted by hash
+}
+
+type ringPoint struct {
+	hash  uint64
+	shard int
+}
+
+// NewShardedCache returns a cache routing keys over shards. The order of shards matters:
+// the same slice always maps a key to the same shard.
+func NewShardedCache(shards []*MultiLevelCache) (*ShardedMultiLevelCache, error) {
+	if len(shards) == 0 {
+		return nil, &CacheError{Op: "new", Cause: errors.New("at least one shard is required")}
+	}
+	ring := make([]ringPoint, 0, len(shards)*shardVirtualNodes)
+	for i, shard := range shards {
+		if shard == nil {
+			return nil, &CacheError{Op: "new", Cause: fmt.Errorf("shard %d is nil", i)}
+		}
+		for v := range shardVirtualNodes {
+			ring = append(ring, ringPoint{hash: ringHash(strconv.Itoa(i) + "#" + strconv.Itoa(v)), shard: i})
+		}
+	}
+	slices.SortFunc(ring, func(a, b ringPoint) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.shard, b.shard))
+	})
+	return &ShardedMultiLevelCache{shards: shards, ring: ring}, nil
+}
+
+// ringHash is FNV-1a finished with the splitmix64 mixer, since plain FNV of keys that
+// differ only in their last bytes clusters on the ring.
+func ringHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// ShardIndex returns the index in the shards passed to NewShardedCache that owns key.
+func (s *ShardedMultiLevelCache) ShardIndex(key string) int {
+	h := ringHash(key)
+	i, _ := slices.BinarySearchFunc(s.ring, h, func(p ringPoint, h uint64) int {
+		return cmp.Compare(p.hash, h)
+	})
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.ring[i].shard
+}
+
+// Shard returns the cache that owns key.
+func (s *ShardedMultiLevelCache) Shard(key string) *MultiLevelCache {
+	return s.shards[s.ShardIndex(key)]
+}
+
+// Shards returns the caches passed to NewShardedCache.
+func (s *ShardedMultiLevelCache) Shards() []*MultiLevelCache {
+	return slices.Clone(s.shards)
+}
+
+// Get reads key from its shard.
+func (s *ShardedMultiLevelCache) Get(ctx context.Context, key string, dest any, opts CacheOptions) (CacheGetResult, error) {
+	return s.Shard(key).Get(ctx, key, dest, opts)
+}
+
+// Set writes key to its shard.
+func (s *ShardedMultiLevelCache) Set(ctx context.Context, key string, value any, opts CacheOptions) error {
+	return s.Shard(key).Set(ctx, key, value, opts)
+}
+
+// Delete removes key from its shard.
+func (s *ShardedMultiLevelCache) Delete(ctx context.Context, key string) error {
+	return s.Shard(key).Delete(ctx, key)
+}
+
+// GetMulti reads every key of dests into its destination, one goroutine per shard
+// involved, and returns the result of each key. A failed key does not stop the others;
+// the failures are returned joined and the key is left out of the results.
+func (s *ShardedMultiLevelCache) GetMulti(ctx context.Context, dests map[string]any, opts CacheOptions) (map[string]CacheGetResult, error) {
+	var mu sync.Mutex
+	results := make(map[string]CacheGetResult, len(dests))
+	err := s.eachShard(mapKeys(dests), func(shard *MultiLevelCache, keys []string) error {
+		var errs []error
+		for _, key := range keys {
+			res, err := shard.Get(ctx, key, dests[key], opts)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			mu.Lock()
+			results[key] = res
+			mu.Unlock()
+		}
+		return errors.Join(errs...)
+	})
+	return results, err
+}
+
+// SetMulti writes entries with each shard's SetMulti, in parallel across shards.
+func (s *ShardedMultiLevelCache) SetMulti(ctx context.Context, entries map[string]any, opts CacheOptions) error {
+	return s.eachShard(mapKeys(entries), func(shard *MultiLevelCache, keys []string) error {
+		group := make(map[string]any, len(keys))
+		for _, key := range keys {
+			group[key] = entries[key]
+		}
+		return shard.SetMulti(ctx, group, opts)
+	})
+}
+
+// DeleteMulti removes keys, in parallel across shards. A failed key does not stop the
+// others; the failures are returned joined.
+func (s *ShardedMultiLevelCache) DeleteMulti(ctx context.Context, keys []string) error {
+	return s.eachShard(keys, func(shard *MultiLevelCache, keys []string) error {
+		var errs []error
+		for _, key := range keys {
+			if err := shard.Delete(ctx, key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Flush flushes every shard and returns the total number of keys removed. Shards that
+// fail do not stop the others; the failures are returned joined.
+func (s *ShardedMultiLevelCache) Flush(ctx context.Context) (int, error) {
+	var errs []error
+	total := 0
+	for _, shard := range s.shards {
+		n, err := shard.Flush(ctx)
+		total += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// eachShard groups keys by shard and runs fn for every group in its own goroutine,
+// returning the errors joined in shard order.
+func (s *ShardedMultiLevelCache) eachShard(keys []string, fn func(shard *MultiLevelCache, keys []string) error) error {
+	groups := make([][]string, len(s.shards))
+	for _, key := range keys {
+		i := s.ShardIndex(key)
+		groups[i] = append(groups[i], key)
+	}
+
+	errs := make([]error, len(s.shards))
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(s.shards[i], group)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
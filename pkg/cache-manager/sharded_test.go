@@ -0,0 +1,113 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShardedCache(t *testing.T, n int) (*ShardedMultiLevelCache, []*memoryRawCache) {
+	t.Helper()
+	shards := make([]*MultiLevelCache, n)
+	l1s := make([]*memoryRawCache, n)
+	for i := range shards {
+		var err error
+		l1s[i] = newMemoryRawCache()
+		shards[i], err = NewMultiLevelCache(l1s[i], nil, JSONSerializer{}, MultiLevelConfig{
+			Mode:         ModeL1Only,
+			L1DefaultTTL: time.Minute,
+		})
+		require.NoError(t, err)
+	}
+	sc, err := NewShardedCache(shards)
+	require.NoError(t, err)
+	return sc, l1s
+}
+
+func TestShardedCacheDistributesKeysEvenly(t *testing.T) {
+	t.Parallel()
+
+	sc, _ := newTestShardedCache(t, 4)
+	counts := make([]int, 4)
+	for i := range 1000 {
+		counts[sc.ShardIndex(fmt.Sprintf("user:%d", i))]++
+	}
+	for i, n := range counts {
+		require.InDelta(t, 250, n, 50, "shard %d got %d of 1000 keys: %v", i, n, counts)
+	}
+}
+
+func TestShardedCacheRoutesKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sc, l1s := newTestShardedCache(t, 4)
+	require.NoError(t, sc.Set(ctx, "user:1", "ada", CacheOptions{}))
+	for i, l1 := range l1s {
+		require.Equal(t, i == sc.ShardIndex("user:1"), l1.has("user:1"), "shard %d", i)
+	}
+
+	var got string
+	res, err := sc.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "ada", got)
+
+	require.NoError(t, sc.Delete(ctx, "user:1"))
+	res, err = sc.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+}
+
+func TestShardedCacheMulti(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sc, l1s := newTestShardedCache(t, 4)
+	entries := make(map[string]any)
+	for i := range 40 {
+		entries[fmt.Sprintf("k%d", i)] = i
+	}
+	require.NoError(t, sc.SetMulti(ctx, entries, CacheOptions{}))
+
+	dests := make(map[string]any)
+	for key := range entries {
+		dests[key] = new(int)
+	}
+	dests["missing"] = new(int)
+	results, err := sc.GetMulti(ctx, dests, CacheOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 41)
+	require.False(t, results["missing"].Found)
+	for key, want := range entries {
+		require.True(t, results[key].Found, key)
+		require.Equal(t, want, *dests[key].(*int))
+	}
+
+	require.NoError(t, sc.DeleteMulti(ctx, []string{"k0", "k1"}))
+	results, err = sc.GetMulti(ctx, map[string]any{"k0": new(int), "k2": new(int)}, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, results["k0"].Found)
+	require.True(t, results["k2"].Found)
+
+	n, err := sc.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 38, n)
+	for _, l1 := range l1s {
+		l1.mu.Lock()
+		require.Empty(t, l1.data)
+		l1.mu.Unlock()
+	}
+}
+
+func TestNewShardedCacheValidates(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewShardedCache(nil)
+	require.Error(t, err)
+	_, err = NewShardedCache([]*MultiLevelCache{nil})
+	require.ErrorContains(t, err, "shard 0 is nil")
+}
@@ -0,0 +1,106 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCacheFull is returned by SizeLimitedCache when a new key would exceed the quota.
+var ErrCacheFull = errors.New("cache key quota exceeded")
+
+// SizeLimitedCache wraps a RawCache and caps the number of distinct keys this instance
+// may write, protecting shared Redis instances from a single noisy service.
+//
+// Keys are tracked locally, so entries that expire in the inner cache keep counting
+// against the quota until they are deleted or the cache is flushed.
+type SizeLimitedCache struct {
+	inner   RawCache
+	maxKeys int64
+	keys    sync.Map // key -> struct{}
+	count   atomic.Int64
+}
+
+// NewSizeLimitedCache wraps inner with a quota of maxKeys distinct keys.
+func NewSizeLimitedCache(inner RawCache, maxKeys int) *SizeLimitedCache {
+	return &SizeLimitedCache{inner: inner, maxKeys: int64(maxKeys)}
+}
+
+// Len returns the number of tracked keys.
+func (s *SizeLimitedCache) Len() int {
+	return int(s.count.Load())
+}
+
+// Get delegates to the wrapped cache.
+func (s *SizeLimitedCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.inner.Get(ctx, key)
+}
+
+// Set writes through to the wrapped cache, returning ErrCacheFull when key is new
+// and the quota is already reached. Overwriting a tracked key is always allowed.
+func (s *SizeLimitedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	added := false
+	if _, tracked := s.keys.Load(key); !tracked {
+		if !s.reserve() {
+			return ErrCacheFull
+		}
+		if _, loaded := s.keys.LoadOrStore(key, struct{}{}); loaded {
+			// another writer tracked the key concurrently; give the slot back
+			s.count.Add(-1)
+		} else {
+			added = true
+		}
+	}
+
+	if err := s.inner.Set(ctx, key, value, ttl); err != nil {
+		if added {
+			s.untrack(key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes key from the wrapped cache and releases its quota slot.
+func (s *SizeLimitedCache) Delete(ctx context.Context, key string) error {
+	if err := s.inner.Delete(ctx, key); err != nil {
+		return err
+	}
+	s.untrack(key)
+	return nil
+}
+
+// Flush deletes every tracked key from the wrapped cache and resets the counter to zero.
+func (s *SizeLimitedCache) Flush(ctx context.Context) error {
+	var firstErr error
+	s.keys.Range(func(k, _ any) bool {
+		if err := s.inner.Delete(ctx, k.(string)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		s.keys.Delete(k)
+		return true
+	})
+	s.count.Store(0)
+	return firstErr
+}
+
+// reserve claims a quota slot, returning false when the cache is full.
+func (s *SizeLimitedCache) reserve() bool {
+	for {
+		n := s.count.Load()
+		if n >= s.maxKeys {
+			return false
+		}
+		if s.count.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+func (s *SizeLimitedCache) untrack(key string) {
+	if _, loaded := s.keys.LoadAndDelete(key); loaded {
+		s.count.Add(-1)
+	}
+}
@@ -0,0 +1,35 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeLimitedCacheRejectsKeysOverQuota(t *testing.T) {
+	t.Parallel()
+
+	sc := NewSizeLimitedCache(newMemoryRawCache(), 100)
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, sc.Set(ctx, fmt.Sprintf("key:%d", i), []byte("v"), time.Minute))
+	}
+	require.ErrorIs(t, sc.Set(ctx, "key:100", []byte("v"), time.Minute), ErrCacheFull)
+
+	// Overwriting an existing key does not consume quota.
+	require.NoError(t, sc.Set(ctx, "key:0", []byte("updated"), time.Minute))
+
+	require.NoError(t, sc.Delete(ctx, "key:1"))
+	require.Equal(t, 99, sc.Len())
+	require.NoError(t, sc.Set(ctx, "key:100", []byte("v"), time.Minute))
+
+	require.NoError(t, sc.Flush(ctx))
+	require.Equal(t, 0, sc.Len())
+	_, ok, err := sc.Get(ctx, "key:100")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
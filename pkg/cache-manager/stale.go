@@ -0,0 +1,89 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+// MaxStaleGrace caps BigCacheConfig.StaleGrace: past it, a value is too old to be better
+// than an error.
+const MaxStaleGrace = time.Hour
+
+// StaleReader is implemented by raw caches that can return an entry after its expiry,
+// such as a BigCache with StaleGrace.
+type StaleReader interface {
+	// GetStale returns key's payload whether it is live or expired within the grace
+	// window.
+	GetStale(ctx context.Context, key string) ([]byte, bool, error)
+}
+
+var _ StaleReader = (*BigCache)(nil)
+
+// GetStale returns the payload of key while it is live or expired for at most
+// StaleGrace.
+func (b *BigCache) GetStale(ctx context.Context, key string) ([]byte, bool, error) {
+	release := b.rlock()
+	if release == nil {
+		return nil, false, &CacheError{Op: "get", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
+	}
+	defer release()
+
+	raw, err := b.cache.Get(key)
+	if err != nil {
+		if errors.Is(err, bigcache.ErrEntryNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, &CacheError{Op: "get", Level: LevelL1, Key: key, Cause: err}
+	}
+	payload, _, ok := decodeEntry(raw, b.now()-b.staleGrace)
+	return payload, ok, nil
+}
+
+type servedStaleKey struct{}
+
+// ServedStale reports whether the Get whose AfterGet hook received ctx was served a
+// stale L1 value by MultiLevelConfig.ServeStaleOnError.
+func ServedStale(ctx context.Context) bool {
+	stale, _ := ctx.Value(servedStaleKey{}).(bool)
+	return stale
+}
+
+// backendFailure reports whether err is an L2 read or Loader failure, the errors
+// ServeStaleOnError covers.
+func backendFailure(err error) bool {
+	var ce *CacheError
+	return errors.As(err, &ce) && (ce.Level == LevelL2 || ce.Op == "load")
+}
+
+// serveStale fills dest from an expired L1 copy of key (a stored key) after L2 or the
+// Loader failed. It reports false when L1 holds none within its grace window.
+func (m *MultiLevelCache) serveStale(ctx context.Context, key string, dest any, cause error) (EntryMetadata, bool) {
+	reader, ok := m.l1.(StaleReader)
+	if !ok || dest == nil || m.switches.l1Off.Load() {
+		return EntryMetadata{}, false
+	}
+	data, ok, err := reader.GetStale(ctx, key)
+	if err != nil || !ok {
+		return EntryMetadata{}, false
+	}
+	hash, payload := splitContentHash(data)
+	if err := m.decode(m.l1Serializer, payload, dest); err != nil {
+		return EntryMetadata{}, false
+	}
+	m.staleServes.Add(1)
+	m.log.logger.Warn("cache get served stale l1 value", "key", key, "error", cause)
+	m.emit(ctx, "get", key, LevelL1, EventHit)
+	return EntryMetadata{ETag: etagOf(hash), Level: LevelL1, Stale: true}, true
+}
+
+// checkServeStale validates MultiLevelConfig.ServeStaleOnError against l1.
+func checkServeStale(l1 RawCache) error {
+	if _, ok := l1.(StaleReader); !ok {
+		return fmt.Errorf("ServeStaleOnError requires an L1 that implements StaleReader, got %T", l1)
+	}
+	return nil
+}
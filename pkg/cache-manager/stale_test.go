@@ -0,0 +1,120 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeStaleOnTotalBackendFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var staleHooks []bool
+	clock := newFakeClock()
+	l1 := newFakeClockBigCache(t, clock, BigCacheConfig{StaleGrace: time.Minute})
+	ml := newTestMultiLevelCacheOver(t, l1, failingRawCache{err: errors.New("redis down")}, MultiLevelConfig{
+		ServeStaleOnError: true,
+		Clock:             clock,
+		L1DefaultTTL:      10 * time.Second,
+		AfterGet: func(ctx context.Context, _ string, _ bool, _ string, _ error) {
+			staleHooks = append(staleHooks, ServedStale(ctx))
+		},
+	})
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{}), "L1 took the write")
+
+	var got string
+	res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Stale, "a live L1 hit is not stale")
+
+	// Just past the expiry and at the very end of the grace window: stale.
+	clock.Advance(10*time.Second + time.Nanosecond)
+	got = ""
+	res, err = ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL1, Stale: true}, res)
+	require.Equal(t, "v", got)
+
+	clock.Advance(time.Minute - time.Nanosecond)
+	meta, found, err := ml.GetWithMetadata(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, meta.Stale)
+
+	// Past the grace window the backend error surfaces.
+	clock.Advance(time.Nanosecond)
+	_, err = ml.Get(ctx, "k", &got, CacheOptions{})
+	require.ErrorContains(t, err, "redis down")
+
+	stats, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), stats.StaleServes)
+	require.Equal(t, []bool{false, true, true, false}, staleHooks)
+}
+
+func TestServeStaleWhenLoaderFails(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	l1 := newFakeClockBigCache(t, clock, BigCacheConfig{StaleGrace: time.Minute})
+	errDB := errors.New("db down")
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{
+		Mode:              ModeL1Only,
+		Clock:             clock,
+		ServeStaleOnError: true,
+		Loader:            LoaderFunc(func(context.Context, string) (any, time.Duration, error) { return nil, 0, errDB }),
+	})
+	require.NoError(t, err)
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{L1TTL: time.Second}))
+
+	clock.Advance(2 * time.Second)
+	var got string
+	res, err := ml.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Stale)
+	require.Equal(t, "v", got)
+
+	_, err = ml.Get(ctx, "missing", &got, CacheOptions{})
+	require.ErrorIs(t, err, errDB, "nothing stale to serve")
+}
+
+func TestStaleGraceKeepsExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{StaleGrace: time.Minute})
+	require.NoError(t, bc.Set(ctx, "k", []byte("v"), time.Second))
+
+	clock.Advance(30 * time.Second)
+	_, ok, err := bc.Get(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, ok, "Get does not serve expired entries")
+	purged, err := bc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Zero(t, purged, "entries within the grace window are kept")
+	data, ok, err := bc.GetStale(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("v"), data)
+
+	clock.Advance(time.Minute)
+	purged, err = bc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+}
+
+func TestServeStaleValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewBigCache(context.Background(), BigCacheConfig{StaleGrace: MaxStaleGrace + time.Second})
+	require.ErrorContains(t, err, "stale grace")
+
+	_, err = NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, ServeStaleOnError: true})
+	require.ErrorContains(t, err, "StaleReader")
+}
@@ -0,0 +1,136 @@
+package cache_manager
+
+import (
+	"bufio"
+	"context"
+	"strconv"
+	"strings"
+)
+
+// StatsReporter is implemented by raw caches that expose their own hit/miss counters.
+type StatsReporter interface {
+	Stats(ctx context.Context) (LevelStats, error)
+}
+
+// LevelStats are the backend's own counters for one cache level since it started.
+type LevelStats struct {
+	Hits       int64 `json:"hits"`
+	Misses     int64 `json:"misses"`
+	DelHits    int64 `json:"del_hits,omitempty"`
+	DelMisses  int64 `json:"del_misses,omitempty"`
+	Collisions int64 `json:"collisions,omitempty"`
+}
+
+// HitRatio returns Hits / (Hits + Misses), or 0 before any lookup.
+func (s LevelStats) HitRatio() float64 {
+	return hitRatio(s.Hits, s.Misses)
+}
+
+// CacheStatsReport aggregates backend statistics across levels. Levels whose raw cache
+// does not implement StatsReporter are omitted.
+type CacheStatsReport struct {
+	L1 *LevelStats `json:"l1,omitempty"`
+	L2 *LevelStats `json:"l2,omitempty"`
+	// Hits and Misses sum the per-level counters, so an L1 miss served by L2 counts once
+	// as a miss and once as a hit.
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// BigCacheCollisions counts L1 hash collisions, where one key's entry replaced another's.
+	BigCacheCollisions int64 `json:"bigcache_collisions"`
+	// L2State is the degradation state, empty unless MultiLevelConfig.Degradation is set
+	// or L2 is a ConnectionNotifier.
+	// L2 counters are not collected while degraded.
+	L2State string `json:"l2_state,omitempty"`
+	// L2InFlight and L2Throttled are the L2 operations running now and those throttled
+	// since start, reported only when MultiLevelConfig.L2MaxConcurrency is set.
+	L2InFlight  *int64 `json:"l2_in_flight,omitempty"`
+	L2Throttled *int64 `json:"l2_throttled,omitempty"`
+	// DisabledLevels lists the levels switched off with SetLevelEnabled.
+	DisabledLevels []string `json:"disabled_levels,omitempty"`
+	// StaleServes counts the Gets served an expired L1 copy by ServeStaleOnError.
+	StaleServes int64 `json:"stale_serves"`
+	// L1Memory is the L1 payload estimate, reported only when
+	// MultiLevelConfig.L1SoftMemoryBudget is set.
+	L1Memory *L1MemoryUsage `json:"l1_memory,omitempty"`
+	// PoisonedEntries counts the undecodable entries deleted by
+	// MultiLevelConfig.QuarantineUndecodable.
+	PoisonedEntries int64 `json:"poisoned_entries"`
+}
+
+// HitRatio returns Hits / (Hits + Misses), or 0 before any lookup.
+func (r CacheStatsReport) HitRatio() float64 {
+	return hitRatio(r.Hits, r.Misses)
+}
+
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// Stats collects the backend counters of every configured level (bigcache Stats, Redis
+// INFO stats).
+func (m *MultiLevelCache) Stats(ctx context.Context) (CacheStatsReport, error) {
+	if m == nil {
+		return CacheStatsReport{}, &CacheError{Op: "stats", Cause: ErrNotInitialized}
+	}
+
+	report := CacheStatsReport{DisabledLevels: m.disabledLevels(), StaleServes: m.staleServes.Load()}
+	if m.degradation != nil || m.l2Monitored {
+		report.L2State = m.L2State().String()
+	}
+	if m.quarantine != nil {
+		report.PoisonedEntries = m.quarantine.poisoned.Load()
+	}
+	if m.l1Budget != nil {
+		usage := m.l1Budget.usage()
+		report.L1Memory = &usage
+	}
+	if m.l2Gate != nil {
+		inFlight, throttled := m.l2Gate.inFlight.Load(), m.l2Gate.throttled.Load()
+		report.L2InFlight, report.L2Throttled = &inFlight, &throttled
+	}
+	for _, lvl := range m.levels() {
+		reporter, ok := lvl.cache.(StatsReporter)
+		if !ok || (lvl.name == LevelL2 && !m.l2Available()) {
+			continue
+		}
+		stats, err := reporter.Stats(ctx)
+		if err != nil {
+			return CacheStatsReport{}, wrapError("stats", lvl.name, "", err)
+		}
+		if lvl.name == LevelL1 {
+			report.L1 = &stats
+			report.BigCacheCollisions = stats.Collisions
+		} else {
+			report.L2 = &stats
+		}
+		report.Hits += stats.Hits
+		report.Misses += stats.Misses
+	}
+	return report, nil
+}
+
+// parseRedisInfoStats extracts keyspace hit/miss counters from an INFO stats reply.
+func parseRedisInfoStats(info string) LevelStats {
+	var stats LevelStats
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch name {
+		case "keyspace_hits":
+			stats.Hits = n
+		case "keyspace_misses":
+			stats.Misses = n
+		}
+	}
+	return stats
+}
@@ -0,0 +1,77 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// statsRawCache reports fixed L2 counters, standing in for Redis INFO stats.
+type statsRawCache struct {
+	*memoryRawCache
+	stats LevelStats
+}
+
+func (c statsRawCache) Stats(context.Context) (LevelStats, error) {
+	return c.stats, nil
+}
+
+func TestMultiLevelCacheStatsAggregatesLevels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bcConfig := bigcache.DefaultConfig(time.Minute)
+	bcConfig.Verbose = false
+	l1, err := NewBigCache(ctx, BigCacheConfig{Config: bcConfig})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l1.Close() })
+
+	l2 := statsRawCache{memoryRawCache: newMemoryRawCache(), stats: LevelStats{Hits: 3, Misses: 1}}
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, WarmupTTL: time.Minute})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Set(ctx, "user:1", "ada", CacheOptions{}))
+	var got string
+	for i := 0; i < 3; i++ {
+		res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+		require.NoError(t, err)
+		require.True(t, res.Found)
+	}
+	res, err := ml.Get(ctx, "user:2", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.NoError(t, ml.Delete(ctx, "user:1"))
+
+	report, err := ml.Stats(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, report.L1)
+	require.Equal(t, LevelStats{Hits: 3, Misses: 1, DelHits: 1}, *report.L1)
+	require.Equal(t, &l2.stats, report.L2)
+	require.Equal(t, int64(6), report.Hits)
+	require.Equal(t, int64(2), report.Misses)
+	require.Zero(t, report.BigCacheCollisions)
+	require.InDelta(t, 0.75, report.HitRatio(), 1e-9)
+}
+
+func TestMultiLevelCacheStatsSkipsLevelsWithoutReporter(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	report, err := ml.Stats(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, report.L1)
+	require.Nil(t, report.L2)
+	require.Zero(t, report.HitRatio())
+}
+
+func TestParseRedisInfoStats(t *testing.T) {
+	t.Parallel()
+
+	info := "# Stats\r\ntotal_connections_received:4\r\nkeyspace_hits:42\r\nkeyspace_misses:8\r\nevicted_keys:0\r\n"
+	stats := parseRedisInfoStats(info)
+	require.Equal(t, LevelStats{Hits: 42, Misses: 8}, stats)
+	require.InDelta(t, 0.84, stats.HitRatio(), 1e-9)
+}
@@ -0,0 +1,197 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TagIndex is implemented by raw caches that can record which keys belong to a tag, so
+// every process sees the same members. RedisCache keeps them in a set.
+type TagIndex interface {
+	// AddTagMembers records keys under tag, keeping the record for at least ttl.
+	AddTagMembers(ctx context.Context, tag string, keys []string, ttl time.Duration) error
+	// PopTagMembers returns the keys recorded under tag and forgets them atomically.
+	PopTagMembers(ctx context.Context, tag string) ([]string, error)
+}
+
+var _ TagIndex = (*RedisCache)(nil)
+
+// tagAddScript adds members to a set and extends its expiry to at least ARGV[1] ms, so
+// the set outlives every member added to it; a ttl of 0 makes it persistent.
+var tagAddScript = redis.NewScript(`
+local existed = redis.call('EXISTS', KEYS[1])
+for i = 2, #ARGV do
+	redis.call('SADD', KEYS[1], ARGV[i])
+end
+local ttl = tonumber(ARGV[1])
+if ttl <= 0 then
+	redis.call('PERSIST', KEYS[1])
+else
+	local pttl = redis.call('PTTL', KEYS[1])
+	if existed == 0 or (pttl >= 0 and pttl < ttl) then
+		redis.call('PEXPIRE', KEYS[1], ttl)
+	end
+end
+return 1
+`)
+
+// AddTagMembers adds keys to the Redis set tag.
+func (r *RedisCache) AddTagMembers(ctx context.Context, tag string, keys []string, ttl time.Duration) error {
+	done, err := r.begin("tag", tag)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, ttl.Milliseconds())
+	for _, k := range keys {
+		args = append(args, k)
+	}
+	if err := tagAddScript.Run(ctx, r.client, []string{tag}, args...).Err(); err != nil {
+		return &CacheError{Op: "tag", Level: LevelL2, Key: tag, Cause: err}
+	}
+	return nil
+}
+
+// PopTagMembers reads and deletes the Redis set tag in one transaction.
+func (r *RedisCache) PopTagMembers(ctx context.Context, tag string) ([]string, error) {
+	done, err := r.begin("untag", tag)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var members *redis.StringSliceCmd
+	_, err = r.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		members = p.SMembers(ctx, tag)
+		p.Del(ctx, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, &CacheError{Op: "untag", Level: LevelL2, Key: tag, Cause: err}
+	}
+	return members.Val(), nil
+}
+
+// localTagIndex records tag members in process, for caches whose L2 is not targeted or
+// is not a TagIndex.
+type localTagIndex struct {
+	mu   sync.Mutex
+	tags map[string]map[string]struct{}
+}
+
+func (t *localTagIndex) add(tag string, keys []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tags == nil {
+		t.tags = make(map[string]map[string]struct{})
+	}
+	members := t.tags[tag]
+	if members == nil {
+		members = make(map[string]struct{}, len(keys))
+		t.tags[tag] = members
+	}
+	for _, k := range keys {
+		members[k] = struct{}{}
+	}
+}
+
+func (t *localTagIndex) pop(tag string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	members := t.tags[tag]
+	delete(t.tags, tag)
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// tagStoreKey is where the members of tag are recorded in L2.
+func (m *MultiLevelCache) tagStoreKey(tag string) string {
+	return m.storeKey(NewKey("tag").Str(tag).String())
+}
+
+// SetWithTags is Set followed by recording key under each tag, so InvalidateTag can
+// remove every entry of a collection, such as all cached pages of a list, at once.
+//
+// When L2 is targeted and is a TagIndex, as RedisCache is, the members are kept next to
+// the entries in L2 and shared by every process; otherwise they are kept in this
+// process. If recording fails the entry is deleted again, so it cannot outlive an
+// invalidation of its tag, and the error is returned.
+func (m *MultiLevelCache) SetWithTags(ctx context.Context, key string, value any, opts CacheOptions, tags ...string) error {
+	if m == nil {
+		return &CacheError{Op: "set", Key: key, Cause: ErrNotInitialized}
+	}
+	if err := m.Set(ctx, key, value, opts); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	targetL1, targetL2 := m.determineCacheLevel()
+	targetL1, targetL2 = m.applyEndpointLevelOverrides(opts, targetL1, targetL2)
+	index, shared := m.l2.(TagIndex)
+	if !targetL2 || !shared {
+		for _, tag := range tags {
+			m.localTags.add(tag, []string{key})
+		}
+		return nil
+	}
+
+	l1TTL, l2TTL := m.ttlsFor(m.storeKey(key), opts)
+	ttl := l2TTL
+	if targetL1 && l1TTL > ttl {
+		ttl = l1TTL
+	}
+	for _, tag := range tags {
+		if err := index.AddTagMembers(ctx, m.tagStoreKey(tag), []string{key}, ttl); err != nil {
+			return errors.Join(wrapError("tag", LevelL2, key, err), m.Delete(ctx, key))
+		}
+	}
+	return nil
+}
+
+// InvalidateTag deletes every entry recorded under tag by SetWithTags, from this
+// process's index and from L2's, and forgets the tag. It returns the sorted keys it
+// deleted. Like Delete, it does not reach L1 copies held by other processes.
+func (m *MultiLevelCache) InvalidateTag(ctx context.Context, tag string) ([]string, error) {
+	if m == nil {
+		return nil, &CacheError{Op: "invalidate", Key: tag, Cause: ErrNotInitialized}
+	}
+	defer m.observeLatency("invalidate", time.Now())
+
+	members := make(map[string]struct{})
+	for _, k := range m.localTags.pop(tag) {
+		members[k] = struct{}{}
+	}
+	if index, ok := m.l2.(TagIndex); ok && m.l2Available() {
+		keys, err := index.PopTagMembers(ctx, m.tagStoreKey(tag))
+		if err != nil {
+			return nil, wrapError("invalidate", LevelL2, tag, err)
+		}
+		for _, k := range keys {
+			members[k] = struct{}{}
+		}
+	}
+
+	deleted := make([]string, 0, len(members))
+	var errs []error
+	for k := range members {
+		if err := m.Delete(ctx, k); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted = append(deleted, k)
+	}
+	sort.Strings(deleted)
+	return deleted, errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidateTagDropsEveryPage(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	page1 := CollectionKey("users:list", map[string]string{"limit": "2", "offset": "0"})
+	page2 := CollectionKey("users:list", map[string]string{"limit": "2", "offset": "2"})
+	require.NotEqual(t, page1, page2)
+
+	require.NoError(t, ml.SetWithTags(ctx, page1, []int{1, 2}, CacheOptions{}, "users:list"))
+	require.NoError(t, ml.SetWithTags(ctx, page2, []int{3}, CacheOptions{}, "users:list"))
+	require.NoError(t, ml.SetWithTags(ctx, "teams:list", []int{9}, CacheOptions{}, "teams:list"))
+
+	var got []int
+	_, err := ml.Get(ctx, page2, &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, got, "pages must not overwrite each other")
+
+	deleted, err := ml.InvalidateTag(ctx, "users:list")
+	require.NoError(t, err)
+	require.Equal(t, []string{page1, page2}, deleted)
+	for _, key := range []string{page1, page2} {
+		require.False(t, l1.has(key), key)
+		require.False(t, l2.has(key), key)
+	}
+	require.True(t, l1.has("teams:list"), "other tags are left alone")
+
+	deleted, err = ml.InvalidateTag(ctx, "users:list")
+	require.NoError(t, err)
+	require.Empty(t, deleted)
+}
+
+func TestInvalidateTagSharesMembersThroughRedis(t *testing.T) {
+	t.Parallel()
+
+	writer, mr := newRenameTestCache(t, newMemoryRawCache())
+	l2, err := NewRedisCache(writer.l2.(*RedisCache).client)
+	require.NoError(t, err)
+	other, err := NewMultiLevelCache(newMemoryRawCache(), l2, JSONSerializer{}, MultiLevelConfig{Mode: ModeBothLevels, InstanceName: "users"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	page1 := CollectionKey("users:list", map[string]string{"offset": "0"})
+	page2 := CollectionKey("users:list", map[string]string{"offset": "2"})
+	require.NoError(t, writer.SetWithTags(ctx, page1, "a", CacheOptions{L1TTL: time.Minute, L2TTL: time.Hour}, "users:list"))
+	require.NoError(t, writer.SetWithTags(ctx, page2, "b", CacheOptions{L1TTL: time.Minute, L2TTL: time.Minute}, "users:list"))
+	tagKey := writer.tagStoreKey("users:list")
+	require.Equal(t, time.Hour, mr.TTL(tagKey), "the tag must outlive its longest-lived member")
+
+	// Another process sees the members recorded in Redis.
+	deleted, err := other.InvalidateTag(ctx, "users:list")
+	require.NoError(t, err)
+	require.Equal(t, []string{page1, page2}, deleted)
+	require.False(t, mr.Exists(writer.storeKey(page1)))
+	require.False(t, mr.Exists(writer.storeKey(page2)))
+	require.False(t, mr.Exists(tagKey))
+}
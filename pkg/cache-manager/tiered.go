@@ -0,0 +1,192 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Level is one tier of a TieredCache. Levels are ordered fastest first, e.g. an
+// in-process BigCache, a Redis replica in the same zone, then the regional Redis.
+type Level struct {
+	// Name identifies the level in errors and logs. Defaults to "L<n>" (1-based).
+	Name string
+	// Cache stores the serialized values for this level.
+	Cache RawCache
+	// DefaultTTL is applied by Set. Defaults to 5 minutes.
+	DefaultTTL time.Duration
+	// WarmupTTL is applied when a hit in a slower level is copied into this one.
+	// Defaults to DefaultTTL.
+	WarmupTTL time.Duration
+	// NoWarmup keeps hits in slower levels from being copied into this level.
+	NoWarmup bool
+}
+
+// TieredConfig exposes optional tuning knobs for a TieredCache.
+type TieredConfig struct {
+	// Logger receives the per-operation debug logs. nil uses slog.Default().
+	Logger *slog.Logger
+	// LogSampleRate is the fraction of per-operation debug logs emitted. nil uses 0.01.
+	LogSampleRate *float64
+}
+
+// TieredCache is a cache-aside facade over any number of levels. Get probes the
+// levels in order and, on a hit, warms every faster level; Set and Delete write
+// through all of them.
+//
+// It covers the plain Get/Set/Delete path only. Modes, per-call level targeting,
+// compression, chunking and the other MultiLevelCache features are tied to the
+// L1/L2 split and are not available here. CacheOptions.L1TTL overrides the first
+// level's TTL and L2TTL the TTL of every slower level; TargetL1/TargetL2 are rejected.
+type TieredCache struct {
+	levels     []Level
+	serializer Serializer
+	log        *sampledLogger
+}
+
+var _ Cache = (*TieredCache)(nil)
+
+// NewTieredCache builds a TieredCache from levels ordered fastest first.
+func NewTieredCache(levels []Level, serializer Serializer, cfg TieredConfig) (*TieredCache, error) {
+	if serializer == nil {
+		return nil, &CacheError{Op: "new", Cause: ErrSerializerMissing}
+	}
+	if len(levels) == 0 {
+		return nil, &CacheError{Op: "new", Cause: fmt.Errorf("%w: no levels given", ErrLevelNotConfigured)}
+	}
+
+	tiers := make([]Level, len(levels))
+	for i, lvl := range levels {
+		if lvl.Name == "" {
+			lvl.Name = fmt.Sprintf("L%d", i+1)
+		}
+		if lvl.Cache == nil {
+			return nil, &CacheError{Op: "new", Level: lvl.Name, Cause: ErrLevelNotConfigured}
+		}
+		if lvl.DefaultTTL <= 0 {
+			lvl.DefaultTTL = 5 * time.Minute
+		}
+		if lvl.WarmupTTL <= 0 {
+			lvl.WarmupTTL = lvl.DefaultTTL
+		}
+		tiers[i] = lvl
+	}
+
+	logSampleRate := defaultLogSampleRate
+	if cfg.LogSampleRate != nil {
+		logSampleRate = *cfg.LogSampleRate
+	}
+
+	return &TieredCache{
+		levels:     tiers,
+		serializer: serializer,
+		log:        newSampledLogger(cfg.Logger, logSampleRate),
+	}, nil
+}
+
+// Levels returns the configured levels, fastest first, with defaults applied.
+func (t *TieredCache) Levels() []Level {
+	return append([]Level(nil), t.levels...)
+}
+
+// Get returns the value from the fastest level holding key and copies it into every
+// faster level that allows warmup. The result's Level is that level's Name. A level
+// error stops the probe and is returned.
+func (t *TieredCache) Get(ctx context.Context, key string, dest any, opts CacheOptions) (CacheGetResult, error) {
+	miss := CacheGetResult{Level: CacheLevelNone}
+	if err := checkTieredOptions("get", key, opts); err != nil {
+		return miss, err
+	}
+
+	for i, lvl := range t.levels {
+		data, found, err := lvl.Cache.Get(ctx, key)
+		if err != nil {
+			t.log.Debug(ctx, "cache get error", "level", lvl.Name, "key", key, "err", err)
+			return miss, wrapError("get", lvl.Name, key, err)
+		}
+		if !found {
+			continue
+		}
+
+		if err := t.serializer.Unmarshal(data, dest); err != nil {
+			return miss, wrapError("get", lvl.Name, key, err)
+		}
+		t.log.Debug(ctx, "cache hit", "level", lvl.Name, "key", key)
+		t.warm(ctx, key, data, i)
+		return CacheGetResult{Found: true, Level: CacheLevelHit(lvl.Name)}, nil
+	}
+
+	t.log.Debug(ctx, "cache miss", "key", key)
+	return miss, nil
+}
+
+// warm copies data into the levels faster than hit. Failures are logged and ignored:
+// the caller already has the value, and the next Get simply warms again.
+func (t *TieredCache) warm(ctx context.Context, key string, data []byte, hit int) {
+	for _, lvl := range t.levels[:hit] {
+		if lvl.NoWarmup {
+			continue
+		}
+		if err := lvl.Cache.Set(ctx, key, data, lvl.WarmupTTL); err != nil {
+			t.log.Debug(ctx, "cache warmup failed", "level", lvl.Name, "key", key, "err", err)
+		}
+	}
+}
+
+// Set writes value to every level. It only fails when no level accepted the write;
+// partial failures are logged.
+func (t *TieredCache) Set(ctx context.Context, key string, value any, opts CacheOptions) error {
+	if err := checkTieredOptions("set", key, opts); err != nil {
+		return err
+	}
+
+	data, err := t.serializer.Marshal(value)
+	if err != nil {
+		return wrapError("set", "", key, err)
+	}
+
+	var errs []error
+	for i, lvl := range t.levels {
+		if err := lvl.Cache.Set(ctx, key, data, t.ttlFor(i, opts)); err != nil {
+			t.log.Debug(ctx, "cache set error", "level", lvl.Name, "key", key, "err", err)
+			errs = append(errs, wrapError("set", lvl.Name, key, err))
+		}
+	}
+	if len(errs) == len(t.levels) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// ttlFor resolves the Set TTL of level i from the per-call options.
+func (t *TieredCache) ttlFor(i int, opts CacheOptions) time.Duration {
+	override := opts.L2TTL
+	if i == 0 {
+		override = opts.L1TTL
+	}
+	if override > 0 {
+		return override
+	}
+	return t.levels[i].DefaultTTL
+}
+
+// Delete removes key from every level, including the ones after a failing level,
+// and returns the errors joined.
+func (t *TieredCache) Delete(ctx context.Context, key string) error {
+	var errs []error
+	for _, lvl := range t.levels {
+		if err := lvl.Cache.Delete(ctx, key); err != nil {
+			errs = append(errs, wrapError("delete", lvl.Name, key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkTieredOptions(op, key string, opts CacheOptions) error {
+	if opts.TargetL1 != nil || opts.TargetL2 != nil {
+		return &CacheError{Op: op, Key: key, Cause: fmt.Errorf("%w: TieredCache always uses every level", ErrLevelOverrideNotAllowed)}
+	}
+	return nil
+}
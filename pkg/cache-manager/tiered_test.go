@@ -0,0 +1,134 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTieredCache(t *testing.T) (*TieredCache, []*memoryRawCache) {
+	t.Helper()
+
+	raws := []*memoryRawCache{newMemoryRawCache(), newMemoryRawCache(), newMemoryRawCache()}
+	tc, err := NewTieredCache([]Level{
+		{Cache: raws[0], DefaultTTL: time.Minute, WarmupTTL: 10 * time.Second},
+		{Cache: raws[1], DefaultTTL: 10 * time.Minute, WarmupTTL: 2 * time.Minute},
+		{Cache: raws[2], DefaultTTL: time.Hour},
+	}, JSONSerializer{}, TieredConfig{})
+	require.NoError(t, err)
+	return tc, raws
+}
+
+func TestTieredCacheHitInSlowestLevelWarmsFasterLevels(t *testing.T) {
+	t.Parallel()
+
+	tc, raws := newTestTieredCache(t)
+	ctx := context.Background()
+	require.NoError(t, raws[2].Set(ctx, "user:1", []byte(`"ada"`), time.Hour))
+
+	var got string
+	res, err := tc.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheGetResult{Found: true, Level: "L3"}, res)
+	require.Equal(t, "ada", got)
+
+	require.True(t, raws[0].has("user:1"))
+	require.True(t, raws[1].has("user:1"))
+	require.Equal(t, 10*time.Second, raws[0].ttl["user:1"])
+	require.Equal(t, 2*time.Minute, raws[1].ttl["user:1"])
+	require.Equal(t, time.Hour, raws[2].ttl["user:1"])
+}
+
+func TestTieredCacheHitInMiddleLevelLeavesSlowerLevelsAlone(t *testing.T) {
+	t.Parallel()
+
+	tc, raws := newTestTieredCache(t)
+	ctx := context.Background()
+	require.NoError(t, raws[1].Set(ctx, "user:1", []byte(`"ada"`), time.Hour))
+
+	var got string
+	res, err := tc.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.True(t, raws[0].has("user:1"))
+	require.False(t, raws[2].has("user:1"))
+}
+
+func TestTieredCacheNoWarmupLevelIsSkipped(t *testing.T) {
+	t.Parallel()
+
+	l1, l2, l3 := newMemoryRawCache(), newMemoryRawCache(), newMemoryRawCache()
+	tc, err := NewTieredCache([]Level{
+		{Cache: l1},
+		{Cache: l2, NoWarmup: true},
+		{Cache: l3},
+	}, JSONSerializer{}, TieredConfig{})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, l3.Set(ctx, "k", []byte(`1`), time.Hour))
+
+	var got int
+	res, err := tc.Get(ctx, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.True(t, l1.has("k"))
+	require.False(t, l2.has("k"))
+}
+
+func TestTieredCacheSetAndDeleteUseEveryLevel(t *testing.T) {
+	t.Parallel()
+
+	tc, raws := newTestTieredCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, tc.Set(ctx, "k", "v", CacheOptions{L2TTL: 3 * time.Minute}))
+	require.Equal(t, time.Minute, raws[0].ttl["k"])
+	require.Equal(t, 3*time.Minute, raws[1].ttl["k"])
+	require.Equal(t, 3*time.Minute, raws[2].ttl["k"])
+
+	require.NoError(t, tc.Delete(ctx, "k"))
+	for _, raw := range raws {
+		require.False(t, raw.has("k"))
+	}
+}
+
+func TestTieredCacheSetFailsOnlyWhenEveryLevelFails(t *testing.T) {
+	t.Parallel()
+
+	down := failingRawCache{err: errors.New("down")}
+	up := newMemoryRawCache()
+	tc, err := NewTieredCache([]Level{{Cache: down}, {Cache: up}}, JSONSerializer{}, TieredConfig{})
+	require.NoError(t, err)
+	require.NoError(t, tc.Set(context.Background(), "k", "v", CacheOptions{}))
+	require.True(t, up.has("k"))
+
+	tc, err = NewTieredCache([]Level{{Cache: down}, {Cache: down}}, JSONSerializer{}, TieredConfig{})
+	require.NoError(t, err)
+	require.Error(t, tc.Set(context.Background(), "k", "v", CacheOptions{}))
+}
+
+func TestTieredCacheRejectsLevelTargeting(t *testing.T) {
+	t.Parallel()
+
+	tc, _ := newTestTieredCache(t)
+	err := tc.Set(context.Background(), "k", "v", CacheOptions{TargetL1: BoolPtr(true)})
+	require.ErrorIs(t, err, ErrLevelOverrideNotAllowed)
+}
+
+func TestNewTieredCacheValidatesLevels(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewTieredCache(nil, JSONSerializer{}, TieredConfig{})
+	require.ErrorIs(t, err, ErrLevelNotConfigured)
+
+	_, err = NewTieredCache([]Level{{Cache: newMemoryRawCache()}, {}}, JSONSerializer{}, TieredConfig{})
+	require.ErrorIs(t, err, ErrLevelNotConfigured)
+
+	tc, err := NewTieredCache([]Level{{Cache: newMemoryRawCache()}}, JSONSerializer{}, TieredConfig{})
+	require.NoError(t, err)
+	require.Equal(t, "L1", tc.Levels()[0].Name)
+	require.Equal(t, 5*time.Minute, tc.Levels()[0].WarmupTTL)
+}
@@ -0,0 +1,115 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+// Toucher is implemented by raw caches that can mark a key as just accessed without
+// reading or rewriting its value. It reports false when the key does not exist.
+type Toucher interface {
+	Touch(ctx context.Context, key string) (bool, error)
+}
+
+var (
+	_ Toucher = (*RedisCache)(nil)
+	_ Toucher = (*BigCache)(nil)
+)
+
+// Touch resets the idle time of key with TOUCH, which is what OBJECT IDLETIME and the
+// allkeys-lru eviction policy go by. The TTL is left alone.
+func (r *RedisCache) Touch(ctx context.Context, key string) (bool, error) {
+	done, err := r.begin("touch", key)
+	if err != nil {
+		return false, err
+	}
+	defer done()
+	n, err := r.client.Touch(ctx, key).Result()
+	if err != nil {
+		return false, &CacheError{Op: "touch", Level: LevelL2, Key: key, Cause: err}
+	}
+	return n > 0, nil
+}
+
+// Touch marks key as accessed now. The entry header records the expiry and the TTL the
+// entry was stored with, so the last access is expiry minus that TTL; Touch rewrites
+// the header with a full TTL from now, keeping the payload and priority. Entries
+// without a TTL have nothing to update and are only checked for presence.
+func (b *BigCache) Touch(ctx context.Context, key string) (bool, error) {
+	release := b.rlock()
+	if release == nil {
+		return false, &CacheError{Op: "touch", Level: LevelL1, Key: key, Cause: ErrNotInitialized}
+	}
+	defer release()
+
+	unlock := b.writeLocks.Lock(key)
+	defer unlock()
+	raw, err := b.cache.Get(key)
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, &CacheError{Op: "touch", Level: LevelL1, Key: key, Cause: err}
+	}
+	now := b.now()
+	payload, priority, ok := decodeEntry(raw, now)
+	if !ok {
+		if b.removable(raw, now) {
+			_ = b.delete(key)
+		}
+		return false, nil
+	}
+
+	originalTTL := entryOriginalTTL(raw)
+	if entryExpiry(raw) == 0 || originalTTL <= 0 {
+		return true, nil
+	}
+	entry := encodeEntryAt(payload, b.clock.Now().Add(originalTTL).UnixNano(), originalTTL, priority)
+	if err := b.set(key, entry); err != nil {
+		return false, &CacheError{Op: "touch", Level: LevelL1, Key: key, Cause: err}
+	}
+	return true, nil
+}
+
+// Touch marks key as accessed without reading or changing its value, for access-time
+// based eviction and analytics. Only L1 is touched unless opts.TouchBothLevels is set;
+// an L2-only cache touches L2. It reports whether any touched level held the key.
+//
+// The touched levels must implement Toucher, as RedisCache and BigCache do. BigCache
+// restarts the entry's TTL from now; Redis only resets the idle time.
+func (m *MultiLevelCache) Touch(ctx context.Context, key string, opts CacheOptions) (bool, error) {
+	if m == nil {
+		return false, &CacheError{Op: "touch", Key: key, Cause: ErrNotInitialized}
+	}
+	defer m.observeLatency("touch", time.Now())
+	storeKey := m.storeKey(key)
+
+	levels := make([]namedLevel, 0, 2)
+	if m.l1 != nil {
+		levels = append(levels, namedLevel{name: LevelL1, cache: m.l1})
+	}
+	if m.l2 != nil && (opts.TouchBothLevels || m.l1 == nil) {
+		levels = append(levels, namedLevel{name: LevelL2, cache: m.l2})
+	}
+	for _, lvl := range levels {
+		if _, ok := lvl.cache.(Toucher); !ok {
+			return false, &CacheError{Op: "touch", Level: lvl.name, Key: key, Cause: fmt.Errorf("%s cannot touch keys: %w", lvl.name, errors.ErrUnsupported)}
+		}
+	}
+
+	touched := false
+	for _, lvl := range levels {
+		found, err := lvl.cache.(Toucher).Touch(ctx, storeKey)
+		if err != nil {
+			m.emit(ctx, "touch", storeKey, lvl.name, EventError)
+			return touched, wrapError("touch", lvl.name, key, err)
+		}
+		m.emit(ctx, "touch", storeKey, lvl.name, EventOK)
+		touched = touched || found
+	}
+	return touched, nil
+}
@@ -0,0 +1,101 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigCacheTouchRestartsTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{})
+	ctx := context.Background()
+	require.NoError(t, bc.SetWithPriority(ctx, "k", []byte("v"), time.Minute, 0))
+
+	clock.Advance(40 * time.Second)
+	touched, err := bc.Touch(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, touched)
+
+	ttl, found, err := bc.TTL(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, time.Minute, ttl, "touch must record the access as now")
+
+	data, found, err := bc.Get(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("v"), data)
+
+	touched, err = bc.Touch(ctx, "missing")
+	require.NoError(t, err)
+	require.False(t, touched)
+
+	clock.Advance(2 * time.Minute)
+	touched, err = bc.Touch(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, touched, "expired entries are not touched")
+}
+
+func TestBigCacheTouchKeepsEntriesInStaleGrace(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	bc := newFakeClockBigCache(t, clock, BigCacheConfig{StaleGrace: time.Minute})
+	ctx := context.Background()
+	require.NoError(t, bc.Set(ctx, "k", []byte("v"), time.Second))
+
+	clock.Advance(30 * time.Second)
+	touched, err := bc.Touch(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, touched, "expired entries are not touched")
+
+	data, found, err := bc.GetStale(ctx, "k")
+	require.NoError(t, err)
+	require.True(t, found, "Touch must not delete an entry inside the grace window")
+	require.Equal(t, []byte("v"), data)
+}
+
+func TestTouchOnlyL1ByDefault(t *testing.T) {
+	t.Parallel()
+
+	ml, mr := newRenameTestCache(t, nil)
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1, Name: "Ada"}, CacheOptions{L1TTL: time.Minute, L2TTL: time.Hour}))
+	require.NoError(t, mr.Set("users:user:2", "{}"))
+
+	touched, err := ml.Touch(ctx, "user:1", CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, touched)
+
+	// Only present in L2, so it needs TouchBothLevels to be found.
+	touched, err = ml.Touch(ctx, "user:2", CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, touched)
+	touched, err = ml.Touch(ctx, "user:2", CacheOptions{TouchBothLevels: true})
+	require.NoError(t, err)
+	require.True(t, touched)
+
+	touched, err = ml.Touch(ctx, "user:3", CacheOptions{TouchBothLevels: true})
+	require.NoError(t, err)
+	require.False(t, touched)
+
+	var got loadedUser
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, loadedUser{ID: 1, Name: "Ada"}, got)
+}
+
+func TestTouchNeedsToucherLevels(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	_, err := ml.Touch(context.Background(), "k", CacheOptions{})
+	require.True(t, errors.Is(err, errors.ErrUnsupported), err)
+}
@@ -0,0 +1,120 @@
+package cache_manager
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Trace steps recorded by Get and Set.
+const (
+	// TraceLookup is a read of one level; Result is EventHit, EventMiss or EventError.
+	TraceLookup = "lookup"
+	// TraceDecode is deserializing a hit into the destination.
+	TraceDecode = "decode"
+	// TraceWarmup is copying an L2 hit into L1; Result is EventOK, EventError,
+	// TraceQueued or TraceSkipped.
+	TraceWarmup = "warmup"
+	// TraceLoad is the configured Loader filling a miss.
+	TraceLoad = "load"
+	// TraceEncode is serializing a value for the levels it is written to.
+	TraceEncode = "encode"
+	// TraceWrite is a write to one level; Result is EventOK, EventError or TraceSkipped.
+	TraceWrite = "write"
+)
+
+// TraceSkipped is the Result of a step that was not performed, e.g. a warmup of an
+// oversized payload or a write to a degraded L2.
+const TraceSkipped = "skipped"
+
+// TraceQueued is the Result of a warmup handed to the MultiLevelConfig.WarmupBatch queue.
+const TraceQueued = "queued"
+
+// TraceEvent is one step of a traced cache call.
+type TraceEvent struct {
+	Op     string `json:"op"`
+	Step   string `json:"step"`
+	Key    string `json:"key"`
+	Level  string `json:"level,omitempty"`
+	Result string `json:"result,omitempty"`
+	// TTL is the expiry a write or warmup used.
+	TTL      time.Duration `json:"ttl_ns,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// traceRecorder collects the TraceEvents of one context.
+type traceRecorder struct {
+	mu     sync.Mutex
+	events []TraceEvent
+}
+
+type traceKey struct{}
+
+// WithTrace returns a copy of ctx that records every step of the Get and Set calls
+// made with it, for TraceFromContext. Tracing is meant for debugging single requests;
+// untraced calls do not pay for it.
+func WithTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, traceKey{}, &traceRecorder{})
+}
+
+// TraceFromContext returns the steps recorded so far in a context made by WithTrace, in
+// order, or nil when ctx is not traced.
+func TraceFromContext(ctx context.Context) []TraceEvent {
+	tr := traceFrom(ctx)
+	if tr == nil {
+		return nil
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]TraceEvent{}, tr.events...)
+}
+
+func traceFrom(ctx context.Context) *traceRecorder {
+	if ctx == nil {
+		return nil
+	}
+	tr, _ := ctx.Value(traceKey{}).(*traceRecorder)
+	return tr
+}
+
+// start returns the time a step begins, or the zero time when not tracing.
+func (tr *traceRecorder) start() time.Time {
+	if tr == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// record appends ev with the time elapsed since began and the message of err, if any.
+func (tr *traceRecorder) record(ev TraceEvent, began time.Time, err error) {
+	if tr == nil {
+		return
+	}
+	if !began.IsZero() {
+		ev.Duration = time.Since(began)
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.events = append(tr.events, ev)
+}
+
+// trace records ev on tr, if tracing, with its store key replaced by the logical key.
+func (m *MultiLevelCache) trace(tr *traceRecorder, ev TraceEvent, began time.Time, err error) {
+	if tr == nil {
+		return
+	}
+	ev.Key = m.logicalKey(ev.Key)
+	tr.record(ev, began, err)
+}
+
+// eventResult is EventOK for a nil err and EventError otherwise.
+func eventResult(err error) string {
+	if err != nil {
+		return EventError
+	}
+	return EventOK
+}
@@ -0,0 +1,84 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// traceSteps drops the timings from events, which vary between runs.
+func traceSteps(events []TraceEvent) []TraceEvent {
+	out := make([]TraceEvent, len(events))
+	for i, ev := range events {
+		ev.Duration = 0
+		out[i] = ev
+	}
+	return out
+}
+
+func TestTraceL1Hit(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := WithTrace(context.Background())
+	require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1}, CacheOptions{}))
+	var got loadedUser
+	_, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+
+	require.Equal(t, []TraceEvent{
+		{Op: "set", Step: TraceEncode, Key: "user:1"},
+		{Op: "set", Step: TraceWrite, Key: "user:1", Level: LevelL1, Result: EventOK, TTL: time.Minute},
+		{Op: "set", Step: TraceWrite, Key: "user:1", Level: LevelL2, Result: EventOK, TTL: time.Minute},
+		{Op: "get", Step: TraceLookup, Key: "user:1", Level: LevelL1, Result: EventHit},
+		{Op: "get", Step: TraceDecode, Key: "user:1", Level: LevelL1},
+	}, traceSteps(TraceFromContext(ctx)))
+}
+
+func TestTraceL2HitWithWarmup(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, ml.Set(context.Background(), "user:1", loadedUser{ID: 1}, CacheOptions{}))
+	require.NoError(t, l1.Delete(context.Background(), "user:1"))
+
+	ctx := WithTrace(context.Background())
+	var got loadedUser
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CacheLevelL2, res.Level)
+
+	require.Equal(t, []TraceEvent{
+		{Op: "get", Step: TraceLookup, Key: "user:1", Level: LevelL1, Result: EventMiss},
+		{Op: "get", Step: TraceLookup, Key: "user:1", Level: LevelL2, Result: EventHit},
+		{Op: "get", Step: TraceDecode, Key: "user:1", Level: LevelL2},
+		{Op: "get", Step: TraceWarmup, Key: "user:1", Level: LevelL1, Result: EventOK, TTL: time.Minute},
+	}, traceSteps(TraceFromContext(ctx)))
+}
+
+func TestTraceMiss(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := WithTrace(context.Background())
+	var got loadedUser
+	res, err := ml.Get(ctx, "user:404", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+
+	require.Equal(t, []TraceEvent{
+		{Op: "get", Step: TraceLookup, Key: "user:404", Level: LevelL1, Result: EventMiss},
+		{Op: "get", Step: TraceLookup, Key: "user:404", Level: LevelL2, Result: EventMiss},
+	}, traceSteps(TraceFromContext(ctx)))
+}
+
+func TestTraceFromUntracedContext(t *testing.T) {
+	t.Parallel()
+
+	ml, _, _ := newTestMultiLevelCache(t, MultiLevelConfig{})
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "k", 1, CacheOptions{}))
+	require.Nil(t, TraceFromContext(ctx))
+}
@@ -0,0 +1,63 @@
+package cache_manager
+
+import (
+	"path"
+	"time"
+)
+
+// TTLProvider computes per-key TTLs for Set. A zero duration falls back to the cache's
+// L1DefaultTTL/L2DefaultTTL; per-call CacheOptions TTLs still take precedence.
+type TTLProvider interface {
+	L1TTL(key string) time.Duration
+	L2TTL(key string) time.Duration
+}
+
+// PatternTTL assigns TTLs to keys matching a glob Pattern (path.Match syntax, e.g. "user:*").
+type PatternTTL struct {
+	Pattern string
+	L1TTL   time.Duration
+	L2TTL   time.Duration
+}
+
+// PatternTTLProvider is a TTLProvider that uses the first matching pattern. Keys without
+// a match use the cache defaults.
+type PatternTTLProvider []PatternTTL
+
+func (p PatternTTLProvider) L1TTL(key string) time.Duration {
+	if rule, ok := p.match(key); ok {
+		return rule.L1TTL
+	}
+	return 0
+}
+
+func (p PatternTTLProvider) L2TTL(key string) time.Duration {
+	if rule, ok := p.match(key); ok {
+		return rule.L2TTL
+	}
+	return 0
+}
+
+func (p PatternTTLProvider) match(key string) (PatternTTL, bool) {
+	for _, rule := range p {
+		if ok, _ := path.Match(rule.Pattern, key); ok {
+			return rule, true
+		}
+	}
+	return PatternTTL{}, false
+}
+
+// ttlsFor resolves the L1 and L2 TTLs for key: per-call options first, then the
+// TTLProvider, then the cache defaults.
+func (m *MultiLevelCache) ttlsFor(key string, opts CacheOptions) (time.Duration, time.Duration) {
+	defaultL1, defaultL2 := m.l1DefaultTTL, m.l2DefaultTTL
+	if m.ttlProvider != nil {
+		key = m.logicalKey(key)
+		if ttl := m.ttlProvider.L1TTL(key); ttl > 0 {
+			defaultL1 = ttl
+		}
+		if ttl := m.ttlProvider.L2TTL(key); ttl > 0 {
+			defaultL2 = ttl
+		}
+	}
+	return opts.normalize(defaultL1, defaultL2)
+}
@@ -0,0 +1,67 @@
+package cache_manager
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// truncatingSerializer drops the second half of every JSON payload without reporting
+// an error, like a custom serializer with a buffered writer that is never flushed.
+type truncatingSerializer struct{ JSONSerializer }
+
+func (s truncatingSerializer) Marshal(value any) ([]byte, error) {
+	data, err := s.JSONSerializer.Marshal(value)
+	return data[:len(data)/2], err
+}
+
+func TestValidateOnWriteRejectsTruncatedPayload(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]string{"name": "ada", "role": "admin"}
+
+	cfg := MultiLevelConfig{
+		L1Serializer:    truncatingSerializer{},
+		L2Serializer:    truncatingSerializer{},
+		ValidateOnWrite: true,
+	}
+	ml, l1, l2 := newTestMultiLevelCache(t, cfg)
+	err := ml.Set(context.Background(), "user:1", value, CacheOptions{})
+	require.ErrorIs(t, err, ErrSerialization)
+	require.False(t, l1.has("user:1"))
+	require.False(t, l2.has("user:1"))
+
+	// Without validation the garbage is cached and only fails on read.
+	cfg.ValidateOnWrite = false
+	ml, l1, _ = newTestMultiLevelCache(t, cfg)
+	require.NoError(t, ml.Set(context.Background(), "user:1", value, CacheOptions{}))
+	require.True(t, l1.has("user:1"))
+}
+
+func TestValidateOnWriteRejectsInvalidUTF8JSON(t *testing.T) {
+	t.Parallel()
+
+	ml, l1, _ := newTestMultiLevelCache(t, MultiLevelConfig{ValidateOnWrite: true})
+	err := ml.Set(context.Background(), "k", json.RawMessage("\"\xff\""), CacheOptions{})
+	require.ErrorIs(t, err, ErrSerialization)
+	require.False(t, l1.has("k"))
+}
+
+func TestValidateOnWriteAcceptsValidValues(t *testing.T) {
+	t.Parallel()
+
+	ml, _, l2 := newTestMultiLevelCache(t, MultiLevelConfig{ValidateOnWrite: true})
+	ctx := context.Background()
+	require.NoError(t, ml.Set(ctx, "user:1", loadedUser{ID: 1, Name: "Ada"}, CacheOptions{}))
+	require.NoError(t, ml.Set(ctx, "nil", nil, CacheOptions{}))
+	require.NoError(t, ml.Set(ctx, "empty", "", CacheOptions{}))
+
+	var got loadedUser
+	res, err := ml.Get(ctx, "user:1", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, loadedUser{ID: 1, Name: "Ada"}, got)
+	require.True(t, l2.has("nil"))
+}
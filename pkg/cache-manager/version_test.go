@@ -0,0 +1,96 @@
+package cache_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newVersionedCache(t *testing.T, l1, l2 *memoryRawCache, version string) *MultiLevelCache {
+	t.Helper()
+
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:      ModeBothLevels,
+		WarmupTTL: time.Minute,
+		Version:   version,
+		TTLProvider: PatternTTLProvider{
+			{Pattern: "session:*", L1TTL: 5 * time.Second, L2TTL: 10 * time.Second},
+		},
+	})
+	require.NoError(t, err)
+	return ml
+}
+
+func TestVersionHidesEntriesFromOtherVersions(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	v1 := newVersionedCache(t, l1, l2, "v1")
+	v2 := newVersionedCache(t, l1, l2, "v2")
+	ctx := context.Background()
+
+	require.NoError(t, v1.Set(ctx, "user:42", "old shape", CacheOptions{}))
+	require.True(t, l1.has("v1:user:42"))
+	require.True(t, l2.has("v1:user:42"))
+
+	var got string
+	res, err := v2.Get(ctx, "user:42", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.False(t, res.Found)
+
+	require.NoError(t, v2.Set(ctx, "user:42", "new shape", CacheOptions{}))
+	res, err = v1.Get(ctx, "user:42", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, "old shape", got)
+
+	// Delete and Flush under v2 leave the v1 entries to age out on their own.
+	require.NoError(t, v2.Delete(ctx, "user:42"))
+	require.False(t, l1.has("v2:user:42"))
+	require.True(t, l1.has("v1:user:42"))
+	require.True(t, l2.has("v1:user:42"))
+
+	require.NoError(t, v2.Set(ctx, "user:43", "new shape", CacheOptions{}))
+	deleted, err := v2.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	require.True(t, l2.has("v1:user:42"))
+}
+
+func TestVersionIsHiddenFromCallers(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := newMemoryRawCache(), newMemoryRawCache()
+	v1 := newVersionedCache(t, l1, l2, "v1")
+	v2 := newVersionedCache(t, l1, l2, "v2")
+	ctx := context.Background()
+
+	require.NoError(t, v1.Set(ctx, "user:1", 1, CacheOptions{}))
+	require.NoError(t, v2.Set(ctx, "user:2", 2, CacheOptions{}))
+	require.NoError(t, v2.Set(ctx, "session:1", "s", CacheOptions{}))
+
+	keys, err := v2.Keys(ctx, "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"session:1", "user:2"}, keys)
+	l1Keys, err := v2.ListL1Keys(ctx, "user:*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"user:2"}, l1Keys)
+
+	// TTLProvider patterns match the unversioned key.
+	require.Equal(t, 5*time.Second, l1.ttl["v2:session:1"])
+	require.Equal(t, 10*time.Second, l2.ttl["v2:session:1"])
+
+	info, found, err := v2.Inspect(ctx, "user:2")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "user:2", info.Key)
+
+	var ev CacheEvent
+	for len(v2.Events()) > 0 {
+		ev = <-v2.Events()
+	}
+	require.Equal(t, "session:1", ev.Key)
+	require.Equal(t, "v2", ev.Version)
+}
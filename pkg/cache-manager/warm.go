@@ -0,0 +1,50 @@
+package cache_manager
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Warm writes pre-serialized entries straight to the levels selected by the cache mode,
+// bypassing the Serializer. Keys are written in sorted order and the first failure stops
+// the warmup. A zero TTL uses the TTLProvider or the level's default. It is meant for
+// tests, benchmarks and bulk loads where the payload bytes are already in the cache's
+// wire format.
+func (m *MultiLevelCache) Warm(ctx context.Context, entries map[string][]byte, l1TTL, l2TTL time.Duration) error {
+	if m == nil {
+		return &CacheError{Op: "warm", Cause: ErrNotInitialized}
+	}
+
+	targetL1, targetL2 := m.determineCacheLevel()
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	m.warmup.start(int64(len(keys)), m.clock.Now())
+	defer m.warmup.finish()
+
+	for _, logical := range keys {
+		data := entries[logical]
+		key := m.storeKey(logical)
+		keyL1TTL, keyL2TTL := m.ttlsFor(key, CacheOptions{L1TTL: l1TTL, L2TTL: l2TTL})
+		m.changes.forget(key)
+		if targetL1 && m.l1 != nil {
+			if err := m.setL1(ctx, key, data, keyL1TTL, CacheOptions{}); err != nil {
+				m.warmup.failed()
+				return wrapError("warm", LevelL1, key, err)
+			}
+		}
+		if targetL2 && m.l2 != nil {
+			if err := m.setL2(ctx, key, data, keyL2TTL, CacheOptions{}); err != nil {
+				m.warmup.failed()
+				return wrapError("warm", LevelL2, key, err)
+			}
+		}
+		m.warmup.loaded()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"go-cache-poc/internal/db"
+)
+
+const (
+	// warmFromDBPageSize is the number of users fetched per ListUsers call.
+	warmFromDBPageSize = 100
+	// warmFromDBConcurrency caps the number of concurrent cache writes during warmup.
+	warmFromDBConcurrency = 10
+	// warmFromDBLogEvery controls how often warmup progress is logged.
+	warmFromDBLogEvery = 100
+)
+
+// WarmFromDB pages through every user in store and caches each one under UserCacheKey.
+// Writes run concurrently (at most 10 at a time). Individual write failures do not stop
+// the warmup; the first one is returned once all users have been processed. Progress is
+// reported by WarmupStatus.
+func (m *MultiLevelCache) WarmFromDB(ctx context.Context, store db.UserStore, opts CacheOptions) error {
+	if m == nil {
+		return &CacheError{Op: "warm", Cause: ErrNotInitialized}
+	}
+	if store == nil {
+		return errors.New("store is required")
+	}
+
+	total, err := store.CountUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("count users: %w", err)
+	}
+	m.warmup.start(total, m.clock.Now())
+	defer m.warmup.finish()
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, warmFromDBConcurrency)
+		warmed   atomic.Int64
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for offset := 0; ; offset += warmFromDBPageSize {
+		users, err := store.ListUsers(ctx, warmFromDBPageSize, offset)
+		if err != nil {
+			wg.Wait()
+			return fmt.Errorf("list users at offset %d: %w", offset, err)
+		}
+
+		for _, user := range users {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(u db.User) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if err := m.Set(ctx, UserCacheKey(u.ID), u, opts); err != nil {
+					m.warmup.failed()
+					errOnce.Do(func() { firstErr = fmt.Errorf("warm user %d: %w", u.ID, err) })
+					return
+				}
+				m.warmup.loaded()
+				if n := warmed.Add(1); n%warmFromDBLogEvery == 0 {
+					slog.Info("cache warmup progress", "warmed", n)
+				}
+			}(user)
+		}
+
+		if len(users) < warmFromDBPageSize {
+			break
+		}
+	}
+
+	wg.Wait()
+	slog.Info("cache warmup from db complete", "warmed", warmed.Load())
+	return firstErr
+}
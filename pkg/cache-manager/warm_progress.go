@@ -0,0 +1,82 @@
+package cache_manager
+
+import (
+	"sync"
+	"time"
+)
+
+// WarmupStatus reports the progress of the current or last Warm / WarmFromDB call.
+type WarmupStatus struct {
+	Total     int64     `json:"total"`
+	Loaded    int64     `json:"loaded"`
+	Failed    int64     `json:"failed"`
+	StartedAt time.Time `json:"started_at"`
+	// EstimatedCompletion extrapolates the time spent so far over the remaining entries;
+	// it is zero until the first entry is processed.
+	EstimatedCompletion time.Time `json:"estimated_completion"`
+}
+
+// warmupTracker records warm-up progress for WarmupStatus.
+type warmupTracker struct {
+	mu       sync.Mutex
+	status   WarmupStatus
+	finished bool
+}
+
+// start resets the tracker for a warm-up of total entries.
+func (w *warmupTracker) start(total int64, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = WarmupStatus{Total: total, StartedAt: now}
+	w.finished = false
+}
+
+func (w *warmupTracker) loaded() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.Loaded++
+}
+
+func (w *warmupTracker) failed() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.Failed++
+}
+
+func (w *warmupTracker) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finished = true
+}
+
+// snapshot returns the status with EstimatedCompletion computed as
+// StartedAt + elapsed / (processed / Total).
+func (w *warmupTracker) snapshot(now time.Time) WarmupStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := w.status
+	if processed := status.Loaded + status.Failed; processed > 0 && status.Total > 0 {
+		elapsed := now.Sub(status.StartedAt)
+		status.EstimatedCompletion = status.StartedAt.Add(time.Duration(float64(elapsed) * float64(status.Total) / float64(processed)))
+	}
+	return status
+}
+
+// complete reports whether the last warm-up finished after processing every entry.
+func (w *warmupTracker) complete() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.finished && w.status.Loaded+w.status.Failed >= w.status.Total
+}
+
+// WarmupStatus returns the progress of the running or most recent warm-up.
+func (m *MultiLevelCache) WarmupStatus() WarmupStatus {
+	return m.warmup.snapshot(m.clock.Now())
+}
+
+// IsWarmupComplete reports whether a warm-up has run and processed every entry. It is
+// false before the first warm-up and while one is running, and stays false when Warm
+// stopped at a failed write.
+func (m *MultiLevelCache) IsWarmupComplete() bool {
+	return m.warmup.complete()
+}
@@ -0,0 +1,98 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// observingRawCache calls onSet before every write.
+type observingRawCache struct {
+	*memoryRawCache
+	onSet func(key string) error
+}
+
+func (c *observingRawCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.onSet(key); err != nil {
+		return err
+	}
+	return c.memoryRawCache.Set(ctx, key, value, ttl)
+}
+
+func newWarmProgressTestCache(t *testing.T, clock *fakeClock, onSet func(string) error) *MultiLevelCache {
+	t.Helper()
+
+	l1 := &observingRawCache{memoryRawCache: newMemoryRawCache(), onSet: onSet}
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only, Clock: clock})
+	require.NoError(t, err)
+	return ml
+}
+
+func TestWarmupStatusTracksProgress(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	start := clock.Now()
+	var ml *MultiLevelCache
+	var seen []int64
+	ml = newWarmProgressTestCache(t, clock, func(string) error {
+		status := ml.WarmupStatus()
+		seen = append(seen, status.Loaded)
+		require.Equal(t, int64(4), status.Total)
+		require.False(t, ml.IsWarmupComplete())
+		clock.Advance(time.Second)
+		return nil
+	})
+	require.False(t, ml.IsWarmupComplete())
+
+	entries := make(map[string][]byte)
+	for i := 0; i < 4; i++ {
+		entries[fmt.Sprintf("user:%d", i)] = []byte(fmt.Sprint(i))
+	}
+	require.NoError(t, ml.Warm(context.Background(), entries, 0, 0))
+
+	require.Equal(t, []int64{0, 1, 2, 3}, seen)
+	require.True(t, ml.IsWarmupComplete())
+	status := ml.WarmupStatus()
+	require.Equal(t, int64(4), status.Loaded)
+	require.Zero(t, status.Failed)
+	require.Equal(t, start, status.StartedAt)
+	require.Equal(t, start.Add(4*time.Second), status.EstimatedCompletion)
+}
+
+func TestWarmupStatusEstimatesCompletion(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	var w warmupTracker
+	w.start(10, clock.Now())
+	require.True(t, w.snapshot(clock.Now()).EstimatedCompletion.IsZero())
+
+	clock.Advance(2 * time.Second)
+	w.loaded()
+	w.failed()
+	// 2 of 10 entries took 2s, so the whole run should take 10s.
+	require.Equal(t, w.status.StartedAt.Add(10*time.Second), w.snapshot(clock.Now()).EstimatedCompletion)
+}
+
+func TestWarmupIncompleteAfterFailedWrite(t *testing.T) {
+	t.Parallel()
+
+	ml := newWarmProgressTestCache(t, newFakeClock(), func(key string) error {
+		if key == "b" {
+			return errors.New("disk full")
+		}
+		return nil
+	})
+
+	err := ml.Warm(context.Background(), map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}, 0, 0)
+	require.Error(t, err)
+	require.False(t, ml.IsWarmupComplete())
+	status := ml.WarmupStatus()
+	require.Equal(t, int64(1), status.Loaded)
+	require.Equal(t, int64(1), status.Failed)
+}
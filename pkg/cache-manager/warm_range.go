@@ -0,0 +1,153 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"go-cache-poc/internal/db"
+)
+
+// warmRangeMaxFailures caps how many failed ids a WarmRangeReport lists; Failed still
+// counts all of them.
+const warmRangeMaxFailures = 100
+
+// WarmRangeOptions configures WarmUsersRange.
+type WarmRangeOptions struct {
+	// BatchSize is the number of users fetched per GetUsersRange call. Zero uses 100.
+	BatchSize int
+	// Concurrency caps the number of concurrent cache writes. Zero uses 10.
+	Concurrency int
+	// Cache is passed to every Set; its TTLs decide how long the warmed users live.
+	Cache CacheOptions
+	// Progress, if set, is called after every batch with the totals so far, on the
+	// goroutine that called WarmUsersRange.
+	Progress func(WarmRangeProgress)
+}
+
+// WarmRangeProgress is the running total of a WarmUsersRange call.
+type WarmRangeProgress struct {
+	Batches int `json:"batches"`
+	Fetched int `json:"fetched"`
+	Loaded  int `json:"loaded"`
+	Failed  int `json:"failed"`
+	// LastID is the highest id fetched so far.
+	LastID int `json:"last_id"`
+}
+
+// WarmRangeFailure is a user that could not be written to the cache.
+type WarmRangeFailure struct {
+	ID    int    `json:"id"`
+	Error string `json:"error"`
+}
+
+// WarmRangeReport summarizes a WarmUsersRange call.
+type WarmRangeReport struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	WarmRangeProgress
+	// Failures lists the first 100 failed writes.
+	Failures []WarmRangeFailure `json:"failures,omitempty"`
+	// Canceled is set when ctx ended before the whole range was processed.
+	Canceled bool `json:"canceled"`
+}
+
+// WarmUsersRange caches the users with from <= id <= to under UserCacheKey, fetching
+// them from store in batches with GetUsersRange and writing each batch with bounded
+// concurrency before the next one is fetched. It is meant for demos and for filling a
+// cache ahead of a traffic increase.
+//
+// A failed write does not stop the run; it is counted and listed in the report. A failed
+// fetch stops the run and is returned. When ctx ends, no further batch is fetched or
+// write started, Canceled is set and ctx's error is returned; the report covers
+// everything done until then. Unlike WarmFromDB it does not touch WarmupStatus, which
+// tracks the startup warm-up.
+func (m *MultiLevelCache) WarmUsersRange(ctx context.Context, store db.UserStore, from, to int, opts WarmRangeOptions) (WarmRangeReport, error) {
+	report := WarmRangeReport{From: from, To: to}
+	if m == nil {
+		return report, &CacheError{Op: "warm", Cause: ErrNotInitialized}
+	}
+	if store == nil {
+		return report, errors.New("store is required")
+	}
+	if from > to {
+		return report, fmt.Errorf("invalid range: from %d is greater than to %d", from, to)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = warmFromDBPageSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = warmFromDBConcurrency
+	}
+
+	var mu sync.Mutex
+	fail := func(id int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		report.Failed++
+		if len(report.Failures) < warmRangeMaxFailures {
+			report.Failures = append(report.Failures, WarmRangeFailure{ID: id, Error: err.Error()})
+		}
+	}
+
+	sem := make(chan struct{}, opts.Concurrency)
+	complete := false
+	for cursor := from; !complete; {
+		if ctx.Err() != nil {
+			break
+		}
+		users, err := store.GetUsersRange(ctx, cursor, to, opts.BatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return report, fmt.Errorf("get users %d-%d: %w", cursor, to, err)
+		}
+		if len(users) == 0 {
+			complete = true
+			break
+		}
+
+		var wg sync.WaitGroup
+		for _, user := range users {
+			if ctx.Err() != nil {
+				break
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(u db.User) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if err := m.Set(ctx, UserCacheKey(u.ID), u, opts.Cache); err != nil {
+					fail(u.ID, err)
+					return
+				}
+				mu.Lock()
+				report.Loaded++
+				mu.Unlock()
+			}(user)
+		}
+		wg.Wait()
+
+		last := users[len(users)-1].ID
+		report.Batches++
+		report.Fetched += len(users)
+		report.LastID = last
+		if opts.Progress != nil {
+			opts.Progress(report.WarmRangeProgress)
+		}
+		complete = len(users) < opts.BatchSize || last >= to
+		cursor = last + 1
+	}
+
+	if err := ctx.Err(); err != nil && !complete {
+		report.Canceled = true
+		slog.Warn("cache range warmup canceled", "from", from, "to", to, "loaded", report.Loaded, "failed", report.Failed)
+		return report, err
+	}
+	slog.Info("cache range warmup complete", "from", from, "to", to, "loaded", report.Loaded, "failed", report.Failed)
+	return report, nil
+}
@@ -0,0 +1,135 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go-cache-poc/internal/db"
+)
+
+// rangeRecordingStore records the GetUsersRange calls made against a MemoryStore and
+// runs afterRange after each one.
+type rangeRecordingStore struct {
+	*db.MemoryStore
+	mu         sync.Mutex
+	calls      [][3]int
+	afterRange func(call int)
+}
+
+func (s *rangeRecordingStore) GetUsersRange(ctx context.Context, fromID, toID, limit int) ([]db.User, error) {
+	users, err := s.MemoryStore.GetUsersRange(ctx, fromID, toID, limit)
+	s.mu.Lock()
+	s.calls = append(s.calls, [3]int{fromID, toID, limit})
+	call := len(s.calls)
+	s.mu.Unlock()
+	if s.afterRange != nil {
+		s.afterRange(call)
+	}
+	return users, err
+}
+
+func newRangeRecordingStore(n int) *rangeRecordingStore {
+	store := db.NewMemoryStore()
+	for i := 1; i <= n; i++ {
+		store.Seed(db.User{ID: i, Name: "user"})
+	}
+	return &rangeRecordingStore{MemoryStore: store}
+}
+
+func TestWarmUsersRangeBatches(t *testing.T) {
+	t.Parallel()
+
+	store := newRangeRecordingStore(30)
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	var progress []WarmRangeProgress
+	report, err := ml.WarmUsersRange(context.Background(), store, 3, 14, WarmRangeOptions{
+		BatchSize: 5,
+		Cache:     CacheOptions{L1TTL: time.Minute},
+		Progress:  func(p WarmRangeProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, [][3]int{{3, 14, 5}, {8, 14, 5}, {13, 14, 5}}, store.calls)
+	require.Equal(t, []WarmRangeProgress{
+		{Batches: 1, Fetched: 5, Loaded: 5, LastID: 7},
+		{Batches: 2, Fetched: 10, Loaded: 10, LastID: 12},
+		{Batches: 3, Fetched: 12, Loaded: 12, LastID: 14},
+	}, progress)
+	require.Equal(t, WarmRangeReport{From: 3, To: 14, WarmRangeProgress: progress[2]}, report)
+
+	for id := 1; id <= 30; id++ {
+		require.Equal(t, id >= 3 && id <= 14, l1.has(UserCacheKey(id)), "user %d", id)
+	}
+	require.Equal(t, time.Minute, l1.ttl[UserCacheKey(3)])
+}
+
+func TestWarmUsersRangeReportsFailedWrites(t *testing.T) {
+	t.Parallel()
+
+	store := newRangeRecordingStore(10)
+	errFull := errors.New("cache full")
+	l1 := &observingRawCache{memoryRawCache: newMemoryRawCache(), onSet: func(key string) error {
+		if key == UserCacheKey(4) || key == UserCacheKey(7) {
+			return errFull
+		}
+		return nil
+	}}
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	report, err := ml.WarmUsersRange(context.Background(), store, 1, 10, WarmRangeOptions{BatchSize: 3, Concurrency: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, 4, report.Batches)
+	require.Equal(t, 10, report.Fetched)
+	require.Equal(t, 8, report.Loaded)
+	require.Equal(t, 2, report.Failed)
+	require.False(t, report.Canceled)
+	require.Len(t, report.Failures, 2)
+	require.ElementsMatch(t, []int{4, 7}, []int{report.Failures[0].ID, report.Failures[1].ID})
+	require.Contains(t, report.Failures[0].Error, "cache full")
+	require.True(t, l1.has(UserCacheKey(10)), "a failed write must not stop the run")
+}
+
+func TestWarmUsersRangeStopsWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	store := newRangeRecordingStore(50)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store.afterRange = func(call int) {
+		if call == 2 {
+			cancel()
+		}
+	}
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	report, err := ml.WarmUsersRange(ctx, store, 1, 50, WarmRangeOptions{BatchSize: 10})
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.True(t, report.Canceled)
+	require.Len(t, store.calls, 2, "no batch is fetched after cancellation")
+	require.Equal(t, 10, report.Loaded, "writes of the canceled batch are not started")
+	require.True(t, l1.has(UserCacheKey(10)))
+	require.False(t, l1.has(UserCacheKey(11)))
+}
+
+func TestWarmUsersRangeRejectsInvertedRange(t *testing.T) {
+	t.Parallel()
+
+	ml, err := NewMultiLevelCache(newMemoryRawCache(), nil, JSONSerializer{}, MultiLevelConfig{Mode: ModeL1Only})
+	require.NoError(t, err)
+
+	_, err = ml.WarmUsersRange(context.Background(), newRangeRecordingStore(1), 5, 1, WarmRangeOptions{})
+	require.ErrorContains(t, err, "invalid range")
+}
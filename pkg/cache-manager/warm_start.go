@@ -0,0 +1,184 @@
+package cache_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWarmupMaxKeys     = 10000
+	defaultWarmupConcurrency = 10
+	defaultWarmupTimeout     = time.Minute
+	// autoWarmLogEvery controls how often AutoWarmOnStart progress is logged.
+	autoWarmLogEvery = 1000
+)
+
+// LimitedKeyLister is implemented by raw caches that can stop listing keys early, such
+// as RedisCache.
+type LimitedKeyLister interface {
+	ListKeys(ctx context.Context, pattern string, max int) ([]string, error)
+}
+
+// autoWarm is the state of the AutoWarmOnStart run.
+type autoWarm struct {
+	done chan struct{}
+	err  error // set before done is closed
+}
+
+// startAutoWarm validates the AutoWarmOnStart settings and starts the run in the
+// background. A cache that cannot be warmed finishes immediately with the reason as the
+// WaitForWarmup error.
+func (m *MultiLevelCache) startAutoWarm(cfg MultiLevelConfig) {
+	maxKeys := cfg.WarmupMaxKeys
+	if maxKeys <= 0 {
+		maxKeys = defaultWarmupMaxKeys
+	}
+	concurrency := cfg.WarmupConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultWarmupConcurrency
+	}
+	timeout := cfg.WarmupTimeout
+	if timeout <= 0 {
+		timeout = defaultWarmupTimeout
+	}
+
+	m.autoWarm = &autoWarm{done: make(chan struct{})}
+	m.backgroundWork.Add(1)
+	go func() {
+		defer m.backgroundWork.Done()
+		defer close(m.autoWarm.done)
+		ctx, cancel := context.WithTimeout(m.background, timeout)
+		defer cancel()
+		m.autoWarm.err = m.warmL1FromL2(ctx, maxKeys, concurrency)
+		if m.autoWarm.err != nil {
+			m.log.logger.Warn("cache auto warmup incomplete", "error", m.autoWarm.err)
+		}
+	}()
+}
+
+// WaitForWarmup blocks until the AutoWarmOnStart run has finished or ctx is done. It
+// returns the run's error, such as a failed key listing, the WarmupTimeout or a Close
+// during the run, and nil
+// right away when AutoWarmOnStart is off. Failures of individual keys are counted in
+// WarmupStatus instead.
+func (m *MultiLevelCache) WaitForWarmup(ctx context.Context) error {
+	if m == nil {
+		return &CacheError{Op: "warm", Cause: ErrNotInitialized}
+	}
+	if m.autoWarm == nil {
+		return nil
+	}
+	select {
+	case <-m.autoWarm.done:
+		return m.autoWarm.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// warmL1FromL2 copies up to maxKeys of this instance's L2 entries into L1 with the
+// warmup TTL, concurrency at a time. Payloads are copied as stored, so it requires the
+// same serializer in both levels.
+func (m *MultiLevelCache) warmL1FromL2(ctx context.Context, maxKeys, concurrency int) error {
+	if m.l1 == nil || m.l2 == nil || m.mode != ModeBothLevels {
+		return &CacheError{Op: "warm", Cause: fmt.Errorf("%w: auto warmup needs ModeBothLevels", ErrModeMismatch)}
+	}
+	if m.splitFormats {
+		return &CacheError{Op: "warm", Cause: errors.New("auto warmup cannot copy entries between different L1 and L2 serializers")}
+	}
+
+	keys, err := m.listL2Keys(ctx, maxKeys)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	m.warmup.start(int64(len(keys)), m.clock.Now())
+	defer m.warmup.finish()
+	m.log.logger.Info("cache auto warmup started", "keys", len(keys))
+
+	var (
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, concurrency)
+		warmed atomic.Int64
+	)
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := m.warmKeyFromL2(ctx, key); err != nil {
+				m.warmup.failed()
+				m.log.Debug(ctx, "cache auto warmup key failed", "key", key, "error", err)
+				return
+			}
+			m.warmup.loaded()
+			if n := warmed.Add(1); n%autoWarmLogEvery == 0 {
+				m.log.logger.Info("cache auto warmup progress", "warmed", n, "total", len(keys))
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	status := m.WarmupStatus()
+	m.log.logger.Info("cache auto warmup finished", "loaded", status.Loaded, "failed", status.Failed, "duration", time.Since(start))
+	if err := ctx.Err(); err != nil {
+		return &CacheError{Op: "warm", Cause: err}
+	}
+	return nil
+}
+
+// listL2Keys returns up to max stored keys of this instance from L2, without chunk keys.
+func (m *MultiLevelCache) listL2Keys(ctx context.Context, max int) ([]string, error) {
+	pattern := m.storePattern("*")
+	var keys []string
+	var err error
+	switch lister := m.l2.(type) {
+	case LimitedKeyLister:
+		keys, err = lister.ListKeys(ctx, pattern, max)
+	case KeyLister:
+		keys, err = lister.Keys(ctx, pattern)
+	default:
+		return nil, &CacheError{Op: "warm", Level: LevelL2, Cause: errors.New("L2 cannot list keys")}
+	}
+	if err != nil {
+		return nil, wrapError("warm", LevelL2, "", err)
+	}
+
+	out := keys[:0]
+	for _, key := range keys {
+		if !strings.Contains(key, ":__chunk:") {
+			out = append(out, key)
+		}
+	}
+	if len(out) > max {
+		out = out[:max]
+	}
+	return out, nil
+}
+
+// warmKeyFromL2 copies one stored key from L2 into L1. Keys that expired since the
+// listing or exceed L1MaxValueBytes are skipped without an error.
+func (m *MultiLevelCache) warmKeyFromL2(ctx context.Context, key string) error {
+	data, ok, err := m.readL2(ctx, key)
+	if err != nil {
+		return wrapError("warm", LevelL2, key, err)
+	}
+	if !ok || m.skipL1Oversize(key, len(data), CacheOptions{}) {
+		return nil
+	}
+	if err := m.l1.Set(ctx, key, data, m.warmupTTL); err != nil {
+		return wrapError("warm", LevelL1, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/require"
+)
+
+// newAutoWarmRedis returns a RedisCache holding n users written by an earlier instance
+// with the given config.
+func newAutoWarmRedis(t *testing.T, n int, cfg MultiLevelConfig) *RedisCache {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:                     mr.Addr(),
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+	l2, err := NewRedisCache(client)
+	require.NoError(t, err)
+
+	cfg.Mode = ModeL2Only
+	writer, err := NewMultiLevelCache(nil, l2, JSONSerializer{}, cfg)
+	require.NoError(t, err)
+	for i := 1; i <= n; i++ {
+		require.NoError(t, writer.Set(context.Background(), UserCacheKey(i), loadedUser{ID: i, Name: fmt.Sprintf("user-%d", i)}, CacheOptions{}))
+	}
+	return l2
+}
+
+func TestAutoWarmOnStartCopiesL2IntoL1(t *testing.T) {
+	t.Parallel()
+
+	cfg := MultiLevelConfig{InstanceName: "users", ContentHashes: true, L2Compression: L2CompressionConfig{Algorithm: CompressionGzip}}
+	l2 := newAutoWarmRedis(t, 100, cfg)
+
+	l1 := newMemoryRawCache()
+	cfg.Mode = ModeBothLevels
+	cfg.WarmupTTL = 30 * time.Second
+	cfg.AutoWarmOnStart = true
+	cfg.WarmupConcurrency = 4
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, ml.WaitForWarmup(ctx))
+	require.True(t, ml.IsWarmupComplete())
+	require.Equal(t, int64(100), ml.WarmupStatus().Loaded)
+
+	for i := 1; i <= 100; i++ {
+		var got loadedUser
+		res, err := ml.Get(ctx, UserCacheKey(i), &got, CacheOptions{})
+		require.NoError(t, err)
+		require.Equal(t, CacheGetResult{Found: true, Level: CacheLevelL1}, res)
+		require.Equal(t, fmt.Sprintf("user-%d", i), got.Name)
+		require.Equal(t, 30*time.Second, l1.ttl[ml.storeKey(UserCacheKey(i))])
+	}
+}
+
+func TestAutoWarmOnStartRespectsMaxKeys(t *testing.T) {
+	t.Parallel()
+
+	l2 := newAutoWarmRedis(t, 100, MultiLevelConfig{})
+	l1 := newMemoryRawCache()
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:            ModeBothLevels,
+		AutoWarmOnStart: true,
+		WarmupMaxKeys:   25,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ml.WaitForWarmup(context.Background()))
+	require.Equal(t, int64(25), ml.WarmupStatus().Loaded)
+	require.Len(t, l1.data, 25)
+}
+
+func TestWaitForWarmup(t *testing.T) {
+	t.Parallel()
+
+	// Without AutoWarmOnStart there is nothing to wait for.
+	ml, l1, l2 := newTestMultiLevelCache(t, MultiLevelConfig{})
+	require.NoError(t, ml.WaitForWarmup(context.Background()))
+
+	// Split serializers cannot be copied as stored, so the run ends with an error.
+	split, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		Mode:            ModeBothLevels,
+		L1Serializer:    GobSerializer{},
+		AutoWarmOnStart: true,
+	})
+	require.NoError(t, err)
+	require.Error(t, split.WaitForWarmup(context.Background()))
+}
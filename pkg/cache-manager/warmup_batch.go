@@ -0,0 +1,171 @@
+package cache_manager
+
+import (
+	"context"
+	"time"
+)
+
+// MultiTTLInspector is implemented by raw caches that can report the remaining TTLs of
+// several keys in one round trip (a Redis pipeline). A key without an expiry reports 0
+// and a missing key a negative duration.
+type MultiTTLInspector interface {
+	MTTL(ctx context.Context, keys []string) ([]time.Duration, error)
+}
+
+var _ MultiTTLInspector = (*RedisCache)(nil)
+
+// Warmup batching defaults.
+const (
+	defaultWarmupBatchEntries = 100
+	defaultWarmupBatchPending = 1000
+	// warmupFlushTimeout bounds the final batch written by Close.
+	warmupFlushTimeout = 5 * time.Second
+)
+
+// WarmupBatchConfig batches the L1 warmups of L2 hits; see MultiLevelConfig.WarmupBatch.
+type WarmupBatchConfig struct {
+	// Window is how long the first queued warmup waits for others before the batch is
+	// written, e.g. 5ms. 0 disables batching.
+	Window time.Duration
+	// MaxEntries writes the batch as soon as it holds this many warmups. Default 100.
+	MaxEntries int
+	// MaxPending bounds the warmups waiting to be written; while the queue is full,
+	// L2 hits are not warmed. Default 1000.
+	MaxPending int
+}
+
+// pendingWarmup is an L1 warmup waiting for its batch.
+type pendingWarmup struct {
+	key  string
+	data []byte
+}
+
+// warmupBatcher queues warmups for the goroutine started by startWarmupBatcher.
+type warmupBatcher struct {
+	queue      chan pendingWarmup
+	window     time.Duration
+	maxEntries int
+}
+
+// startWarmupBatcher starts the goroutine that writes queued warmups, which Close
+// stops after writing what is still queued.
+func (m *MultiLevelCache) startWarmupBatcher(cfg WarmupBatchConfig) {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaultWarmupBatchEntries
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = defaultWarmupBatchPending
+	}
+	b := &warmupBatcher{
+		queue:      make(chan pendingWarmup, cfg.MaxPending),
+		window:     cfg.Window,
+		maxEntries: cfg.MaxEntries,
+	}
+	m.warmups = b
+
+	m.backgroundWork.Add(1)
+	go func() {
+		defer m.backgroundWork.Done()
+		m.runWarmupBatcher(b)
+	}()
+}
+
+// queueWarmup hands an L1 warmup to the batcher and reports false when the queue is full.
+func (m *MultiLevelCache) queueWarmup(key string, data []byte) bool {
+	select {
+	case m.warmups.queue <- pendingWarmup{key: key, data: data}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *MultiLevelCache) runWarmupBatcher(b *warmupBatcher) {
+	var batch []pendingWarmup
+	var timer *time.Timer
+	var expired <-chan time.Time
+	for {
+		select {
+		case w := <-b.queue:
+			batch = append(batch, w)
+			if len(batch) == 1 {
+				timer = time.NewTimer(b.window)
+				expired = timer.C
+			}
+			if len(batch) < b.maxEntries {
+				continue
+			}
+			timer.Stop()
+		case <-expired:
+		case <-m.background.Done():
+			ctx, cancel := context.WithTimeout(context.Background(), warmupFlushTimeout)
+			defer cancel()
+			for {
+				select {
+				case w := <-b.queue:
+					batch = append(batch, w)
+				default:
+					for len(batch) > 0 {
+						n := min(len(batch), b.maxEntries)
+						m.flushWarmups(ctx, batch[:n])
+						batch = batch[n:]
+					}
+					return
+				}
+			}
+		}
+		expired = nil
+		m.flushWarmups(m.background, batch)
+		batch = nil
+	}
+}
+
+// flushWarmups writes a batch of warmups to L1. When L2 is a MultiTTLInspector, the
+// remaining L2 TTLs of the whole batch are looked up in one round trip: each entry's
+// TTL is capped by its L2 TTL, and keys gone from L2 meanwhile are not warmed. Keys L1
+// already holds are left alone, as they were written after the L2 read.
+func (m *MultiLevelCache) flushWarmups(ctx context.Context, batch []pendingWarmup) {
+	ttls := make([]time.Duration, len(batch))
+	for i := range ttls {
+		ttls[i] = m.warmupTTL
+	}
+	if inspector, ok := m.l2.(MultiTTLInspector); ok && m.l2Available() {
+		keys := make([]string, len(batch))
+		for i, w := range batch {
+			keys[i] = w.key
+		}
+		remaining, err := inspector.MTTL(ctx, keys)
+		if err != nil {
+			m.log.Debug(ctx, "cache warmup batch l2 ttl lookup failed, using warmup ttl", "entries", len(batch), "error", err)
+		}
+		for i := range remaining {
+			switch r := remaining[i]; {
+			case r < 0:
+				ttls[i] = -1
+			case r > 0 && (ttls[i] <= 0 || r < ttls[i]):
+				ttls[i] = r
+			}
+		}
+	}
+
+	warmed := 0
+	for i, w := range batch {
+		if ttls[i] < 0 {
+			m.log.Debug(ctx, "cache warmup skipped, key gone from l2", "key", w.key)
+			continue
+		}
+		if _, ok, err := m.l1.Get(ctx, w.key); err == nil && ok {
+			continue
+		}
+		if m.skipL1Budget(ctx, w.key, len(w.data)) {
+			continue
+		}
+		if err := m.l1.Set(ctx, w.key, w.data, ttls[i]); err != nil {
+			m.log.Debug(ctx, "cache warmup batch l1 write failed", "key", w.key, "error", err)
+			continue
+		}
+		m.l1Budget.record(w.key, len(w.data))
+		warmed++
+	}
+	m.log.Debug(ctx, "cache warmup batch written", "entries", len(batch), "warmed", warmed)
+}
@@ -0,0 +1,163 @@
+package cache_manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ttlRecordingCache is a memoryRawCache that implements MultiTTLInspector from its
+// stored TTLs and records the keys of every MTTL call.
+type ttlRecordingCache struct {
+	*memoryRawCache
+	callsMu sync.Mutex
+	calls   [][]string
+}
+
+func (c *ttlRecordingCache) MTTL(_ context.Context, keys []string) ([]time.Duration, error) {
+	c.callsMu.Lock()
+	c.calls = append(c.calls, append([]string{}, keys...))
+	c.callsMu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]time.Duration, len(keys))
+	for i, key := range keys {
+		if _, ok := c.data[key]; !ok {
+			out[i] = -1
+			continue
+		}
+		out[i] = c.ttl[key]
+	}
+	return out, nil
+}
+
+func (c *ttlRecordingCache) pipelines() [][]string {
+	c.callsMu.Lock()
+	defer c.callsMu.Unlock()
+	return append([][]string{}, c.calls...)
+}
+
+func newWarmupBatchTestCache(t *testing.T, cfg WarmupBatchConfig) (*MultiLevelCache, *memoryRawCache, *ttlRecordingCache) {
+	t.Helper()
+	l1 := newMemoryRawCache()
+	l2 := &ttlRecordingCache{memoryRawCache: newMemoryRawCache()}
+	ml, err := NewMultiLevelCache(l1, l2, JSONSerializer{}, MultiLevelConfig{
+		WarmupTTL:   time.Minute,
+		WarmupBatch: cfg,
+	})
+	require.NoError(t, err)
+	return ml, l1, l2
+}
+
+func TestWarmupBatchLooksUpTTLsInOnePipelinePerBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newWarmupBatchTestCache(t, WarmupBatchConfig{Window: time.Hour, MaxEntries: 5})
+	for i := range 13 {
+		require.NoError(t, ml.Set(ctx, fmt.Sprintf("k%d", i), i, CacheOptions{TargetL1: BoolPtr(false), L2TTL: 10 * time.Second}))
+	}
+
+	var got int
+	for i := range 10 {
+		res, err := ml.Get(ctx, fmt.Sprintf("k%d", i), &got, CacheOptions{})
+		require.NoError(t, err)
+		require.True(t, res.Found)
+	}
+	require.Eventually(t, func() bool { return len(l2.pipelines()) == 2 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return l1.has("k9") }, time.Second, time.Millisecond)
+	require.Equal(t, []string{"k0", "k1", "k2", "k3", "k4"}, l2.pipelines()[0])
+
+	for i := 10; i < 13; i++ {
+		_, err := ml.Get(ctx, fmt.Sprintf("k%d", i), &got, CacheOptions{})
+		require.NoError(t, err)
+	}
+	require.False(t, l1.has("k10"), "a partial batch waits for the window")
+
+	require.NoError(t, ml.Close(ctx))
+	require.Len(t, l2.pipelines(), 3, "Close writes the pending batch")
+	for i := range 13 {
+		require.True(t, l1.has(fmt.Sprintf("k%d", i)), "k%d", i)
+	}
+	require.Equal(t, 10*time.Second, l1.ttl["k0"], "the warmup TTL is capped by the L2 TTL")
+}
+
+func TestWarmupBatchFlushesAfterWindow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newWarmupBatchTestCache(t, WarmupBatchConfig{Window: 5 * time.Millisecond})
+	t.Cleanup(func() { _ = ml.Close(ctx) })
+	require.NoError(t, ml.Set(ctx, "k", "v", CacheOptions{TargetL1: BoolPtr(false), L2TTL: time.Hour}))
+
+	traced := WithTrace(ctx)
+	var got string
+	_, err := ml.Get(traced, "k", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.Equal(t, TraceQueued, TraceFromContext(traced)[3].Result)
+
+	require.Eventually(t, func() bool { return l1.has("k") }, time.Second, time.Millisecond)
+	require.Len(t, l2.pipelines(), 1)
+	require.Equal(t, time.Minute, l1.ttl["k"])
+}
+
+func TestWarmupBatchSkipsKeysGoneFromL2(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, l2 := newWarmupBatchTestCache(t, WarmupBatchConfig{Window: time.Hour})
+	require.NoError(t, ml.Set(ctx, "gone", "v", CacheOptions{TargetL1: BoolPtr(false)}))
+	require.NoError(t, ml.Set(ctx, "kept", "v", CacheOptions{TargetL1: BoolPtr(false)}))
+
+	var got string
+	_, err := ml.Get(ctx, "gone", &got, CacheOptions{})
+	require.NoError(t, err)
+	_, err = ml.Get(ctx, "kept", &got, CacheOptions{})
+	require.NoError(t, err)
+	require.NoError(t, l2.Delete(ctx, "gone"))
+
+	require.NoError(t, ml.Close(ctx))
+	require.False(t, l1.has("gone"))
+	require.True(t, l1.has("kept"))
+}
+
+func TestWarmupBatchQueueIsBounded(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ml, l1, _ := newWarmupBatchTestCache(t, WarmupBatchConfig{})
+	// A batcher without its goroutine never drains the queue.
+	ml.warmups = &warmupBatcher{queue: make(chan pendingWarmup, 2)}
+
+	var results []string
+	for i := range 3 {
+		key := fmt.Sprintf("k%d", i)
+		require.NoError(t, ml.Set(ctx, key, i, CacheOptions{TargetL1: BoolPtr(false)}))
+		traced := WithTrace(ctx)
+		var got int
+		_, err := ml.Get(traced, key, &got, CacheOptions{})
+		require.NoError(t, err)
+		results = append(results, TraceFromContext(traced)[3].Result)
+	}
+	require.Equal(t, []string{TraceQueued, TraceQueued, TraceSkipped}, results)
+	require.Len(t, ml.warmups.queue, 2)
+	require.False(t, l1.has("k2"))
+}
+
+func TestRedisCacheMTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l2, _ := newRenameTestRedis(t)
+	require.NoError(t, l2.Set(ctx, "expiring", []byte("v"), time.Minute))
+	require.NoError(t, l2.Set(ctx, "persistent", []byte("v"), 0))
+
+	ttls, err := l2.MTTL(ctx, []string{"expiring", "persistent", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, []time.Duration{time.Minute, 0, -1}, ttls)
+}